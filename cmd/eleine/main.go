@@ -15,6 +15,14 @@ import (
 	"github.com/angelomds42/EleineBot/internal/database"
 	"github.com/angelomds42/EleineBot/internal/modules"
 	"github.com/angelomds42/EleineBot/internal/modules/afk"
+	"github.com/angelomds42/EleineBot/internal/modules/antiraid"
+	"github.com/angelomds42/EleineBot/internal/modules/blocklist"
+	"github.com/angelomds42/EleineBot/internal/modules/captcha"
+	"github.com/angelomds42/EleineBot/internal/modules/cleanup"
+	"github.com/angelomds42/EleineBot/internal/modules/edits"
+	"github.com/angelomds42/EleineBot/internal/modules/filters"
+	"github.com/angelomds42/EleineBot/internal/modules/locks"
+	"github.com/angelomds42/EleineBot/internal/modules/sudoers"
 	"github.com/angelomds42/EleineBot/internal/utils"
 )
 
@@ -36,11 +44,22 @@ func main() {
 
 	opts := []bot.Option{
 		bot.WithMiddlewares(
-			database.SaveUsers,
-			afk.CheckAFKMiddleware,
+			edits.EditedMessageMiddleware,
+			utils.ThreadMiddleware,
+			edits.SkipDispatched(database.SaveUsers),
+			edits.SkipDispatched(cleanup.CleanServiceMiddleware),
+			edits.SkipDispatched(sudoers.CheckGbanMiddleware),
+			edits.SkipDispatched(antiraid.CheckAntiraidMiddleware),
+			edits.SkipDispatched(captcha.CheckCaptchaMiddleware),
+			locks.CheckLocksMiddleware,
+			blocklist.CheckBlocklistMiddleware,
+			edits.SkipDispatched(afk.CheckAFKMiddleware),
+			edits.SkipDispatched(filters.CheckFiltersMiddleware),
 			utils.CheckDisabledMiddleware,
 			checkUsername,
+			edits.SkipDispatched(cleanup.CleanCommandsMiddleware),
 		),
+		bot.WithDefaultHandler(utils.DefaultHandler),
 	}
 
 	if config.BotAPIURL != "" {
@@ -55,11 +55,227 @@ func CreateTables() error {
 		CREATE TABLE IF NOT EXISTS commandsDisabled (
 			chat_id INTEGER,
 			command TEXT NOT NULL,
+			admins_only BOOLEAN DEFAULT 0,
 			PRIMARY KEY (chat_id, command)
 		);
+		CREATE TABLE IF NOT EXISTS disabledSettings (
+			chat_id INTEGER PRIMARY KEY,
+			delete_commands BOOLEAN DEFAULT 0
+		);
+		CREATE TABLE IF NOT EXISTS locks (
+			chat_id INTEGER,
+			type TEXT NOT NULL,
+			PRIMARY KEY (chat_id, type)
+		);
+		CREATE TABLE IF NOT EXISTS blocklist (
+			chat_id INTEGER,
+			pattern TEXT NOT NULL,
+			kind TEXT NOT NULL DEFAULT 'word',
+			action TEXT,
+			duration TEXT,
+			PRIMARY KEY (chat_id, pattern)
+		);
+		CREATE TABLE IF NOT EXISTS blocklistSettings (
+			chat_id INTEGER PRIMARY KEY,
+			action TEXT NOT NULL DEFAULT 'delete',
+			duration TEXT
+		);
+		CREATE TABLE IF NOT EXISTS greetings (
+			chat_id INTEGER,
+			type TEXT NOT NULL,
+			enabled BOOLEAN DEFAULT 0,
+			text TEXT,
+			media_type TEXT,
+			file_id TEXT,
+			clean BOOLEAN DEFAULT 0,
+			last_message_id INTEGER,
+			PRIMARY KEY (chat_id, type)
+		);
+		CREATE TABLE IF NOT EXISTS captchaSettings (
+			chat_id INTEGER PRIMARY KEY,
+			enabled BOOLEAN DEFAULT 0,
+			mode TEXT NOT NULL DEFAULT 'button',
+			timeout INTEGER NOT NULL DEFAULT 300
+		);
+		CREATE TABLE IF NOT EXISTS captchaPending (
+			chat_id INTEGER,
+			user_id INTEGER,
+			message_id INTEGER,
+			answer TEXT NOT NULL,
+			attempts INTEGER DEFAULT 0,
+			expires_at INTEGER NOT NULL,
+			PRIMARY KEY (chat_id, user_id)
+		);
+		CREATE TABLE IF NOT EXISTS notes (
+			chat_id INTEGER,
+			name TEXT NOT NULL,
+			text TEXT,
+			media_type TEXT,
+			file_id TEXT,
+			PRIMARY KEY (chat_id, name)
+		);
+		CREATE TABLE IF NOT EXISTS filters (
+			chat_id INTEGER,
+			trigger TEXT NOT NULL,
+			mode TEXT NOT NULL DEFAULT 'contains',
+			text TEXT,
+			media_type TEXT,
+			file_id TEXT,
+			PRIMARY KEY (chat_id, trigger)
+		);
+		CREATE TABLE IF NOT EXISTS rules (
+			chat_id INTEGER PRIMARY KEY,
+			text TEXT NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS reportSettings (
+			chat_id INTEGER PRIMARY KEY,
+			enabled BOOLEAN DEFAULT 1
+		);
+		CREATE TABLE IF NOT EXISTS reportSubscribers (
+			user_id INTEGER PRIMARY KEY
+		);
+		CREATE TABLE IF NOT EXISTS logChannels (
+			chat_id INTEGER PRIMARY KEY,
+			channel_id INTEGER NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS sudoers (
+			user_id INTEGER PRIMARY KEY
+		);
+		CREATE TABLE IF NOT EXISTS gbans (
+			user_id INTEGER PRIMARY KEY,
+			reason TEXT,
+			banned_by INTEGER,
+			date INTEGER NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS gbanSettings (
+			chat_id INTEGER PRIMARY KEY,
+			enabled BOOLEAN DEFAULT 0
+		);
+		CREATE TABLE IF NOT EXISTS gbanBans (
+			chat_id INTEGER,
+			user_id INTEGER,
+			PRIMARY KEY (chat_id, user_id)
+		);
+		CREATE TABLE IF NOT EXISTS approvals (
+			chat_id INTEGER,
+			user_id INTEGER,
+			PRIMARY KEY (chat_id, user_id)
+		);
+		CREATE TABLE IF NOT EXISTS antiraidSettings (
+			chat_id INTEGER PRIMARY KEY,
+			action TEXT DEFAULT 'kick',
+			duration INTEGER DEFAULT 21600,
+			threshold INTEGER DEFAULT 0,
+			until INTEGER DEFAULT 0
+		);
+		CREATE TABLE IF NOT EXISTS nightMode (
+			chat_id INTEGER PRIMARY KEY,
+			enabled BOOLEAN DEFAULT 0,
+			start_minute INTEGER DEFAULT 1380,
+			end_minute INTEGER DEFAULT 420,
+			timezone TEXT DEFAULT 'UTC',
+			active BOOLEAN DEFAULT 0,
+			permissions TEXT
+		);
+		CREATE TABLE IF NOT EXISTS connections (
+			user_id INTEGER PRIMARY KEY,
+			chat_id INTEGER NOT NULL,
+			config_message_id INTEGER DEFAULT 0
+		);
+		CREATE TABLE IF NOT EXISTS connectionHistory (
+			user_id INTEGER,
+			chat_id INTEGER,
+			title TEXT,
+			last_used INTEGER NOT NULL,
+			PRIMARY KEY (user_id, chat_id)
+		);
+		CREATE TABLE IF NOT EXISTS joinRequestSettings (
+			chat_id INTEGER PRIMARY KEY,
+			enabled BOOLEAN DEFAULT 0,
+			approve_username BOOLEAN DEFAULT 0,
+			decline_gbanned BOOLEAN DEFAULT 1,
+			captcha BOOLEAN DEFAULT 0
+		);
+		CREATE TABLE IF NOT EXISTS joinRequests (
+			chat_id INTEGER,
+			user_id INTEGER,
+			first_name TEXT,
+			review_chat_id INTEGER NOT NULL,
+			message_id INTEGER NOT NULL,
+			answer TEXT,
+			attempts INTEGER DEFAULT 0,
+			expires_at INTEGER DEFAULT 0,
+			PRIMARY KEY (chat_id, user_id)
+		);
+		CREATE TABLE IF NOT EXISTS cleanupSettings (
+			chat_id INTEGER PRIMARY KEY,
+			clean_commands BOOLEAN DEFAULT 0,
+			reply_delay INTEGER DEFAULT 0
+		);
+		CREATE TABLE IF NOT EXISTS cleanService (
+			chat_id INTEGER,
+			type TEXT NOT NULL,
+			PRIMARY KEY (chat_id, type)
+		);
+		CREATE TABLE IF NOT EXISTS scheduledDeletions (
+			chat_id INTEGER,
+			message_id INTEGER,
+			delete_at INTEGER NOT NULL,
+			PRIMARY KEY (chat_id, message_id)
+		);
+		CREATE TABLE IF NOT EXISTS topicSettings (
+			chat_id INTEGER,
+			thread_id INTEGER,
+			mediasAuto BOOLEAN,
+			PRIMARY KEY (chat_id, thread_id)
+		);
+		CREATE TABLE IF NOT EXISTS topicCommandsDisabled (
+			chat_id INTEGER,
+			thread_id INTEGER,
+			command TEXT NOT NULL,
+			admins_only BOOLEAN DEFAULT 0,
+			PRIMARY KEY (chat_id, thread_id, command)
+		);
+		CREATE TABLE IF NOT EXISTS editSettings (
+			chat_id INTEGER PRIMARY KEY,
+			enabled BOOLEAN DEFAULT 0
+		);
+		CREATE TABLE IF NOT EXISTS editTriggers (
+			chat_id INTEGER,
+			message_id INTEGER,
+			commands TEXT NOT NULL DEFAULT '',
+			links TEXT NOT NULL DEFAULT '',
+			date INTEGER NOT NULL,
+			PRIMARY KEY (chat_id, message_id)
+		);
 	`
-	_, err := DB.Exec(query)
-	return err
+	if _, err := DB.Exec(query); err != nil {
+		return err
+	}
+	return addMissingColumns()
+}
+
+// addedColumns lists the columns added to tables after their creation, which
+// CREATE TABLE IF NOT EXISTS doesn't add to existing databases.
+var addedColumns = []struct{ table, column, definition string }{
+	{"commandsDisabled", "admins_only", "BOOLEAN DEFAULT 0"},
+}
+
+func addMissingColumns() error {
+	for _, c := range addedColumns {
+		var exists bool
+		err := DB.QueryRow("SELECT EXISTS(SELECT 1 FROM pragma_table_info(?) WHERE name = ?);", c.table, c.column).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := DB.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;", c.table, c.column, c.definition)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func Close() {
@@ -90,11 +306,7 @@ func SaveUsers(next bot.HandlerFunc) bot.HandlerFunc {
 			}
 		}
 
-		if message.SenderChat != nil {
-			return
-		}
-
-		if message.From.ID != message.Chat.ID {
+		if message.Chat.Type != models.ChatTypePrivate {
 			query := "INSERT OR IGNORE INTO groups (id) VALUES (?);"
 			_, err := DB.Exec(query, message.Chat.ID)
 			if err != nil {
@@ -104,6 +316,13 @@ func SaveUsers(next bot.HandlerFunc) bot.HandlerFunc {
 			}
 		}
 
+		// Messages sent on behalf of a chat, such as those of anonymous
+		// admins, come from a placeholder bot that isn't worth saving.
+		if message.SenderChat != nil {
+			next(ctx, b, update)
+			return
+		}
+
 		query := `
 		INSERT INTO users (id, language, username)
     	VALUES (?, ?, ?)
@@ -120,19 +120,59 @@ moderation-help =
     <b>/ban [ID|reply] (duration) (revoke):</b> Bans a user from the group.
     <b>/mute [ID|reply] (tempo):</b> Mute a user from the group.
     <b>/delete [reply]:</b> Delete a message.
+    <b>/purge [reply] (count):</b> Deletes every message from the replied one up to the command, or the last <i>count</i> messages.
+    <b>/spurge [reply] (count):</b> Same as /purge, but without reporting.
     <b> - ID or reply:</b> Specify the user's ID or reply to their message.
     <b> - duration:</b> (optional) Set how long the user will be banned (e.g., 1h, 2d).
     <b> - revoke:</b> (optional) If set, removes all messages from the user.
 
+    <b>— Pins:</b>
+    <b>/pin [reply] (loud|silent):</b> Pins the replied message. Use <code>loud</code> to notify all members.
+    <b>/unpin [reply]:</b> Unpins the replied message, or the latest pin.
+    <b>/unpinall:</b> Unpins all messages after confirmation.
+    <b>/pinned:</b> Shows a link to the current pinned message.
+
+    <b>— Admins:</b>
+    <b>/promote [ID|reply] (title):</b> Promotes a user to admin with basic rights.
+    <b>/fullpromote [ID|reply] (title):</b> Promotes a user with every right you hold.
+    <b>/demote [ID|reply]:</b> Removes a user's admin rights.
+    <b>/title [ID|reply] (text):</b> Sets an admin's custom title.
+    <i>You can only grant rights you hold yourself.</i>
+
+    <b>— Approvals:</b>
+    <b>/approve [ID|reply]:</b> Exempts a user from locks, the blocklist and the captcha.
+    <b>/unapprove [ID|reply]:</b> Removes a user's approval.
+    <b>/approved:</b> Lists the approved users.
+    <b>/unapproveall:</b> Removes every approval. Only the group owner can use it.
+
     <b>— Configuration:</b>
-    <b>/disable (command):</b> Disables the specified command in the group.
-    <b>/enable (command):</b> Reactivates a command that was previously disabled.
-    <b>/disableable:</b> Lists all commands that can be disabled.
-    <b>/disabled:</b> Shows all commands that are currently disabled.
+    <b>/disable (commands):</b> Disables the given commands in the group, along with their aliases. Use <code>/disable module (modules)</code> to disable whole modules, or <code>/disable all</code> for every command. Start with <code>topic</code> inside a forum topic to disable them there only.
+    <b>/enable (commands):</b> Reactivates commands that were previously disabled. Accepts <code>module</code>, <code>all</code> and <code>topic</code> too.
+    <b>/restrict (commands):</b> Makes commands usable only by admins. Accepts <code>module</code>, <code>all</code> and <code>topic</code> too, and /enable lifts it.
+    <b>/disableable:</b> Lists all commands that can be disabled, by module, with their state in the group.
+    <b>/disabled:</b> Shows all commands that are currently disabled or restricted to admins, and in a forum topic, those disabled there only.
+    <b>/disabledel (on/off):</b> Deletes messages that use a disabled command.
     <b>/config:</b> Opens a menu with group configuration options.
+    <b>/export:</b> Sends a file with the group settings, notes, filters and other moderation data.
+    <b>/import:</b> Replying to a file made by /export, replaces the group settings with the ones in it.
+
+    <b>— Log channel:</b>
+    <b>/setlog [channel|reply]:</b> Posts every moderation event to a channel. Give its ID or @username, or reply to a message forwarded from it.
+    <b>/unsetlog:</b> Stops sending events to the log channel.
+    <b>/logchannel:</b> Shows the current log channel.
+    <b>/gbanstat (on/off):</b> Bans users from the bot's global ban list as soon as they join or speak.
+    <i>Restrictions accept a reason after the duration, e.g. <code>/ban 1d spam</code>.</i>
+
+    <b>— Connections</b>
+    <b>/connect (chat ID or @username):</b> Connects to a group you administer, so its settings can be managed from my private chat.
+    <b>/disconnect:</b> Disconnects from the current group.
+    <i>While connected, the settings commands sent in private, such as /config, /disable, /export or /edits, act on the connected group. /reports keeps toggling your own report notifications, and /unapproveall only works in the group itself.</i>
 config-message =
     <b>Settings —</b> Here are my settings for this group.
     To know more, <b>click on the buttons below.</b>
+config-message-connected =
+    <b>Settings —</b> Here are my settings for <b>{ $chatName }</b>.
+    To know more, <b>click on the buttons below.</b>
 config-medias =
     <b>Medias module settings:</b>
     To know more about the <b><i>medias</i></b> module, use /help in my private chat.
@@ -146,7 +186,7 @@ command-already-enabled = The command <code>{ $command }</code> is already enabl
 enable-commands-usage =
     Please specify the command you want to enable. To see which commands are currently disabled, use /disabled.
 
-    <b>Usage:</b> <code>/enable (command)</code>
+    <b>Usage:</b> <code>/enable (commands)</code>, <code>/enable module (modules)</code> or <code>/enable all</code>
 no-disabled-commands = There are no disabled commands <b>in this group.</b>
 disabled-commands = <b>Disabled commands:</b>
 disableables-commands = <b>Disableable commands:</b>
@@ -155,7 +195,42 @@ command-disabled = The command <code>{ $command }</code> has been successfully d
 disable-commands-usage =
     Please specify the command you want to disable. To view the list of disableable commands, use /disableable.
 
-    <b>Usage:</b> <code>/disable (command)</code>
+    <b>Usage:</b> <code>/disable (commands)</code>, <code>/disable module (modules)</code> or <code>/disable all</code>
+command-restricted = The command <code>{ $command }</code> can now be used <b>only by admins.</b>
+command-already-restricted = The command <code>{ $command }</code> is already restricted to admins.
+commands-restricted = The commands { $commands } can now be used <b>only by admins.</b>
+commands-already-restricted = The commands { $commands } are already restricted to admins.
+restrict-commands-usage =
+    Please specify the command you want to restrict to admins. To view the list of disableable commands, use /disableable.
+
+    <b>Usage:</b> <code>/restrict (commands)</code>, <code>/restrict module (modules)</code> or <code>/restrict all</code>
+command-states-legend = <i>✅ Enabled · 🛡 Admins only · 🚫 Disabled</i>
+topic-disabled-commands = <b>Disabled in this topic only:</b>
+topic-scope-notice = <i>This only applies to this topic.</i>
+topic-only = Use this command inside a forum topic.
+commands-disabled = The commands { $commands } have been successfully disabled.
+commands-already-disabled = The commands { $commands } are already disabled.
+commands-enabled = The commands { $commands } have been successfully enabled.
+commands-already-enabled = The commands { $commands } are already enabled.
+enable-all-success = { $count ->
+    [one] The only disabled command has been enabled.
+   *[other] All { $count } disabled commands have been enabled.
+}
+disableables-hint = <i>Commands in parentheses are aliases, disabled along with the command. Use <code>/disable module (name)</code> to disable a whole module.</i>
+disabled-delete-notice = <i>Messages using these commands are deleted.</i>
+module-not-found = There's no module named <code>{ $module }</code> with disableable commands. See /disableable.
+disabledel-usage =
+    Please specify <code>on</code> or <code>off</code>.
+
+    <b>Usage:</b> <code>/disabledel (on/off)</code>
+disabledel-status = Messages using a disabled command { $enabled ->
+    [true] <b>are deleted.</b>
+   *[false] <b>are ignored, but not deleted.</b>
+}
+disabledel-log = Delete disabled commands: { $enabled ->
+    [true] ✅
+   *[false] ☑️
+}
 command-not-deactivatable = The <code>{ $command }</code> command <b>cannot be deactivated.</b>
 medias = Medias
 medias-help =
@@ -297,6 +372,8 @@ lastfm-help =
     <b>/artist   | /art:</b> Shows the artist you are or were listening to.
 id-required = You need to reply to a message or provide the ID of the user.
 id-invalid = Could not find this user. Reply to a message or provide a valid ID.
+id-anonymous = This message was sent by an anonymous admin, who can't be targeted.
+id-channel = This message was sent on behalf of a channel, not by a user.
 ban-success = User <a>{ $userBannedFirstName }</a> has been permanently banned.
 unban-success = User <a>{ $userUnbannedFirstName }</a> has been unbanned and can rejoin the group.
 ban-failed = Could not ban this user.
@@ -308,8 +385,58 @@ ban-success-temp = User <a>{ $userBannedFirstName }</a> has been banned until <c
 delete-msg-id-required = You need to reply to the message you want to delete.
 delete-msg-failed = Could not delete the message. Bots can only delete messages sent within the last 48 hours.
 delete-msg-success = Message deleted successfully.
+purge-usage =
+    Reply to the message you want to start deleting from, or specify how many messages to delete.
+
+    <b>Usage:</b> <code>/purge (count)</code>
+purge-success = { $count ->
+    [one] Purged up to <b>{ $count }</b> message.
+    *[other] Purged up to <b>{ $count }</b> messages.
+}
+purge-partial = Some messages couldn't be deleted because they're older than 48 hours.
+pin-reply-required = Reply to the message you want to pin.
+pin-usage =
+    Reply to the message you want to pin. Use <code>loud</code> to notify all members or <code>silent</code> to pin quietly.
+
+    <b>Usage:</b> <code>/pin (loud|silent)</code>
+pin-success = Message pinned successfully.
+pin-failed = Could not pin this message.
+unpin-success = Message unpinned successfully.
+unpin-failed = Could not unpin the message.
+unpinall-confirm = Are you sure you want to <b>unpin all messages</b> in this group?
+unpinall-yes-button = ✅ Yes
+unpinall-no-button = ❌ No
+unpinall-success = All messages have been unpinned.
+promote-success = User <a>{ $userFirstName }</a> has been promoted.
+fullpromote-success = User <a>{ $userFirstName }</a> has been promoted with full rights.
+promote-failed = Could not promote this user. I can only promote members and admins promoted by me.
+demote-success = User <a>{ $userFirstName }</a> has been demoted.
+demote-failed = Could not demote this user. I can only demote admins promoted by me.
+admin-outranked = You can't change the rights of an admin who holds rights you don't have.
+title-usage =
+    Reply to an admin or provide their ID, followed by the new title.
+
+    <b>Usage:</b> <code>/title (ID) (text)</code>
+title-too-long = The title can have at most <b>{ $max }</b> characters.
+title-success = Title of <a>{ $userFirstName }</a> set to <b>{ $title }</b>.
+title-failed = Could not set the title. I can only change titles of admins promoted by me.
+no-pinned-message = There is no pinned message <b>in this group.</b>
+pinned-message = <b>Click the button below to see the pinned message.</b>
+pinned-message-button = 📌 Pinned message
 bot-not-admin = I need to be an administrator to run this command.
+anonymous-admin-prompt = You're sending this command as an anonymous admin. Press the button below to confirm it and run it as yourself.
+anonymous-admin-button = I'm an admin
+anonymous-admin-expired = This confirmation has expired. Send the command again.
 user-not-admin = You do not have permission to run this command.
+user-missing-right = You need the <b>{ $right }</b> admin right to run this command.
+bot-missing-right = I need the <b>{ $right }</b> admin right to run this command.
+right-pin-messages = Pin messages
+right-promote-members = Add new admins
+right-change-info = Change group info
+right-delete-messages = Delete messages
+right-invite-users = Invite users via link
+right-manage-topics = Manage topics
+right-restrict-members = Ban users
 device-usage-hint = You need to provide a device name or codename to search.
 device-not-found = No devices found matching <code>{ $searchTerm }</code>.
 device-search-error = Could not retrieve device information. Please try again later.
@@ -330,4 +457,734 @@ android-help = <b>Android:</b>
     <b> - name:</b> Commercial name (e.g., <i>Redmi Note 11</i>).
     <b> - codename:</b> Device codename (e.g., <i>spes</i>).
     <b> - model:</b> Device model (e.g., <i>2201117TG</i>).
-group-only = This command can only be used in groups.
\ No newline at end of file
+group-only = This command can only be used in groups.
+locks = Locks
+locks-help =
+    <b>Locks</b>
+
+    Locks let you forbid some kinds of content in the group without muting everyone.
+    Messages with locked content sent by non-admins are <b>deleted automatically.</b>
+
+    <b>— Commands:</b>
+    <b>/lock (types):</b> Locks one or more content types. Use <code>all</code> to lock everything.
+    <b>/unlock (types):</b> Unlocks one or more content types.
+    <b>/locks:</b> Shows the current locks of the group.
+
+    <b>Note:</b>
+    You can also manage locks from the <code>/config</code> menu.
+config-locks =
+    <b>Locks settings:</b>
+    Messages containing locked content sent by non-admins will be deleted.
+
+    <b>Click on a content type to lock or unlock it.</b>
+locks-list = <b>Locks in this group:</b>
+lock-usage =
+    Please specify what you want to lock.
+    <b>Available types:</b> { $types }
+
+    <b>Usage:</b> <code>/lock (types)</code>
+unlock-usage =
+    Please specify what you want to unlock.
+    <b>Available types:</b> { $types }
+
+    <b>Usage:</b> <code>/unlock (types)</code>
+lock-invalid-type =
+    <code>{ $type }</code> is not a valid lock type.
+    <b>Available types:</b> { $types }
+lock-success = Locked: { $types }
+unlock-success = Unlocked: { $types }
+lock-links = Links
+lock-forwards = Forwards
+lock-stickers = Stickers
+lock-gifs = GIFs
+lock-photos = Photos
+lock-videos = Videos
+lock-documents = Documents
+lock-audios = Audios
+lock-voice = Voice notes
+lock-videonotes = Video notes
+lock-polls = Polls
+lock-inline = Inline bots
+lock-contacts = Contacts
+lock-location = Locations
+lock-games = Games
+lock-dice = Dice
+blocklist = Blocklist
+blocklist-help =
+    <b>Blocklist</b>
+
+    The blocklist automatically acts on messages containing banned words or patterns.
+    Messages from admins are never affected.
+
+    <b>— Commands:</b>
+    <b>/addblocklist (trigger) (action) (duration):</b> Adds a trigger. Use quotes for multi-word triggers.
+    <b>/rmblocklist (trigger):</b> Removes a trigger.
+    <b>/blocklist:</b> Lists the triggers of the group.
+    <b>/blocklistmode (action) (duration):</b> Sets the default action of the group.
+
+    <b>— Triggers:</b>
+    <b> - word:</b> Matches the whole word, e.g. <code>spam</code>.
+    <b> - wildcard:</b> <code>*</code> matches any characters, e.g. <code>*coin</code>.
+    <b> - regex:</b> Prefix with <code>re:</code>, e.g. <code>re:t\.me/\w+</code>.
+
+    <b>— Actions:</b> <code>delete</code>, <code>mute</code>, <code>tban</code> and <code>ban</code>.
+    Only <code>mute</code> and <code>tban</code> accept a duration (e.g., 1h, 2d).
+addblocklist-usage =
+    Please specify the trigger you want to add.
+
+    <b>Usage:</b> <code>/addblocklist (trigger) (action) (duration)</code>
+rmblocklist-usage =
+    Please specify the trigger you want to remove. To see the triggers, use /blocklist.
+
+    <b>Usage:</b> <code>/rmblocklist (trigger)</code>
+blocklistmode-usage =
+    Please specify a valid action: <code>delete</code>, <code>mute</code>, <code>tban</code> or <code>ban</code>.
+    <code>tban</code> requires a duration, and only <code>mute</code> and <code>tban</code> accept one.
+
+    <b>Usage:</b> <code>/blocklistmode (action) (duration)</code>
+blocklistmode-changed = The default blocklist action is now <code>{ $action }</code>.
+blocklist-invalid-regex = That regular expression is <b>invalid.</b>
+blocklist-invalid-action = Invalid action. Use <code>delete</code>, <code>mute</code>, <code>tban</code> or <code>ban</code>; <code>tban</code> requires a duration, and only <code>mute</code> and <code>tban</code> accept one.
+blocklist-added = Trigger <code>{ $pattern }</code> added to the blocklist.
+blocklist-removed = Trigger <code>{ $pattern }</code> removed from the blocklist.
+blocklist-not-found = Trigger <code>{ $pattern }</code> is not in the blocklist.
+blocklist-empty = There are no blocklisted triggers <b>in this group.</b>
+blocklist-list = <b>Blocklisted triggers</b> (default action: <code>{ $action }</code>):
+blocklist-action-mute = { $user } has been muted for using a blocklisted word.
+blocklist-action-mute-temp = { $user } has been muted until <code>{ $untilDate }</code> for using a blocklisted word.
+blocklist-action-ban = { $user } has been banned for using a blocklisted word.
+blocklist-action-ban-temp = { $user } has been banned until <code>{ $untilDate }</code> for using a blocklisted word.
+greetings = Greetings
+greetings-help =
+    <b>Greetings</b>
+
+    Greet members when they join the group and say goodbye when they leave.
+
+    <b>— Commands:</b>
+    <b>/welcome (on/off):</b> Enables or disables the welcome message. Without arguments, shows the current settings and a preview.
+    <b>/setwelcome (text):</b> Sets the welcome message. Reply to a message to use it, including photos, GIFs and stickers.
+    <b>/resetwelcome:</b> Restores the default welcome message.
+    <b>/goodbye (on/off):</b> Enables or disables the goodbye message.
+    <b>/setgoodbye (text):</b> Sets the goodbye message.
+    <b>/resetgoodbye:</b> Restores the default goodbye message.
+    <b>/cleanwelcome (on/off):</b> Deletes the previous welcome message when a new one is sent.
+
+    <b>— Placeholders:</b>
+    <code>{"{first}"}</code>, <code>{"{last}"}</code>, <code>{"{fullname}"}</code>, <code>{"{username}"}</code>, <code>{"{mention}"}</code>, <code>{"{id}"}</code>, <code>{"{chatname}"}</code>, <code>{"{count}"}</code> and <code>{"{rules}"}</code> (adds a button to the rules).
+
+    <b>— Buttons:</b>
+    <code>[text](buttonurl://example.com)</code> adds a button, and <code>[text](buttonurl://example.com:same)</code> puts it on the same row as the previous one.
+greeting-welcome = welcome message
+greeting-goodbye = goodbye message
+greeting-saved = The { $type } has been <b>saved.</b>
+greeting-reset = The { $type } has been <b>reset</b> to the default.
+greeting-enabled = The { $type } is now <b>enabled.</b>
+greeting-disabled = The { $type } is now <b>disabled.</b>
+greeting-status =
+    <b>Current { $type }:</b>
+    Enabled: { $enabled ->
+        [true] ✅
+       *[false] ❌
+    }
+    Delete previous: { $clean ->
+        [true] ✅
+       *[false] ❌
+    }
+greeting-rules-button = 📜 Rules
+welcome-default = Hey { "{mention}" }, welcome to <b>{ "{chatname}" }</b>!
+goodbye-default = { "{first}" } has left the group.
+welcome-usage =
+    Please specify <code>on</code> or <code>off</code>.
+
+    <b>Usage:</b> <code>/welcome (on/off)</code>
+goodbye-usage =
+    Please specify <code>on</code> or <code>off</code>.
+
+    <b>Usage:</b> <code>/goodbye (on/off)</code>
+setwelcome-usage =
+    Reply to a message or write the welcome message after the command.
+
+    <b>Usage:</b> <code>/setwelcome (text)</code>
+setgoodbye-usage =
+    Reply to a message or write the goodbye message after the command.
+
+    <b>Usage:</b> <code>/setgoodbye (text)</code>
+cleanwelcome-usage =
+    Please specify <code>on</code> or <code>off</code>.
+
+    <b>Usage:</b> <code>/cleanwelcome (on/off)</code>
+cleanwelcome-enabled = The previous welcome message will now be <b>deleted</b> when someone joins.
+cleanwelcome-disabled = Previous welcome messages will <b>no longer be deleted.</b>
+captcha = Captcha
+captcha-help =
+    <b>Captcha</b>
+
+    New members are muted until they prove they are human by solving a challenge. Those who do not solve it in time are kicked.
+    Members added by admins are not challenged.
+
+    <b>— Commands:</b>
+    <b>/captcha (on/off):</b> Enables or disables the captcha. Without arguments, shows the current settings.
+    <b>/captchamode (mode):</b> Sets the challenge: <code>button</code>, <code>math</code> or <code>emoji</code>.
+    <b>/captchatime (duration):</b> Sets how long new members have to solve the challenge, between 30s and 1d (e.g., 5m, 1h).
+captcha-usage =
+    Please specify <code>on</code> or <code>off</code>.
+
+    <b>Usage:</b> <code>/captcha (on/off)</code>
+captchamode-usage =
+    Please specify a valid mode: <code>button</code>, <code>math</code> or <code>emoji</code>.
+
+    <b>Usage:</b> <code>/captchamode (mode)</code>
+captchatime-usage =
+    Please specify a duration between 30s and 1d.
+
+    <b>Usage:</b> <code>/captchatime (duration)</code>
+captcha-enabled = Captcha is now <b>enabled.</b> New members will have to solve a challenge before talking.
+captcha-disabled = Captcha is now <b>disabled.</b>
+captchamode-changed = The captcha mode is now <code>{ $mode }</code>.
+captchatime-changed = New members now have <b>{ $timeout }</b> to solve the captcha.
+captcha-status =
+    <b>Captcha settings:</b>
+    Enabled: { $enabled ->
+        [true] ✅
+       *[false] ❌
+    }
+    Mode: <code>{ $mode }</code>
+    Timeout: { $timeout }
+captcha-verify-button = ✅ I'm not a robot
+captcha-button = Welcome, <a href='tg://user?id={ $userID }'>{ $userFirstName }</a>! Press the button below within <b>{ $timeout }</b> to prove you are human.
+captcha-math = Welcome, <a href='tg://user?id={ $userID }'>{ $userFirstName }</a>! Solve <b>{ $question }</b> within <b>{ $timeout }</b> to prove you are human.
+captcha-emoji = Welcome, <a href='tg://user?id={ $userID }'>{ $userFirstName }</a>! Select the { $question } within <b>{ $timeout }</b> to prove you are human.
+captcha-solved = Verified! You can now talk in the group.
+captcha-wrong = { $attempts ->
+    [one] Wrong answer. You have 1 attempt left.
+   *[other] Wrong answer. You have { $attempts } attempts left.
+}
+captcha-failed = Wrong answer. You have been removed from the group.
+captcha-expired = This captcha is no longer valid.
+notes = Notes
+notes-help =
+    <b>Notes</b>
+
+    Save messages to be retrieved later, such as rules and useful links.
+
+    <b>— Commands:</b>
+    <b>/save (name) (text):</b> Saves a note. Reply to a message to save it, including media and formatting.
+    <b>/get (name):</b> Sends a note. Reply to a message to send the note as a reply to it.
+    <b>#name:</b> Same as <code>/get name</code>.
+    <b>/notes:</b> Lists the notes of the group.
+    <b>/clear (name):</b> Deletes a note.
+
+    <b>— Formatting:</b>
+    Notes accept the same placeholders and buttons as the welcome message, e.g. <code>{"{first}"}</code> and <code>[text](buttonurl://example.com)</code>.
+    Add <code>{"{private}"}</code> to a note to send it in private instead of in the group.
+get-usage =
+    Please specify the note you want to get.
+
+    <b>Usage:</b> <code>/get (name)</code>
+save-usage =
+    Please specify the name and the content of the note, or reply to a message.
+
+    <b>Usage:</b> <code>/save (name) (text)</code>
+clear-usage =
+    Please specify the note you want to delete.
+
+    <b>Usage:</b> <code>/clear (name)</code>
+note-invalid-name = Note names can only contain <b>letters, numbers, - and _.</b>
+note-saved = Note <code>#{ $name }</code> <b>saved.</b>
+note-deleted = Note <code>#{ $name }</code> <b>deleted.</b>
+note-not-found = There is no note called <code>#{ $name }</code>.
+notes-empty = There are no notes <b>in this group.</b>
+notes-list = <b>Notes in this group:</b>
+note-sent-private = I've sent you the note in private.
+note-private-start = This note is private. Start a chat with me so I can send it to you.
+filters = Filters
+filters-help =
+    <b>Filters</b>
+
+    Filters make the bot reply automatically when a message contains a trigger.
+
+    <b>— Commands:</b>
+    <b>/filter (trigger) (text):</b> Adds a filter. Reply to a message to use it as the reply, including stickers and media.
+    <b>/stop (trigger):</b> Removes a filter.
+    <b>/filters:</b> Lists the filters of the group.
+
+    <b>— Triggers:</b>
+    Use quotes for triggers with multiple words, e.g. <code>/filter "good morning" Hello!</code>
+    By default the trigger can be anywhere in the message. Prefix it to change how it matches:
+    <b> - exact:</b> The whole message must be the trigger, e.g. <code>exact:hi</code>.
+    <b> - prefix:</b> The message must start with the trigger, e.g. <code>prefix:!help</code>.
+    <b> - re:</b> The trigger is a regular expression, e.g. <code>re:^(hi|hello)$</code>.
+
+    Replies accept the same placeholders and buttons as the welcome message, e.g. <code>{"{first}"}</code> and <code>[text](buttonurl://example.com)</code>.
+    Use <code>/disable filters</code> to stop all filters from replying.
+filter-usage =
+    Please specify the trigger and the reply, or reply to a message.
+
+    <b>Usage:</b> <code>/filter (trigger) (text)</code>
+stop-usage =
+    Please specify the filter you want to remove. To see the filters, use /filters.
+
+    <b>Usage:</b> <code>/stop (trigger)</code>
+filter-invalid-regex = That regular expression is <b>invalid.</b>
+filter-saved = Filter <code>{ $trigger }</code> ({ $mode }) <b>saved.</b>
+filter-deleted = Filter <code>{ $trigger }</code> <b>removed.</b>
+filter-not-found = There is no filter for <code>{ $trigger }</code>.
+filters-empty = There are no filters <b>in this group.</b>
+filters-list = <b>Filters in this group:</b>
+rules = Rules
+rules-help =
+    <b>Rules</b>
+
+    Keep the rules of the group one command away. In groups, the rules are sent in private to avoid flooding the chat.
+
+    <b>— Commands:</b>
+    <b>/rules:</b> Shows the rules of the group.
+    <b>/setrules (text):</b> Sets the rules. Reply to a message to use it, keeping its formatting.
+    <b>/clearrules:</b> Deletes the rules.
+setrules-usage =
+    Please write the rules after the command or reply to a message containing them.
+
+    <b>Usage:</b> <code>/setrules (text)</code>
+rules-saved = The rules of the group have been <b>saved.</b>
+rules-cleared = The rules of the group have been <b>deleted.</b>
+rules-empty = This group has not set any rules yet.
+rules-group = Click the button below to read the rules of the group.
+rules-button = 📜 Rules
+rules-private = <b>Rules of { $chatName }:</b>
+reports = Reports
+reports-help =
+    <b>Reports</b>
+
+    Members can report a message to the admins by replying to it with /report or by mentioning @admin.
+    The admins are notified with buttons to take action. To avoid abuse, each member can only report once every few minutes.
+
+    <b>— Commands:</b>
+    <b>/report (reason):</b> Reports the replied message to the admins.
+    <b>/reports (on/off):</b> In groups, enables or disables reports. In private, chooses whether you receive the reports of the groups you admin.
+report-usage = Reply to the message you want to report.
+report-not-allowed = You can't report this message.
+report-cooldown = You have reported a message recently. <b>Please wait before reporting again.</b>
+report-already = This message has already been reported to the admins.
+report-sent = { $reporter } reported { $user } to the admins.
+report-reason = <b>Reason:</b> { $reason }
+report-private =
+    { $reporter } reported { $user } in <b>{ $chatName }</b>.
+    <a href='{ $link }'>Go to the message</a>
+report-ban-button = 🔨 Ban
+report-mute-button = 🔇 Mute
+report-delete-button = 🗑 Delete
+report-ignore-button = ✖️ Ignore
+report-handled = { $action ->
+    [ban] <a href='tg://user?id={ $userID }'>The reported user</a> was <b>banned</b> by { $admin }.
+    [mute] <a href='tg://user?id={ $userID }'>The reported user</a> was <b>muted</b> by { $admin }.
+    [delete] The reported message was <b>deleted</b> by { $admin }.
+   *[ignore] The report was <b>dismissed</b> by { $admin }.
+}
+report-action-failed = I couldn't do that. Check that I have the necessary permissions.
+reports-usage =
+    Please specify <code>on</code> or <code>off</code>.
+
+    <b>Usage:</b> <code>/reports (on/off)</code>
+reports-group-status = Reports in this group are { $enabled ->
+    [true] <b>enabled.</b>
+   *[false] <b>disabled.</b>
+}
+reports-private-status = { $enabled ->
+    [true] You <b>will receive</b> in private the reports of the groups you admin.
+   *[false] You <b>won't receive</b> in private the reports of the groups you admin.
+}
+setlog-usage =
+    Tell me which channel to use.
+
+    <b>Usage:</b> <code>/setlog (ID|@username)</code>, or reply to a message forwarded from the channel.
+setlog-not-channel = That chat isn't a channel.
+setlog-bot-cant-post = I need to be an admin allowed to post messages in that channel.
+setlog-user-not-admin = You must be an admin of that channel to use it as the log channel.
+setlog-success = Moderation events will now be posted to <b>{ $channelName }</b>.
+log-channel-linked = This channel will now receive the moderation logs of <b>{ $chatName }</b>.
+unsetlog-success = The log channel has been unlinked.
+log-channel-none = This group has no log channel.
+log-channel-current = The log channel is <b>{ $channelName }</b> (<code>{ $channelID }</code>).
+log-action-ban = 🔨 <b>#BAN</b>
+log-action-unban = 🔓 <b>#UNBAN</b>
+log-action-mute = 🔇 <b>#MUTE</b>
+log-action-unmute = 🔊 <b>#UNMUTE</b>
+log-action-delete = 🗑 <b>#DELETE</b>
+log-action-promote = ⭐️ <b>#PROMOTE</b>
+log-action-fullpromote = 🌟 <b>#FULLPROMOTE</b>
+log-action-demote = ⬇️ <b>#DEMOTE</b>
+log-action-config = ⚙️ <b>#CONFIG</b>
+log-action-language = 🌐 <b>#LANGUAGE</b>
+log-action-disable = 🚫 <b>#DISABLE</b>
+log-action-restrict = 🛡 <b>#RESTRICT</b>
+log-action-joinapprove = ✅ <b>#JOINAPPROVE</b>
+log-action-joindecline = ⛔️ <b>#JOINDECLINE</b>
+log-action-import = 📥 <b>#IMPORT</b>
+log-action-newtopic = 💬 <b>#NEWTOPIC</b>
+log-action-closetopic = 🔒 <b>#CLOSETOPIC</b>
+log-action-reopentopic = 🔓 <b>#REOPENTOPIC</b>
+log-action-renametopic = ✏️ <b>#RENAMETOPIC</b>
+log-action-enable = ✅ <b>#ENABLE</b>
+log-action-join = 👋 <b>#JOIN</b>
+log-action-leave = 🚪 <b>#LEAVE</b>
+log-chat = <b>Chat:</b> { $chatName } (<code>{ $chatID }</code>)
+log-admin = <b>Admin:</b> { $admin } (<code>{ $id }</code>)
+log-user = <b>User:</b> { $user } (<code>{ $id }</code>)
+log-details = <b>Change:</b> { $details }
+log-reason = <b>Reason:</b> { $reason }
+log-until = <b>Until:</b> <code>{ $untilDate }</code>
+log-link = <a href='{ $link }'>Go to message</a>
+log-action-gban = 🌐 <b>#GBAN</b>
+gban-enforced = <a href='tg://user?id={ $userID }'>{ $userFirstName }</a> is globally banned and has been removed from this group.
+gban-reason = <b>Reason:</b> { $reason }
+gban-protected = This user can't be globally banned.
+gban-success = User <code>{ $userID }</code> has been globally banned. { $count ->
+    [one] Banned from <b>1</b> group.
+   *[other] Banned from <b>{ $count }</b> groups.
+}
+gban-not-found = This user isn't globally banned.
+ungban-success = User <code>{ $userID }</code> has been removed from the global ban list. { $count ->
+    [one] Unbanned from <b>1</b> group.
+   *[other] Unbanned from <b>{ $count }</b> groups.
+}
+gbanstat-usage =
+    Specify <code>on</code> or <code>off</code>.
+
+    <b>Usage:</b> <code>/gbanstat (on/off)</code>
+gbanstat-status = Global bans in this group are { $enabled ->
+    [true] <b>enforced.</b>
+   *[false] <b>not enforced.</b>
+} The global list has <b>{ $count }</b> users.
+gbanexport-caption = Global ban list with <b>{ $count }</b> users.
+gbanimport-usage = Reply to a JSON file exported with /gbanexport.
+gbanimport-invalid = This file isn't a valid global ban list.
+gbanimport-success = { $count ->
+    [one] Imported <b>1</b> global ban.
+   *[other] Imported <b>{ $count }</b> global bans.
+}
+sudo-added = User <code>{ $userID }</code> is now a sudo user.
+sudo-already = User <code>{ $userID }</code> is already a sudo user.
+sudo-removed = User <code>{ $userID }</code> is no longer a sudo user.
+sudo-not-found = User <code>{ $userID }</code> isn't a sudo user.
+sudoers-empty = There are no sudo users.
+sudoers-list = <b>Sudo users:</b>
+log-action-approve = ✅ <b>#APPROVE</b>
+log-action-unapprove = ❎ <b>#UNAPPROVE</b>
+log-action-unapproveall = ❎ <b>#UNAPPROVEALL</b>
+user-not-owner = Only the group owner can do this.
+approve-admin = Admins are already exempt from automated moderation.
+approve-success = User <a>{ $userFirstName }</a> has been approved and won't be affected by automated moderation.
+approve-unchanged = User <a>{ $userFirstName }</a> is already approved.
+unapprove-success = User <a>{ $userFirstName }</a> is no longer approved.
+unapprove-unchanged = User <a>{ $userFirstName }</a> isn't approved.
+approved-empty = There are no approved users in this group.
+approved-list = <b>Approved users:</b>
+unapproveall-confirm = Are you sure you want to remove every approval in this group?
+unapproveall-yes-button = ✅ Yes
+unapproveall-no-button = ❌ No
+unapproveall-success = { $count ->
+    [one] <b>1</b> user is no longer approved.
+   *[other] <b>{ $count }</b> users are no longer approved.
+}
+antiraid = Anti-raid
+antiraid-help =
+    <b>Anti-raid</b>
+
+    While the anti-raid mode is on, every new member is removed as soon as they join. It turns itself off after the configured duration.
+    It can also turn on automatically when too many users join within a minute.
+
+    <b>— Commands:</b>
+    <b>/antiraid (duration|on/off):</b> Toggles the anti-raid mode. Give a duration (e.g., 30m, 3h) to enable it for that long.
+    <b>/raidtime (duration):</b> Sets how long the anti-raid mode lasts by default, between 1m and 7d.
+    <b>/raidaction (kick/ban):</b> Sets what happens to users who join during a raid.
+    <b>/autoantiraid (count/off):</b> Enables the anti-raid mode automatically when <i>count</i> users join within a minute.
+antiraid-usage =
+    Specify <code>on</code>, <code>off</code> or a duration between 1m and 7d.
+
+    <b>Usage:</b> <code>/antiraid (duration|on/off)</code>
+antiraid-enabled = 🛡 The anti-raid mode is <b>on</b> for <b>{ $duration }</b>. New members will be { $action ->
+    [ban] <b>banned.</b>
+   *[kick] <b>kicked.</b>
+}
+antiraid-disabled = The anti-raid mode is <b>off</b>.
+antiraid-triggered = 🚨 <b>Raid detected!</b> At least { $threshold } users joined within a minute, so the anti-raid mode is <b>on</b> for <b>{ $duration }</b>. New members will be { $action ->
+    [ban] <b>banned.</b>
+   *[kick] <b>kicked.</b>
+}
+antiraid-ended = The anti-raid mode has ended. New members can join again.
+antiraid-log-on = Enabled
+antiraid-log-off = Disabled
+antiraid-log-auto = Enabled automatically after { $threshold } joins within a minute
+log-action-antiraid = 🛡 <b>#ANTIRAID</b>
+raidtime-usage =
+    Specify a duration between 1m and 7d.
+
+    <b>Usage:</b> <code>/raidtime (duration)</code>
+raidtime-current = The anti-raid mode lasts <b>{ $duration }</b> by default.
+raidtime-set = The anti-raid mode will now last <b>{ $duration }</b> by default.
+raidaction-usage =
+    Specify <code>kick</code> or <code>ban</code>.
+
+    <b>Usage:</b> <code>/raidaction (kick/ban)</code>
+raidaction-current = Users who join during a raid are { $action ->
+    [ban] <b>banned.</b>
+   *[kick] <b>kicked.</b>
+}
+raidaction-set = Users who join during a raid will now be { $action ->
+    [ban] <b>banned.</b>
+   *[kick] <b>kicked.</b>
+}
+autoantiraid-usage =
+    Specify how many joins within a minute trigger the anti-raid mode, at least 2, or <code>off</code>.
+
+    <b>Usage:</b> <code>/autoantiraid (count/off)</code>
+autoantiraid-current = { $threshold ->
+    [0] The anti-raid mode <b>won't</b> turn on automatically.
+   *[other] The anti-raid mode turns on automatically when <b>{ $threshold }</b> users join within a minute.
+}
+nightmode = Night mode
+nightmode-help =
+    <b>Night mode</b>
+
+    Turns the group read-only during the configured hours. When the night ends, the permissions the group had before are restored.
+
+    <b>— Commands:</b>
+    <b>/nightmode (on/off):</b> Enables or disables night mode. Without arguments, shows the current settings.
+    <b>/nighttime (start) (end):</b> Sets when the night starts and ends, e.g. <code>/nighttime 23:00 07:00</code>.
+    <b>/nighttz (timezone):</b> Sets the timezone of the schedule, e.g. <code>America/Sao_Paulo</code>.
+
+    <b>Note:</b>
+    You can also manage night mode from the <code>/config</code> menu.
+nightmode-usage =
+    Specify <code>on</code> or <code>off</code>.
+
+    <b>Usage:</b> <code>/nightmode (on/off)</code>
+nightmode-status =
+    Night mode is { $enabled ->
+        [true] <b>enabled.</b>
+       *[false] <b>disabled.</b>
+    }
+    <b>Start:</b> <code>{ $start }</code>
+    <b>End:</b> <code>{ $end }</code>
+    <b>Timezone:</b> <code>{ $timezone }</code>
+nighttime-usage =
+    Specify two different times in the <code>HH:MM</code> format.
+
+    <b>Usage:</b> <code>/nighttime (start) (end)</code>
+nighttime-set = Night mode will now run from <code>{ $start }</code> to <code>{ $end }</code>.
+nighttz-usage =
+    Specify an IANA timezone.
+
+    <b>Usage:</b> <code>/nighttz (timezone)</code>, e.g. <code>/nighttz Europe/Lisbon</code>
+nighttz-invalid = <code>{ $timezone }</code> isn't a valid timezone. Use an IANA name, e.g. <code>Europe/Lisbon</code>.
+nighttz-set = The night mode timezone is now <code>{ $timezone }</code>, where it's <code>{ $time }</code>.
+nightmode-started = 🌙 <b>Night mode is on.</b> The group is read-only until <code>{ $end }</code>.
+nightmode-ended = ☀️ <b>Good morning!</b> Night mode is over and everyone can send messages again until <code>{ $start }</code>.
+config-nightmode =
+    <b>Night mode settings:</b>
+    The group becomes read-only between the start and end times.
+
+    <b>Use ➖ and ➕ to move the times by 30 minutes.</b>
+nightmode-button = Night mode
+nightmode-start-button = Start: { $time }
+nightmode-end-button = End: { $time }
+nightmode-time-alert = Use ➖ and ➕ to move this time, or /nighttime to set it precisely.
+nightmode-timezone-alert = Use /nighttz followed by an IANA timezone, such as Europe/Lisbon, to change it.
+connect-group = Click the button below to manage this group from my private chat.
+connect-button = Connect in private
+connect-usage = Use <code>/connect (chat ID or @username)</code> to manage a group you administer from here.
+connect-current = You're connected to <b>{ $chatName }</b>.
+connect-recent = <b>Recent connections:</b>
+connect-success =
+    You're now connected to <b>{ $chatName }</b>.
+    Use /config, /disable, /enable and /disabled here to manage it, and /disconnect when you're done.
+connect-not-found = I couldn't find that chat. Make sure I'm a member of it.
+connect-not-group = You can only connect to groups.
+connect-not-admin = You must be an admin of that group to connect to it.
+connect-failed = I couldn't connect you to that group, try again later.
+disconnect-success = You've been disconnected.
+disconnect-none = You aren't connected to any group.
+joinrequests = Join requests
+joinrequests-help =
+    <b>Join requests</b>
+
+    When the group requires admin approval to join, I can handle the requests for you. Each request is posted to the log channel, or to the group if there's none, with buttons to approve or decline it.
+
+    <b>— Commands:</b>
+    <b>/joinrequests (on/off):</b> Enables or disables the handling of join requests. Without arguments, shows the current settings.
+    <b>/joinrequests username (on/off):</b> Approves users with a username right away.
+    <b>/joinrequests gban (on/off):</b> Declines globally banned users right away.
+    <b>/joinrequests captcha (on/off):</b> Sends the requester the group's captcha in private and approves them once they solve it.
+
+    <b>Note:</b>
+    Users who can't be messaged in private are left for the admins to review.
+joinrequests-usage =
+    Specify <code>on</code> or <code>off</code>, optionally after <code>username</code>, <code>gban</code> or <code>captcha</code>.
+
+    <b>Usage:</b> <code>/joinrequests [username|gban|captcha] (on/off)</code>
+joinrequests-status =
+    Join requests are { $enabled ->
+        [true] <b>handled by me.</b>
+       *[false] <b>not handled by me.</b>
+    }
+    <b>Approve users with a username:</b> { $username ->
+        [true] yes
+       *[false] no
+    }
+    <b>Decline globally banned users:</b> { $gban ->
+        [true] yes
+       *[false] no
+    }
+    <b>Captcha:</b> { $captcha ->
+        [true] yes
+       *[false] no
+    }
+    <b>Reviews are posted to:</b> { $logChannel ->
+        [true] the log channel
+       *[false] this group
+    }
+joinrequest-new = 📨 <a href='tg://user?id={ $userID }'>{ $userFirstName }</a> (<code>{ $userID }</code>) asked to join <b>{ $chatName }</b>.
+joinrequest-username = <b>Username:</b> @{ $username }
+joinrequest-bio = <b>Bio:</b> { $bio }
+joinrequest-approve-button = ✅ Approve
+joinrequest-decline-button = ⛔️ Decline
+joinrequest-approved = <a href='tg://user?id={ $adminID }'>{ $adminFirstName }</a> approved <a href='tg://user?id={ $userID }'>{ $userFirstName }</a> to join <b>{ $chatName }</b>.
+joinrequest-declined = <a href='tg://user?id={ $adminID }'>{ $adminFirstName }</a> declined the request of <a href='tg://user?id={ $userID }'>{ $userFirstName }</a> to join <b>{ $chatName }</b>.
+joinrequest-gone = This request has already been handled.
+joinrequest-captcha-button = You asked to join <b>{ $chatName }</b>. Press the button below within <b>{ $timeout }</b> to prove you are human.
+joinrequest-captcha-math = You asked to join <b>{ $chatName }</b>. Solve <b>{ $question }</b> within <b>{ $timeout }</b> to prove you are human.
+joinrequest-captcha-emoji = You asked to join <b>{ $chatName }</b>. Select the { $question } within <b>{ $timeout }</b> to prove you are human.
+joinrequest-captcha-solved = ✅ Done! Your request to join <b>{ $chatName }</b> has been approved.
+joinrequest-captcha-failed = ⛔️ You failed the captcha, so your request to join <b>{ $chatName }</b> has been declined.
+joinrequest-captcha-expired = ⌛️ You didn't solve the captcha in time, so your request to join <b>{ $chatName }</b> has been declined.
+joinrequest-reason-gban = Globally banned
+joinrequest-reason-username = Has a username
+joinrequest-reason-captcha-solved = Solved the captcha
+joinrequest-reason-captcha-failed = Failed the captcha
+joinrequest-reason-captcha-expired = Didn't solve the captcha in time
+cleanup = Cleanup
+cleanup-help =
+    <b>Cleanup</b>
+
+    Keeps the group tidy by deleting service messages, the commands sent to me and my own replies.
+
+    <b>— Commands:</b>
+    <b>/cleanservice (type/all) (on/off):</b> Deletes the service messages of a type. Without arguments, shows which types are deleted.
+    <b>/cleancommands (on/off):</b> Deletes the commands sent to me once they're handled.
+    <b>/cleanreplies (duration/off):</b> Deletes my replies after a while, between 5s and 1d.
+
+    <b>Service message types:</b>
+    <code>join</code>, <code>leave</code>, <code>pin</code>, <code>title</code>, <code>photo</code>, <code>videochat</code>, <code>topics</code> and <code>other</code>.
+
+    <b>Note:</b>
+    You can also manage these settings from the <code>/config</code> menu. I need the right to delete messages.
+cleanservice-usage =
+    Specify a service message type, or <code>all</code>, followed by <code>on</code> or <code>off</code>.
+
+    <b>Usage:</b> <code>/cleanservice (type/all) (on/off)</code>
+cleanservice-status = <b>Service messages deleted in this group:</b>
+cleanservice-join = New members
+cleanservice-leave = Members leaving
+cleanservice-pin = Pinned messages
+cleanservice-title = Title changes
+cleanservice-photo = Photo changes
+cleanservice-videochat = Video chats
+cleanservice-topics = Topics
+cleanservice-other = Others
+cleancommands-usage =
+    Specify <code>on</code> or <code>off</code>.
+
+    <b>Usage:</b> <code>/cleancommands (on/off)</code>
+cleancommands-status = Commands sent to me { $enabled ->
+    [true] <b>are deleted</b> once handled.
+   *[false] <b>are kept.</b>
+}
+cleancommands-button = Delete commands
+cleanreplies-usage =
+    Specify a duration between 5s and 1d, or <code>off</code>.
+
+    <b>Usage:</b> <code>/cleanreplies (duration/off)</code>
+cleanreplies-status = My replies are deleted after: <b>{ $delay }</b>
+cleanreplies-never = never
+cleanreplies-button = Delete replies: { $delay }
+cleanreplies-alert = Use ➖ and ➕ to change this delay, or /cleanreplies to set it precisely.
+config-cleanup =
+    <b>Cleanup settings:</b>
+    Choose the service messages to delete, whether the commands sent to me are deleted, and after how long my replies are deleted.
+
+    <b>Use ➖ and ➕ to change the delay.</b>
+export-no-group = Use this command in a group, or /connect to one first.
+export-caption = Settings of <b>{ $chatName }</b>. Reply to this file with /import in another group to copy them there.
+import-usage = Reply to a file made by /export to copy its settings to this group.
+import-invalid = This file isn't a valid settings export.
+import-unsupported = This file was made by a newer version of the bot (format { $version }), so I can't import it.
+import-success =
+    The settings of <b>{ $chatName }</b> were replaced with the ones in the file.
+    <b>Imported:</b> { $sections }
+topics = Topics
+topics-help =
+    <b>Topics</b>
+
+    Manages the topics of groups with topics enabled. Each command acts on the topic it's sent in, the General one included.
+
+    <b>— Commands:</b>
+    <b>/newtopic (name):</b> Creates a topic.
+    <b>/renametopic (name):</b> Renames the current topic.
+    <b>/closetopic:</b> Closes the current topic, so only admins can post in it.
+    <b>/reopentopic:</b> Reopens the current topic.
+    <b>/topicmedias (on/off/default):</b> Chooses whether links sent in the current topic are downloaded automatically, regardless of the group setting.
+
+    <b>Note:</b>
+    Commands can also be disabled in a single topic, with <code>/disable topic (commands)</code>. Managing topics needs the right to manage them, for both you and me.
+topics-forum-only = This group doesn't have topics enabled.
+topics-failed = I couldn't do that. Check that I can manage topics here.
+newtopic-usage =
+    Specify the name of the topic, up to 128 characters.
+
+    <b>Usage:</b> <code>/newtopic (name)</code>
+newtopic-success = The topic <b>{ $name }</b> was created.
+newtopic-welcome = Welcome to <b>{ $name }</b>!
+closetopic-success = This topic is now <b>closed.</b>
+reopentopic-success = This topic is now <b>open.</b>
+renametopic-usage =
+    Specify the new name of the topic, up to 128 characters.
+
+    <b>Usage:</b> <code>/renametopic (name)</code>
+renametopic-success = This topic is now called <b>{ $name }</b>.
+topicmedias-usage =
+    Specify <code>on</code>, <code>off</code> or <code>default</code>, to follow the group setting.
+
+    <b>Usage:</b> <code>/topicmedias (on/off/default)</code>
+topicmedias-status = Links sent in this topic { $state ->
+    [true] <b>are downloaded</b> automatically.
+    [false] <b>aren't downloaded</b> automatically.
+   *[default] follow the <b>group setting</b> for automatic downloads.
+}
+topicmedias-log = Topic automatic downloads: { $state ->
+    [true] ✅
+    [false] ☑️
+   *[default] —
+}
+edits = Edits
+edits-help =
+    <b>Edits</b>
+
+    Handles edited messages like new ones, so fixing a typo in a command or adding a link to a message still gets an answer.
+
+    <b>— Commands:</b>
+    <b>/edits (on/off):</b> Chooses whether edited messages are handled. Without arguments, shows the current setting.
+
+    <b>Note:</b>
+    Only what an edit adds is handled: a command I haven't answered yet or new media links, so media I already sent isn't downloaded again. The locks and the blocklist still check the whole edited message. Messages older than two days are left alone. In groups, only admins can change this.
+edits-usage =
+    Specify <code>on</code> or <code>off</code>.
+
+    <b>Usage:</b> <code>/edits (on/off)</code>
+edits-status = Edited messages { $enabled ->
+    [true] <b>are handled</b> like new ones.
+   *[false] <b>are ignored.</b>
+}
+edits-log = Handle edited messages: { $enabled ->
+    [true] ✅
+   *[false] ☑️
+}
@@ -120,19 +120,59 @@ moderation-help =
     <b>/banir [ID|resposta] (tempo) (revoke):</b> Bane um usuário do grupo.
     <b>/mute [ID|resposta] (tempo):</b> Silenciar um usuário do grupo.
     <b>/delete [resposta]:</b> Deletar uma mensagem.
+    <b>/purge [resposta] (quantidade):</b> Apaga todas as mensagens desde a respondida até o comando, ou as últimas <i>quantidade</i> mensagens.
+    <b>/spurge [resposta] (quantidade):</b> Igual ao /purge, mas sem aviso.
     <b> - ID ou resposta:</b> Especifique o ID do usuário ou responda à mensagem dele.
     <b> - tempo:</b> (opcional) Defina por quanto tempo a restrição será aplicada (ex: 1h, 2d).
     <b> - revoke:</b> (opcional) Se definido, remove todas as mensagens do usuário.
 
+    <b>— Fixados:</b>
+    <b>/pin [resposta] (loud|silent):</b> Fixa a mensagem respondida. Use <code>loud</code> para notificar todos os membros.
+    <b>/unpin [resposta]:</b> Desafixa a mensagem respondida, ou a última fixada.
+    <b>/unpinall:</b> Desafixa todas as mensagens após confirmação.
+    <b>/pinned:</b> Mostra um link para a mensagem fixada atual.
+
+    <b>— Administradores:</b>
+    <b>/promote [ID|resposta] (título):</b> Promove um usuário a administrador com permissões básicas.
+    <b>/fullpromote [ID|resposta] (título):</b> Promove um usuário com todas as permissões que você possui.
+    <b>/demote [ID|resposta]:</b> Remove as permissões de administrador de um usuário.
+    <b>/title [ID|resposta] (texto):</b> Define o título personalizado de um administrador.
+    <i>Você só pode conceder permissões que você mesmo possui.</i>
+
+    <b>— Aprovações:</b>
+    <b>/approve [ID|resposta]:</b> Isenta um usuário dos bloqueios, da lista de bloqueio e do captcha.
+    <b>/unapprove [ID|resposta]:</b> Remove a aprovação de um usuário.
+    <b>/approved:</b> Lista os usuários aprovados.
+    <b>/unapproveall:</b> Remove todas as aprovações. Apenas o dono do grupo pode usá-lo.
+
     <b>— Configurações:</b>
-    <b>/disable (comando):</b> Desativa o comando especificado no grupo.
-    <b>/enable (comando):</b> Reativa o comando que foi previamente desativado.
-    <b>/disableable:</b> Lista todos os comandos que podem ser desativados.
-    <b>/disabled:</b> Exibe os comandos que estão atualmente desativados.
+    <b>/disable (comandos):</b> Desativa os comandos especificados no grupo, junto com seus apelidos. Use <code>/disable module (módulos)</code> para desativar módulos inteiros, ou <code>/disable all</code> para todos os comandos. Comece com <code>topic</code> dentro de um tópico do fórum para desativá-los apenas nele.
+    <b>/enable (comandos):</b> Reativa comandos que foram previamente desativados. Também aceita <code>module</code>, <code>all</code> e <code>topic</code>.
+    <b>/restrict (comandos):</b> Faz com que os comandos só possam ser usados por administradores. Também aceita <code>module</code>, <code>all</code> e <code>topic</code>, e /enable desfaz.
+    <b>/disableable:</b> Lista todos os comandos que podem ser desativados, por módulo, com seu estado no grupo.
+    <b>/disabled:</b> Exibe os comandos que estão atualmente desativados ou restritos a administradores e, em um tópico do fórum, os desativados apenas nele.
+    <b>/disabledel (on/off):</b> Apaga as mensagens que usam um comando desativado.
     <b>/config:</b> Abre um menu com opções de configurações do grupo.
+    <b>/export:</b> Envia um arquivo com as configurações, notas, filtros e outros dados de moderação do grupo.
+    <b>/import:</b> Respondendo a um arquivo gerado pelo /export, substitui as configurações do grupo pelas dele.
+
+    <b>— Canal de registros:</b>
+    <b>/setlog [canal|resposta]:</b> Envia cada evento de moderação para um canal. Informe o ID ou @username dele, ou responda a uma mensagem encaminhada dele.
+    <b>/unsetlog:</b> Para de enviar eventos ao canal de registros.
+    <b>/logchannel:</b> Mostra o canal de registros atual.
+    <b>/gbanstat (on/off):</b> Bane os usuários da lista global de banimentos do bot assim que entrarem ou falarem.
+    <i>As restrições aceitam um motivo após o tempo, ex: <code>/ban 1d spam</code>.</i>
+
+    <b>— Conexões</b>
+    <b>/connect (ID do chat ou @username):</b> Conecta a um grupo que você administra, para gerenciar suas configurações pelo meu chat privado.
+    <b>/disconnect:</b> Desconecta do grupo atual.
+    <i>Enquanto conectado, os comandos de configuração enviados no privado, como /config, /disable, /export ou /edits, agem sobre o grupo conectado. O /reports continua alterando suas próprias notificações de denúncias, e o /unapproveall só funciona no próprio grupo.</i>
 config-message =
     <b>Configurações —</b> Aqui estão minhas configurações para esse grupo.
     Para saber mais, <b>clique nos botões abaixo.</b>
+config-message-connected =
+    <b>Configurações —</b> Aqui estão minhas configurações para <b>{ $chatName }</b>.
+    Para saber mais, <b>clique nos botões abaixo.</b>
 config-medias =
     <b>Configurações do módulo de mídias:</b>
     Para saber mais sobre o módulo <b><i>mídias</i></b>, use /help no meu chat privado.
@@ -146,7 +186,7 @@ command-already-enabled = O comando <code>{ $command }</code> já estava ativado
 enable-commands-usage =
     Especifique o comando que você deseja ativar. Para ver quais os comandos que estão atualmente desativados, utilize /disabled.
 
-    <b>Uso:</b> <code>/enable (comando)</code>
+    <b>Uso:</b> <code>/enable (comandos)</code>, <code>/enable module (módulos)</code> ou <code>/enable all</code>
 no-disabled-commands = Não existem comandos desativados <b>neste grupo.</b>
 disabled-commands = <b>Comandos desativados:</b>
 disableables-commands = <b>Comandos desativáveis:</b>
@@ -155,7 +195,42 @@ command-disabled = O comando <code>{ $command }</code> foi desativado com sucess
 disable-commands-usage =
     Especifique o comando que você deseja desativar. Para ver a lista de comandos desativáveis, utilize /disableable.
 
-    <b>Uso:</b> <code>/disable (comando)</code>
+    <b>Uso:</b> <code>/disable (comandos)</code>, <code>/disable module (módulos)</code> ou <code>/disable all</code>
+command-restricted = O comando <code>{ $command }</code> agora pode ser usado <b>apenas por administradores.</b>
+command-already-restricted = O comando <code>{ $command }</code> já estava restrito a administradores.
+commands-restricted = Os comandos { $commands } agora podem ser usados <b>apenas por administradores.</b>
+commands-already-restricted = Os comandos { $commands } já estavam restritos a administradores.
+restrict-commands-usage =
+    Especifique o comando que você deseja restringir a administradores. Para ver a lista de comandos desativáveis, utilize /disableable.
+
+    <b>Uso:</b> <code>/restrict (comandos)</code>, <code>/restrict module (módulos)</code> ou <code>/restrict all</code>
+command-states-legend = <i>✅ Ativado · 🛡 Apenas administradores · 🚫 Desativado</i>
+topic-disabled-commands = <b>Desativados apenas neste tópico:</b>
+topic-scope-notice = <i>Isso vale apenas para este tópico.</i>
+topic-only = Use este comando dentro de um tópico do fórum.
+commands-disabled = Os comandos { $commands } foram desativados com sucesso.
+commands-already-disabled = Os comandos { $commands } já estavam desativados.
+commands-enabled = Os comandos { $commands } foram ativados com sucesso.
+commands-already-enabled = Os comandos { $commands } já estavam ativados.
+enable-all-success = { $count ->
+    [one] O único comando desativado foi ativado.
+   *[other] Todos os { $count } comandos desativados foram ativados.
+}
+disableables-hint = <i>Os comandos entre parênteses são apelidos, desativados junto com o comando. Use <code>/disable module (nome)</code> para desativar um módulo inteiro.</i>
+disabled-delete-notice = <i>As mensagens que usam esses comandos são apagadas.</i>
+module-not-found = Não há nenhum módulo chamado <code>{ $module }</code> com comandos desativáveis. Veja /disableable.
+disabledel-usage =
+    Especifique <code>on</code> ou <code>off</code>.
+
+    <b>Uso:</b> <code>/disabledel (on/off)</code>
+disabledel-status = As mensagens que usam um comando desativado { $enabled ->
+    [true] <b>são apagadas.</b>
+   *[false] <b>são ignoradas, mas não apagadas.</b>
+}
+disabledel-log = Apagar comandos desativados: { $enabled ->
+    [true] ✅
+   *[false] ☑️
+}
 command-not-deactivatable = O comando <code>{ $command }</code> <b>não pode ser desativado.</b>
 medias = Mídias
 medias-help =
@@ -295,6 +370,8 @@ lastfm-help =
     <b>/artist   | /art:</b> Exibe o artista que você está ouvindo ou ouviu recentemente.
 id-required = Você precisa responder a uma mensagem ou fornecer o ID do usuário.
 id-invalid = Não foi possível encontrar esse usuário. Responda a uma mensagem ou informe um ID válido.
+id-anonymous = Essa mensagem foi enviada por um administrador anônimo, que não pode ser alvo de ações.
+id-channel = Essa mensagem foi enviada em nome de um canal, não por um usuário.
 ban-success = O usuário <a>{ $userBannedFirstName }</a> foi banido permanentemente.
 unban-success = O usuário <a>{ $userUnbannedFirstName }</a> foi desbanido foi desbanido e pode voltar ao grupo.
 ban-failed = Não foi possível banir este usuário.
@@ -306,8 +383,58 @@ ban-success-temp = O usuário <a>{ $userBannedFirstName }</a> foi banido até <c
 delete-msg-id-required = Você precisa responder a mensagem que deseja deletar.
 delete-msg-failed = Não foi possível excluir a mensagem. Bots só podem deletar mensagens com até 48 horas de envio.
 delete-msg-success = Mensagem deletada com sucesso.
+purge-usage =
+    Responda à mensagem a partir da qual deseja apagar, ou informe quantas mensagens devem ser apagadas.
+
+    <b>Uso:</b> <code>/purge (quantidade)</code>
+purge-success = { $count ->
+    [one] Até <b>{ $count }</b> mensagem apagada.
+    *[other] Até <b>{ $count }</b> mensagens apagadas.
+}
+purge-partial = Algumas mensagens não puderam ser apagadas porque têm mais de 48 horas.
+pin-reply-required = Responda à mensagem que deseja fixar.
+pin-usage =
+    Responda à mensagem que deseja fixar. Use <code>loud</code> para notificar todos os membros ou <code>silent</code> para fixar silenciosamente.
+
+    <b>Uso:</b> <code>/pin (loud|silent)</code>
+pin-success = Mensagem fixada com sucesso.
+pin-failed = Não foi possível fixar esta mensagem.
+unpin-success = Mensagem desafixada com sucesso.
+unpin-failed = Não foi possível desafixar a mensagem.
+unpinall-confirm = Tem certeza de que deseja <b>desafixar todas as mensagens</b> deste grupo?
+unpinall-yes-button = ✅ Sim
+unpinall-no-button = ❌ Não
+unpinall-success = Todas as mensagens foram desafixadas.
+promote-success = O usuário <a>{ $userFirstName }</a> foi promovido.
+fullpromote-success = O usuário <a>{ $userFirstName }</a> foi promovido com todas as permissões.
+promote-failed = Não foi possível promover este usuário. Só posso promover membros e administradores promovidos por mim.
+demote-success = O usuário <a>{ $userFirstName }</a> foi rebaixado.
+demote-failed = Não foi possível rebaixar este usuário. Só posso rebaixar administradores promovidos por mim.
+admin-outranked = Você não pode alterar as permissões de um administrador que tem permissões que você não tem.
+title-usage =
+    Responda a um administrador ou informe o ID dele, seguido do novo título.
+
+    <b>Uso:</b> <code>/title (ID) (texto)</code>
+title-too-long = O título pode ter no máximo <b>{ $max }</b> caracteres.
+title-success = Título de <a>{ $userFirstName }</a> definido como <b>{ $title }</b>.
+title-failed = Não foi possível definir o título. Só posso alterar títulos de administradores promovidos por mim.
+no-pinned-message = Não há mensagem fixada <b>neste grupo.</b>
+pinned-message = <b>Clique no botão abaixo para ver a mensagem fixada.</b>
+pinned-message-button = 📌 Mensagem fixada
 bot-not-admin = Preciso ser administrador para executar este comando.
+anonymous-admin-prompt = Você está enviando este comando como administrador anônimo. Pressione o botão abaixo para confirmá-lo e executá-lo como você mesmo.
+anonymous-admin-button = Sou administrador
+anonymous-admin-expired = Esta confirmação expirou. Envie o comando novamente.
 user-not-admin = Você não tem permissão para executar este comando.
+user-missing-right = Você precisa da permissão de administrador <b>{ $right }</b> para executar este comando.
+bot-missing-right = Preciso da permissão de administrador <b>{ $right }</b> para executar este comando.
+right-pin-messages = Fixar mensagens
+right-promote-members = Adicionar novos administradores
+right-change-info = Alterar informações do grupo
+right-delete-messages = Apagar mensagens
+right-invite-users = Convidar usuários via link
+right-manage-topics = Gerenciar tópicos
+right-restrict-members = Banir usuários
 device-usage-hint = Para pesquisar você precisa fornecer um nome, codinome ou modelo do dispositivo.
 device-not-found = Nenhum dispositivo encontrado com o termo <code>{ $searchTerm }</code>.
 device-search-error = Não foi possível buscar informações sobre esse dispositivo. Por favor, tente novamente mais tarde.
@@ -328,4 +455,734 @@ android-help = <b>Android:</b>
     <b> - nome:</b> Nome comercial (ex: <i>Redmi Note 11</i>).
     <b> - codinome:</b> Codenome do dispositivo (ex: <i>spes</i>).
     <b> - modelo:</b> Modelo do dispositivo (ex: <i>2201117TG</i>).
-group-only = Esse comando só pode ser usado em grupos.
\ No newline at end of file
+group-only = Esse comando só pode ser usado em grupos.
+locks = Bloqueios
+locks-help =
+    <b>Bloqueios</b>
+
+    Os bloqueios permitem proibir alguns tipos de conteúdo no grupo sem silenciar todos.
+    Mensagens com conteúdo bloqueado enviadas por não administradores são <b>apagadas automaticamente.</b>
+
+    <b>— Comandos:</b>
+    <b>/lock (tipos):</b> Bloqueia um ou mais tipos de conteúdo. Use <code>all</code> para bloquear tudo.
+    <b>/unlock (tipos):</b> Desbloqueia um ou mais tipos de conteúdo.
+    <b>/locks:</b> Mostra os bloqueios atuais do grupo.
+
+    <b>Nota:</b>
+    Você também pode gerenciar os bloqueios pelo menu <code>/config</code>.
+config-locks =
+    <b>Configurações de bloqueios:</b>
+    Mensagens com conteúdo bloqueado enviadas por não administradores serão apagadas.
+
+    <b>Clique em um tipo de conteúdo para bloqueá-lo ou desbloqueá-lo.</b>
+locks-list = <b>Bloqueios deste grupo:</b>
+lock-usage =
+    Especifique o que você deseja bloquear.
+    <b>Tipos disponíveis:</b> { $types }
+
+    <b>Uso:</b> <code>/lock (tipos)</code>
+unlock-usage =
+    Especifique o que você deseja desbloquear.
+    <b>Tipos disponíveis:</b> { $types }
+
+    <b>Uso:</b> <code>/unlock (tipos)</code>
+lock-invalid-type =
+    <code>{ $type }</code> não é um tipo de bloqueio válido.
+    <b>Tipos disponíveis:</b> { $types }
+lock-success = Bloqueado: { $types }
+unlock-success = Desbloqueado: { $types }
+lock-links = Links
+lock-forwards = Encaminhadas
+lock-stickers = Stickers
+lock-gifs = GIFs
+lock-photos = Fotos
+lock-videos = Vídeos
+lock-documents = Documentos
+lock-audios = Áudios
+lock-voice = Mensagens de voz
+lock-videonotes = Vídeos redondos
+lock-polls = Enquetes
+lock-inline = Bots inline
+lock-contacts = Contatos
+lock-location = Localizações
+lock-games = Jogos
+lock-dice = Dados
+blocklist = Lista negra
+blocklist-help =
+    <b>Lista negra</b>
+
+    A lista negra age automaticamente sobre mensagens que contêm palavras ou padrões proibidos.
+    Mensagens de administradores nunca são afetadas.
+
+    <b>— Comandos:</b>
+    <b>/addblocklist (gatilho) (ação) (duração):</b> Adiciona um gatilho. Use aspas para gatilhos com várias palavras.
+    <b>/rmblocklist (gatilho):</b> Remove um gatilho.
+    <b>/blocklist:</b> Lista os gatilhos do grupo.
+    <b>/blocklistmode (ação) (duração):</b> Define a ação padrão do grupo.
+
+    <b>— Gatilhos:</b>
+    <b> - palavra:</b> Corresponde à palavra inteira, ex: <code>spam</code>.
+    <b> - curinga:</b> <code>*</code> corresponde a quaisquer caracteres, ex: <code>*coin</code>.
+    <b> - regex:</b> Use o prefixo <code>re:</code>, ex: <code>re:t\.me/\w+</code>.
+
+    <b>— Ações:</b> <code>delete</code>, <code>mute</code>, <code>tban</code> e <code>ban</code>.
+    Só <code>mute</code> e <code>tban</code> aceitam uma duração (ex: 1h, 2d).
+addblocklist-usage =
+    Especifique o gatilho que deseja adicionar.
+
+    <b>Uso:</b> <code>/addblocklist (gatilho) (ação) (duração)</code>
+rmblocklist-usage =
+    Especifique o gatilho que deseja remover. Para ver os gatilhos, use /blocklist.
+
+    <b>Uso:</b> <code>/rmblocklist (gatilho)</code>
+blocklistmode-usage =
+    Especifique uma ação válida: <code>delete</code>, <code>mute</code>, <code>tban</code> ou <code>ban</code>.
+    <code>tban</code> exige uma duração, e só <code>mute</code> e <code>tban</code> aceitam uma.
+
+    <b>Uso:</b> <code>/blocklistmode (ação) (duração)</code>
+blocklistmode-changed = A ação padrão da lista negra agora é <code>{ $action }</code>.
+blocklist-invalid-regex = Essa expressão regular é <b>inválida.</b>
+blocklist-invalid-action = Ação inválida. Use <code>delete</code>, <code>mute</code>, <code>tban</code> ou <code>ban</code>; <code>tban</code> exige uma duração, e só <code>mute</code> e <code>tban</code> aceitam uma.
+blocklist-added = Gatilho <code>{ $pattern }</code> adicionado à lista negra.
+blocklist-removed = Gatilho <code>{ $pattern }</code> removido da lista negra.
+blocklist-not-found = O gatilho <code>{ $pattern }</code> não está na lista negra.
+blocklist-empty = Não há gatilhos na lista negra <b>deste grupo.</b>
+blocklist-list = <b>Gatilhos da lista negra</b> (ação padrão: <code>{ $action }</code>):
+blocklist-action-mute = { $user } foi silenciado por usar uma palavra proibida.
+blocklist-action-mute-temp = { $user } foi silenciado até <code>{ $untilDate }</code> por usar uma palavra proibida.
+blocklist-action-ban = { $user } foi banido por usar uma palavra proibida.
+blocklist-action-ban-temp = { $user } foi banido até <code>{ $untilDate }</code> por usar uma palavra proibida.
+greetings = Saudações
+greetings-help =
+    <b>Saudações</b>
+
+    Cumprimente os membros quando entrarem no grupo e despeça-se quando saírem.
+
+    <b>— Comandos:</b>
+    <b>/welcome (on/off):</b> Ativa ou desativa a mensagem de boas-vindas. Sem argumentos, mostra as configurações atuais e uma prévia.
+    <b>/setwelcome (texto):</b> Define a mensagem de boas-vindas. Responda a uma mensagem para usá-la, incluindo fotos, GIFs e figurinhas.
+    <b>/resetwelcome:</b> Restaura a mensagem de boas-vindas padrão.
+    <b>/goodbye (on/off):</b> Ativa ou desativa a mensagem de despedida.
+    <b>/setgoodbye (texto):</b> Define a mensagem de despedida.
+    <b>/resetgoodbye:</b> Restaura a mensagem de despedida padrão.
+    <b>/cleanwelcome (on/off):</b> Apaga a mensagem de boas-vindas anterior quando uma nova é enviada.
+
+    <b>— Variáveis:</b>
+    <code>{"{first}"}</code>, <code>{"{last}"}</code>, <code>{"{fullname}"}</code>, <code>{"{username}"}</code>, <code>{"{mention}"}</code>, <code>{"{id}"}</code>, <code>{"{chatname}"}</code>, <code>{"{count}"}</code> e <code>{"{rules}"}</code> (adiciona um botão para as regras).
+
+    <b>— Botões:</b>
+    <code>[texto](buttonurl://exemplo.com)</code> adiciona um botão, e <code>[texto](buttonurl://exemplo.com:same)</code> o coloca na mesma linha do anterior.
+greeting-welcome = mensagem de boas-vindas
+greeting-goodbye = mensagem de despedida
+greeting-saved = A { $type } foi <b>salva.</b>
+greeting-reset = A { $type } foi <b>restaurada</b> para a padrão.
+greeting-enabled = A { $type } agora está <b>ativada.</b>
+greeting-disabled = A { $type } agora está <b>desativada.</b>
+greeting-status =
+    <b>{ $type } atual:</b>
+    Ativada: { $enabled ->
+        [true] ✅
+       *[false] ❌
+    }
+    Apagar anterior: { $clean ->
+        [true] ✅
+       *[false] ❌
+    }
+greeting-rules-button = 📜 Regras
+welcome-default = Olá { "{mention}" }, seja bem-vindo(a) ao <b>{ "{chatname}" }</b>!
+goodbye-default = { "{first}" } saiu do grupo.
+welcome-usage =
+    Especifique <code>on</code> ou <code>off</code>.
+
+    <b>Uso:</b> <code>/welcome (on/off)</code>
+goodbye-usage =
+    Especifique <code>on</code> ou <code>off</code>.
+
+    <b>Uso:</b> <code>/goodbye (on/off)</code>
+setwelcome-usage =
+    Responda a uma mensagem ou escreva a mensagem de boas-vindas após o comando.
+
+    <b>Uso:</b> <code>/setwelcome (texto)</code>
+setgoodbye-usage =
+    Responda a uma mensagem ou escreva a mensagem de despedida após o comando.
+
+    <b>Uso:</b> <code>/setgoodbye (texto)</code>
+cleanwelcome-usage =
+    Especifique <code>on</code> ou <code>off</code>.
+
+    <b>Uso:</b> <code>/cleanwelcome (on/off)</code>
+cleanwelcome-enabled = A mensagem de boas-vindas anterior agora será <b>apagada</b> quando alguém entrar.
+cleanwelcome-disabled = As mensagens de boas-vindas anteriores <b>não serão mais apagadas.</b>
+captcha = Captcha
+captcha-help =
+    <b>Captcha</b>
+
+    Novos membros são silenciados até provarem que são humanos resolvendo um desafio. Quem não o resolver a tempo é removido.
+    Membros adicionados por administradores não são desafiados.
+
+    <b>— Comandos:</b>
+    <b>/captcha (on/off):</b> Ativa ou desativa o captcha. Sem argumentos, mostra as configurações atuais.
+    <b>/captchamode (modo):</b> Define o desafio: <code>button</code>, <code>math</code> ou <code>emoji</code>.
+    <b>/captchatime (duração):</b> Define quanto tempo os novos membros têm para resolver o desafio, entre 30s e 1d (ex: 5m, 1h).
+captcha-usage =
+    Especifique <code>on</code> ou <code>off</code>.
+
+    <b>Uso:</b> <code>/captcha (on/off)</code>
+captchamode-usage =
+    Especifique um modo válido: <code>button</code>, <code>math</code> ou <code>emoji</code>.
+
+    <b>Uso:</b> <code>/captchamode (modo)</code>
+captchatime-usage =
+    Especifique uma duração entre 30s e 1d.
+
+    <b>Uso:</b> <code>/captchatime (duração)</code>
+captcha-enabled = O captcha agora está <b>ativado.</b> Novos membros terão que resolver um desafio antes de falar.
+captcha-disabled = O captcha agora está <b>desativado.</b>
+captchamode-changed = O modo do captcha agora é <code>{ $mode }</code>.
+captchatime-changed = Novos membros agora têm <b>{ $timeout }</b> para resolver o captcha.
+captcha-status =
+    <b>Configurações do captcha:</b>
+    Ativado: { $enabled ->
+        [true] ✅
+       *[false] ❌
+    }
+    Modo: <code>{ $mode }</code>
+    Tempo limite: { $timeout }
+captcha-verify-button = ✅ Não sou um robô
+captcha-button = Bem-vindo(a), <a href='tg://user?id={ $userID }'>{ $userFirstName }</a>! Pressione o botão abaixo em até <b>{ $timeout }</b> para provar que você é humano.
+captcha-math = Bem-vindo(a), <a href='tg://user?id={ $userID }'>{ $userFirstName }</a>! Resolva <b>{ $question }</b> em até <b>{ $timeout }</b> para provar que você é humano.
+captcha-emoji = Bem-vindo(a), <a href='tg://user?id={ $userID }'>{ $userFirstName }</a>! Selecione o { $question } em até <b>{ $timeout }</b> para provar que você é humano.
+captcha-solved = Verificado! Agora você pode falar no grupo.
+captcha-wrong = { $attempts ->
+    [one] Resposta errada. Você tem mais 1 tentativa.
+   *[other] Resposta errada. Você tem mais { $attempts } tentativas.
+}
+captcha-failed = Resposta errada. Você foi removido do grupo.
+captcha-expired = Este captcha não é mais válido.
+notes = Notas
+notes-help =
+    <b>Notas</b>
+
+    Salve mensagens para recuperá-las depois, como regras e links úteis.
+
+    <b>— Comandos:</b>
+    <b>/save (nome) (texto):</b> Salva uma nota. Responda a uma mensagem para salvá-la, incluindo mídia e formatação.
+    <b>/get (nome):</b> Envia uma nota. Responda a uma mensagem para enviar a nota como resposta a ela.
+    <b>#nome:</b> O mesmo que <code>/get nome</code>.
+    <b>/notes:</b> Lista as notas do grupo.
+    <b>/clear (nome):</b> Apaga uma nota.
+
+    <b>— Formatação:</b>
+    As notas aceitam as mesmas variáveis e botões da mensagem de boas-vindas, ex: <code>{"{first}"}</code> e <code>[texto](buttonurl://exemplo.com)</code>.
+    Adicione <code>{"{private}"}</code> a uma nota para enviá-la no privado em vez de no grupo.
+get-usage =
+    Especifique a nota que deseja obter.
+
+    <b>Uso:</b> <code>/get (nome)</code>
+save-usage =
+    Especifique o nome e o conteúdo da nota, ou responda a uma mensagem.
+
+    <b>Uso:</b> <code>/save (nome) (texto)</code>
+clear-usage =
+    Especifique a nota que deseja apagar.
+
+    <b>Uso:</b> <code>/clear (nome)</code>
+note-invalid-name = Os nomes das notas só podem conter <b>letras, números, - e _.</b>
+note-saved = Nota <code>#{ $name }</code> <b>salva.</b>
+note-deleted = Nota <code>#{ $name }</code> <b>apagada.</b>
+note-not-found = Não há nenhuma nota chamada <code>#{ $name }</code>.
+notes-empty = Não há notas <b>neste grupo.</b>
+notes-list = <b>Notas deste grupo:</b>
+note-sent-private = Enviei a nota para você no privado.
+note-private-start = Esta nota é privada. Inicie uma conversa comigo para que eu possa enviá-la.
+filters = Filtros
+filters-help =
+    <b>Filtros</b>
+
+    Filtros fazem o bot responder automaticamente quando uma mensagem contém um gatilho.
+
+    <b>— Comandos:</b>
+    <b>/filter (gatilho) (texto):</b> Adiciona um filtro. Responda a uma mensagem para usá-la como resposta, incluindo figurinhas e mídias.
+    <b>/stop (gatilho):</b> Remove um filtro.
+    <b>/filters:</b> Lista os filtros do grupo.
+
+    <b>— Gatilhos:</b>
+    Use aspas para gatilhos com várias palavras, ex: <code>/filter "bom dia" Olá!</code>
+    Por padrão, o gatilho pode estar em qualquer parte da mensagem. Use um prefixo para mudar como ele corresponde:
+    <b> - exact:</b> A mensagem inteira deve ser o gatilho, ex: <code>exact:oi</code>.
+    <b> - prefix:</b> A mensagem deve começar com o gatilho, ex: <code>prefix:!ajuda</code>.
+    <b> - re:</b> O gatilho é uma expressão regular, ex: <code>re:^(oi|olá)$</code>.
+
+    As respostas aceitam as mesmas variáveis e botões da mensagem de boas-vindas, ex: <code>{"{first}"}</code> e <code>[texto](buttonurl://exemplo.com)</code>.
+    Use <code>/disable filters</code> para impedir que todos os filtros respondam.
+filter-usage =
+    Especifique o gatilho e a resposta, ou responda a uma mensagem.
+
+    <b>Uso:</b> <code>/filter (gatilho) (texto)</code>
+stop-usage =
+    Especifique o filtro que deseja remover. Para ver os filtros, use /filters.
+
+    <b>Uso:</b> <code>/stop (gatilho)</code>
+filter-invalid-regex = Essa expressão regular é <b>inválida.</b>
+filter-saved = Filtro <code>{ $trigger }</code> ({ $mode }) <b>salvo.</b>
+filter-deleted = Filtro <code>{ $trigger }</code> <b>removido.</b>
+filter-not-found = Não há nenhum filtro para <code>{ $trigger }</code>.
+filters-empty = Não há filtros <b>neste grupo.</b>
+filters-list = <b>Filtros deste grupo:</b>
+rules = Regras
+rules-help =
+    <b>Regras</b>
+
+    Mantenha as regras do grupo a um comando de distância. Nos grupos, as regras são enviadas no privado para não poluir o chat.
+
+    <b>— Comandos:</b>
+    <b>/rules:</b> Mostra as regras do grupo.
+    <b>/setrules (texto):</b> Define as regras. Responda a uma mensagem para usá-la, mantendo sua formatação.
+    <b>/clearrules:</b> Apaga as regras.
+setrules-usage =
+    Escreva as regras após o comando ou responda a uma mensagem que as contenha.
+
+    <b>Uso:</b> <code>/setrules (texto)</code>
+rules-saved = As regras do grupo foram <b>salvas.</b>
+rules-cleared = As regras do grupo foram <b>apagadas.</b>
+rules-empty = Este grupo ainda não definiu nenhuma regra.
+rules-group = Clique no botão abaixo para ler as regras do grupo.
+rules-button = 📜 Regras
+rules-private = <b>Regras de { $chatName }:</b>
+reports = Denúncias
+reports-help =
+    <b>Denúncias</b>
+
+    Os membros podem denunciar uma mensagem aos administradores respondendo a ela com /report ou mencionando @admin.
+    Os administradores são notificados com botões para tomar uma ação. Para evitar abusos, cada membro só pode denunciar uma vez a cada alguns minutos.
+
+    <b>— Comandos:</b>
+    <b>/report (motivo):</b> Denuncia a mensagem respondida aos administradores.
+    <b>/reports (on/off):</b> Nos grupos, ativa ou desativa as denúncias. No privado, escolhe se você recebe as denúncias dos grupos que administra.
+report-usage = Responda à mensagem que deseja denunciar.
+report-not-allowed = Você não pode denunciar esta mensagem.
+report-cooldown = Você denunciou uma mensagem recentemente. <b>Aguarde antes de denunciar novamente.</b>
+report-already = Esta mensagem já foi denunciada aos administradores.
+report-sent = { $reporter } denunciou { $user } aos administradores.
+report-reason = <b>Motivo:</b> { $reason }
+report-private =
+    { $reporter } denunciou { $user } em <b>{ $chatName }</b>.
+    <a href='{ $link }'>Ir para a mensagem</a>
+report-ban-button = 🔨 Banir
+report-mute-button = 🔇 Silenciar
+report-delete-button = 🗑 Apagar
+report-ignore-button = ✖️ Ignorar
+report-handled = { $action ->
+    [ban] <a href='tg://user?id={ $userID }'>O usuário denunciado</a> foi <b>banido</b> por { $admin }.
+    [mute] <a href='tg://user?id={ $userID }'>O usuário denunciado</a> foi <b>silenciado</b> por { $admin }.
+    [delete] A mensagem denunciada foi <b>apagada</b> por { $admin }.
+   *[ignore] A denúncia foi <b>descartada</b> por { $admin }.
+}
+report-action-failed = Não consegui fazer isso. Verifique se tenho as permissões necessárias.
+reports-usage =
+    Especifique <code>on</code> ou <code>off</code>.
+
+    <b>Uso:</b> <code>/reports (on/off)</code>
+reports-group-status = As denúncias neste grupo estão { $enabled ->
+    [true] <b>ativadas.</b>
+   *[false] <b>desativadas.</b>
+}
+reports-private-status = { $enabled ->
+    [true] Você <b>receberá</b> no privado as denúncias dos grupos que administra.
+   *[false] Você <b>não receberá</b> no privado as denúncias dos grupos que administra.
+}
+setlog-usage =
+    Informe qual canal usar.
+
+    <b>Uso:</b> <code>/setlog (ID|@username)</code>, ou responda a uma mensagem encaminhada do canal.
+setlog-not-channel = Esse chat não é um canal.
+setlog-bot-cant-post = Preciso ser administrador com permissão para publicar mensagens nesse canal.
+setlog-user-not-admin = Você precisa ser administrador desse canal para usá-lo como canal de registros.
+setlog-success = Os eventos de moderação agora serão enviados para <b>{ $channelName }</b>.
+log-channel-linked = Este canal agora receberá os registros de moderação de <b>{ $chatName }</b>.
+unsetlog-success = O canal de registros foi desvinculado.
+log-channel-none = Este grupo não tem canal de registros.
+log-channel-current = O canal de registros é <b>{ $channelName }</b> (<code>{ $channelID }</code>).
+log-action-ban = 🔨 <b>#BAN</b>
+log-action-unban = 🔓 <b>#UNBAN</b>
+log-action-mute = 🔇 <b>#MUTE</b>
+log-action-unmute = 🔊 <b>#UNMUTE</b>
+log-action-delete = 🗑 <b>#DELETE</b>
+log-action-promote = ⭐️ <b>#PROMOTE</b>
+log-action-fullpromote = 🌟 <b>#FULLPROMOTE</b>
+log-action-demote = ⬇️ <b>#DEMOTE</b>
+log-action-config = ⚙️ <b>#CONFIG</b>
+log-action-language = 🌐 <b>#LANGUAGE</b>
+log-action-disable = 🚫 <b>#DISABLE</b>
+log-action-restrict = 🛡 <b>#RESTRICT</b>
+log-action-joinapprove = ✅ <b>#JOINAPPROVE</b>
+log-action-joindecline = ⛔️ <b>#JOINDECLINE</b>
+log-action-import = 📥 <b>#IMPORT</b>
+log-action-newtopic = 💬 <b>#NEWTOPIC</b>
+log-action-closetopic = 🔒 <b>#CLOSETOPIC</b>
+log-action-reopentopic = 🔓 <b>#REOPENTOPIC</b>
+log-action-renametopic = ✏️ <b>#RENAMETOPIC</b>
+log-action-enable = ✅ <b>#ENABLE</b>
+log-action-join = 👋 <b>#JOIN</b>
+log-action-leave = 🚪 <b>#LEAVE</b>
+log-chat = <b>Chat:</b> { $chatName } (<code>{ $chatID }</code>)
+log-admin = <b>Admin:</b> { $admin } (<code>{ $id }</code>)
+log-user = <b>Usuário:</b> { $user } (<code>{ $id }</code>)
+log-details = <b>Alteração:</b> { $details }
+log-reason = <b>Motivo:</b> { $reason }
+log-until = <b>Até:</b> <code>{ $untilDate }</code>
+log-link = <a href='{ $link }'>Ir para a mensagem</a>
+log-action-gban = 🌐 <b>#GBAN</b>
+gban-enforced = <a href='tg://user?id={ $userID }'>{ $userFirstName }</a> está banido globalmente e foi removido deste grupo.
+gban-reason = <b>Motivo:</b> { $reason }
+gban-protected = Este usuário não pode ser banido globalmente.
+gban-success = O usuário <code>{ $userID }</code> foi banido globalmente. { $count ->
+    [one] Banido de <b>1</b> grupo.
+   *[other] Banido de <b>{ $count }</b> grupos.
+}
+gban-not-found = Este usuário não está banido globalmente.
+ungban-success = O usuário <code>{ $userID }</code> foi removido da lista global de banimentos. { $count ->
+    [one] Desbanido de <b>1</b> grupo.
+   *[other] Desbanido de <b>{ $count }</b> grupos.
+}
+gbanstat-usage =
+    Especifique <code>on</code> ou <code>off</code>.
+
+    <b>Uso:</b> <code>/gbanstat (on/off)</code>
+gbanstat-status = Os banimentos globais neste grupo estão { $enabled ->
+    [true] <b>ativados.</b>
+   *[false] <b>desativados.</b>
+} A lista global tem <b>{ $count }</b> usuários.
+gbanexport-caption = Lista global de banimentos com <b>{ $count }</b> usuários.
+gbanimport-usage = Responda a um arquivo JSON exportado com /gbanexport.
+gbanimport-invalid = Este arquivo não é uma lista global de banimentos válida.
+gbanimport-success = { $count ->
+    [one] <b>1</b> banimento global importado.
+   *[other] <b>{ $count }</b> banimentos globais importados.
+}
+sudo-added = O usuário <code>{ $userID }</code> agora é um usuário sudo.
+sudo-already = O usuário <code>{ $userID }</code> já é um usuário sudo.
+sudo-removed = O usuário <code>{ $userID }</code> não é mais um usuário sudo.
+sudo-not-found = O usuário <code>{ $userID }</code> não é um usuário sudo.
+sudoers-empty = Não há usuários sudo.
+sudoers-list = <b>Usuários sudo:</b>
+log-action-approve = ✅ <b>#APPROVE</b>
+log-action-unapprove = ❎ <b>#UNAPPROVE</b>
+log-action-unapproveall = ❎ <b>#UNAPPROVEALL</b>
+user-not-owner = Apenas o dono do grupo pode fazer isso.
+approve-admin = Administradores já são isentos da moderação automática.
+approve-success = O usuário <a>{ $userFirstName }</a> foi aprovado e não será afetado pela moderação automática.
+approve-unchanged = O usuário <a>{ $userFirstName }</a> já está aprovado.
+unapprove-success = O usuário <a>{ $userFirstName }</a> não está mais aprovado.
+unapprove-unchanged = O usuário <a>{ $userFirstName }</a> não está aprovado.
+approved-empty = Não há usuários aprovados neste grupo.
+approved-list = <b>Usuários aprovados:</b>
+unapproveall-confirm = Tem certeza de que deseja remover todas as aprovações deste grupo?
+unapproveall-yes-button = ✅ Sim
+unapproveall-no-button = ❌ Não
+unapproveall-success = { $count ->
+    [one] <b>1</b> usuário não está mais aprovado.
+   *[other] <b>{ $count }</b> usuários não estão mais aprovados.
+}
+antiraid = Anti-raid
+antiraid-help =
+    <b>Anti-raid</b>
+
+    Enquanto o modo anti-raid estiver ativo, todo novo membro é removido assim que entra. Ele se desativa sozinho após a duração configurada.
+    Também pode ser ativado automaticamente quando muitos usuários entram em um minuto.
+
+    <b>— Comandos:</b>
+    <b>/antiraid (tempo|on/off):</b> Alterna o modo anti-raid. Informe um tempo (ex: 30m, 3h) para ativá-lo por esse período.
+    <b>/raidtime (tempo):</b> Define quanto tempo o modo anti-raid dura por padrão, entre 1m e 7d.
+    <b>/raidaction (kick/ban):</b> Define o que acontece com quem entra durante um raid.
+    <b>/autoantiraid (quantidade/off):</b> Ativa o modo anti-raid automaticamente quando <i>quantidade</i> usuários entram em um minuto.
+antiraid-usage =
+    Especifique <code>on</code>, <code>off</code> ou um tempo entre 1m e 7d.
+
+    <b>Uso:</b> <code>/antiraid (tempo|on/off)</code>
+antiraid-enabled = 🛡 O modo anti-raid está <b>ativado</b> por <b>{ $duration }</b>. Novos membros serão { $action ->
+    [ban] <b>banidos.</b>
+   *[kick] <b>expulsos.</b>
+}
+antiraid-disabled = O modo anti-raid está <b>desativado</b>.
+antiraid-triggered = 🚨 <b>Raid detectado!</b> Pelo menos { $threshold } usuários entraram em um minuto, então o modo anti-raid está <b>ativado</b> por <b>{ $duration }</b>. Novos membros serão { $action ->
+    [ban] <b>banidos.</b>
+   *[kick] <b>expulsos.</b>
+}
+antiraid-ended = O modo anti-raid terminou. Novos membros podem entrar novamente.
+antiraid-log-on = Ativado
+antiraid-log-off = Desativado
+antiraid-log-auto = Ativado automaticamente após { $threshold } entradas em um minuto
+log-action-antiraid = 🛡 <b>#ANTIRAID</b>
+raidtime-usage =
+    Especifique um tempo entre 1m e 7d.
+
+    <b>Uso:</b> <code>/raidtime (tempo)</code>
+raidtime-current = O modo anti-raid dura <b>{ $duration }</b> por padrão.
+raidtime-set = O modo anti-raid agora durará <b>{ $duration }</b> por padrão.
+raidaction-usage =
+    Especifique <code>kick</code> ou <code>ban</code>.
+
+    <b>Uso:</b> <code>/raidaction (kick/ban)</code>
+raidaction-current = Quem entra durante um raid é { $action ->
+    [ban] <b>banido.</b>
+   *[kick] <b>expulso.</b>
+}
+raidaction-set = Quem entrar durante um raid agora será { $action ->
+    [ban] <b>banido.</b>
+   *[kick] <b>expulso.</b>
+}
+autoantiraid-usage =
+    Especifique quantas entradas em um minuto ativam o modo anti-raid, no mínimo 2, ou <code>off</code>.
+
+    <b>Uso:</b> <code>/autoantiraid (quantidade/off)</code>
+autoantiraid-current = { $threshold ->
+    [0] O modo anti-raid <b>não</b> será ativado automaticamente.
+   *[other] O modo anti-raid é ativado automaticamente quando <b>{ $threshold }</b> usuários entram em um minuto.
+}
+nightmode = Modo noturno
+nightmode-help =
+    <b>Modo noturno</b>
+
+    Deixa o grupo somente leitura durante os horários configurados. Quando a noite termina, as permissões que o grupo tinha antes são restauradas.
+
+    <b>— Comandos:</b>
+    <b>/nightmode (on/off):</b> Ativa ou desativa o modo noturno. Sem argumentos, mostra as configurações atuais.
+    <b>/nighttime (início) (fim):</b> Define quando a noite começa e termina, ex: <code>/nighttime 23:00 07:00</code>.
+    <b>/nighttz (fuso):</b> Define o fuso horário do agendamento, ex: <code>America/Sao_Paulo</code>.
+
+    <b>Observação:</b>
+    Você também pode gerenciar o modo noturno pelo menu <code>/config</code>.
+nightmode-usage =
+    Especifique <code>on</code> ou <code>off</code>.
+
+    <b>Uso:</b> <code>/nightmode (on/off)</code>
+nightmode-status =
+    O modo noturno está { $enabled ->
+        [true] <b>ativado.</b>
+       *[false] <b>desativado.</b>
+    }
+    <b>Início:</b> <code>{ $start }</code>
+    <b>Fim:</b> <code>{ $end }</code>
+    <b>Fuso horário:</b> <code>{ $timezone }</code>
+nighttime-usage =
+    Especifique dois horários diferentes no formato <code>HH:MM</code>.
+
+    <b>Uso:</b> <code>/nighttime (início) (fim)</code>
+nighttime-set = O modo noturno agora vai de <code>{ $start }</code> até <code>{ $end }</code>.
+nighttz-usage =
+    Especifique um fuso horário IANA.
+
+    <b>Uso:</b> <code>/nighttz (fuso)</code>, ex: <code>/nighttz America/Sao_Paulo</code>
+nighttz-invalid = <code>{ $timezone }</code> não é um fuso horário válido. Use um nome IANA, ex: <code>America/Sao_Paulo</code>.
+nighttz-set = O fuso horário do modo noturno agora é <code>{ $timezone }</code>, onde são <code>{ $time }</code>.
+nightmode-started = 🌙 <b>O modo noturno está ativo.</b> O grupo fica somente leitura até <code>{ $end }</code>.
+nightmode-ended = ☀️ <b>Bom dia!</b> O modo noturno acabou e todos podem enviar mensagens novamente até <code>{ $start }</code>.
+config-nightmode =
+    <b>Configurações do modo noturno:</b>
+    O grupo fica somente leitura entre os horários de início e fim.
+
+    <b>Use ➖ e ➕ para mover os horários em 30 minutos.</b>
+nightmode-button = Modo noturno
+nightmode-start-button = Início: { $time }
+nightmode-end-button = Fim: { $time }
+nightmode-time-alert = Use ➖ e ➕ para mover este horário, ou /nighttime para defini-lo com precisão.
+nightmode-timezone-alert = Use /nighttz seguido de um fuso horário IANA, como America/Sao_Paulo, para alterá-lo.
+connect-group = Clique no botão abaixo para gerenciar este grupo pelo meu chat privado.
+connect-button = Conectar no privado
+connect-usage = Use <code>/connect (ID do chat ou @username)</code> para gerenciar daqui um grupo que você administra.
+connect-current = Você está conectado a <b>{ $chatName }</b>.
+connect-recent = <b>Conexões recentes:</b>
+connect-success =
+    Agora você está conectado a <b>{ $chatName }</b>.
+    Use /config, /disable, /enable e /disabled aqui para gerenciá-lo, e /disconnect quando terminar.
+connect-not-found = Não consegui encontrar esse chat. Verifique se eu sou membro dele.
+connect-not-group = Você só pode se conectar a grupos.
+connect-not-admin = Você precisa ser administrador desse grupo para se conectar a ele.
+connect-failed = Não consegui conectar você a esse grupo, tente novamente mais tarde.
+disconnect-success = Você foi desconectado.
+disconnect-none = Você não está conectado a nenhum grupo.
+joinrequests = Pedidos de entrada
+joinrequests-help =
+    <b>Pedidos de entrada</b>
+
+    Quando o grupo exige a aprovação de um admin para entrar, posso cuidar dos pedidos para você. Cada pedido é enviado ao canal de logs, ou ao grupo se não houver um, com botões para aprová-lo ou recusá-lo.
+
+    <b>— Comandos:</b>
+    <b>/joinrequests (on/off):</b> Ativa ou desativa o tratamento dos pedidos de entrada. Sem argumentos, mostra as configurações atuais.
+    <b>/joinrequests username (on/off):</b> Aprova na hora usuários com nome de usuário.
+    <b>/joinrequests gban (on/off):</b> Recusa na hora usuários banidos globalmente.
+    <b>/joinrequests captcha (on/off):</b> Envia ao solicitante o captcha do grupo no privado e o aprova assim que ele resolver.
+
+    <b>Nota:</b>
+    Usuários que não podem receber mensagens no privado ficam para a revisão dos admins.
+joinrequests-usage =
+    Especifique <code>on</code> ou <code>off</code>, opcionalmente depois de <code>username</code>, <code>gban</code> ou <code>captcha</code>.
+
+    <b>Uso:</b> <code>/joinrequests [username|gban|captcha] (on/off)</code>
+joinrequests-status =
+    Os pedidos de entrada { $enabled ->
+        [true] <b>são tratados por mim.</b>
+       *[false] <b>não são tratados por mim.</b>
+    }
+    <b>Aprovar usuários com nome de usuário:</b> { $username ->
+        [true] sim
+       *[false] não
+    }
+    <b>Recusar usuários banidos globalmente:</b> { $gban ->
+        [true] sim
+       *[false] não
+    }
+    <b>Captcha:</b> { $captcha ->
+        [true] sim
+       *[false] não
+    }
+    <b>As revisões são enviadas para:</b> { $logChannel ->
+        [true] o canal de logs
+       *[false] este grupo
+    }
+joinrequest-new = 📨 <a href='tg://user?id={ $userID }'>{ $userFirstName }</a> (<code>{ $userID }</code>) pediu para entrar em <b>{ $chatName }</b>.
+joinrequest-username = <b>Nome de usuário:</b> @{ $username }
+joinrequest-bio = <b>Bio:</b> { $bio }
+joinrequest-approve-button = ✅ Aprovar
+joinrequest-decline-button = ⛔️ Recusar
+joinrequest-approved = <a href='tg://user?id={ $adminID }'>{ $adminFirstName }</a> aprovou a entrada de <a href='tg://user?id={ $userID }'>{ $userFirstName }</a> em <b>{ $chatName }</b>.
+joinrequest-declined = <a href='tg://user?id={ $adminID }'>{ $adminFirstName }</a> recusou o pedido de <a href='tg://user?id={ $userID }'>{ $userFirstName }</a> para entrar em <b>{ $chatName }</b>.
+joinrequest-gone = Este pedido já foi tratado.
+joinrequest-captcha-button = Você pediu para entrar em <b>{ $chatName }</b>. Pressione o botão abaixo em até <b>{ $timeout }</b> para provar que é humano.
+joinrequest-captcha-math = Você pediu para entrar em <b>{ $chatName }</b>. Resolva <b>{ $question }</b> em até <b>{ $timeout }</b> para provar que é humano.
+joinrequest-captcha-emoji = Você pediu para entrar em <b>{ $chatName }</b>. Selecione o { $question } em até <b>{ $timeout }</b> para provar que é humano.
+joinrequest-captcha-solved = ✅ Pronto! Seu pedido para entrar em <b>{ $chatName }</b> foi aprovado.
+joinrequest-captcha-failed = ⛔️ Você errou o captcha, então seu pedido para entrar em <b>{ $chatName }</b> foi recusado.
+joinrequest-captcha-expired = ⌛️ Você não resolveu o captcha a tempo, então seu pedido para entrar em <b>{ $chatName }</b> foi recusado.
+joinrequest-reason-gban = Banido globalmente
+joinrequest-reason-username = Tem nome de usuário
+joinrequest-reason-captcha-solved = Resolveu o captcha
+joinrequest-reason-captcha-failed = Errou o captcha
+joinrequest-reason-captcha-expired = Não resolveu o captcha a tempo
+cleanup = Limpeza
+cleanup-help =
+    <b>Limpeza</b>
+
+    Mantém o grupo organizado apagando mensagens de serviço, os comandos enviados a mim e minhas próprias respostas.
+
+    <b>— Comandos:</b>
+    <b>/cleanservice (tipo/all) (on/off):</b> Apaga as mensagens de serviço de um tipo. Sem argumentos, mostra quais tipos são apagados.
+    <b>/cleancommands (on/off):</b> Apaga os comandos enviados a mim depois de tratados.
+    <b>/cleanreplies (duração/off):</b> Apaga minhas respostas depois de um tempo, entre 5s e 1d.
+
+    <b>Tipos de mensagens de serviço:</b>
+    <code>join</code>, <code>leave</code>, <code>pin</code>, <code>title</code>, <code>photo</code>, <code>videochat</code>, <code>topics</code> e <code>other</code>.
+
+    <b>Nota:</b>
+    Você também pode gerenciar essas configurações pelo menu <code>/config</code>. Preciso da permissão de apagar mensagens.
+cleanservice-usage =
+    Especifique um tipo de mensagem de serviço, ou <code>all</code>, seguido de <code>on</code> ou <code>off</code>.
+
+    <b>Uso:</b> <code>/cleanservice (tipo/all) (on/off)</code>
+cleanservice-status = <b>Mensagens de serviço apagadas neste grupo:</b>
+cleanservice-join = Novos membros
+cleanservice-leave = Membros saindo
+cleanservice-pin = Mensagens fixadas
+cleanservice-title = Mudanças de título
+cleanservice-photo = Mudanças de foto
+cleanservice-videochat = Chamadas de vídeo
+cleanservice-topics = Tópicos
+cleanservice-other = Outras
+cleancommands-usage =
+    Especifique <code>on</code> ou <code>off</code>.
+
+    <b>Uso:</b> <code>/cleancommands (on/off)</code>
+cleancommands-status = Os comandos enviados a mim { $enabled ->
+    [true] <b>são apagados</b> depois de tratados.
+   *[false] <b>são mantidos.</b>
+}
+cleancommands-button = Apagar comandos
+cleanreplies-usage =
+    Especifique uma duração entre 5s e 1d, ou <code>off</code>.
+
+    <b>Uso:</b> <code>/cleanreplies (duração/off)</code>
+cleanreplies-status = Minhas respostas são apagadas depois de: <b>{ $delay }</b>
+cleanreplies-never = nunca
+cleanreplies-button = Apagar respostas: { $delay }
+cleanreplies-alert = Use ➖ e ➕ para mudar esse tempo, ou /cleanreplies para defini-lo com precisão.
+config-cleanup =
+    <b>Configurações de limpeza:</b>
+    Escolha as mensagens de serviço a apagar, se os comandos enviados a mim são apagados e depois de quanto tempo minhas respostas são apagadas.
+
+    <b>Use ➖ e ➕ para mudar o tempo.</b>
+export-no-group = Use este comando em um grupo, ou use /connect para se conectar a um antes.
+export-caption = Configurações de <b>{ $chatName }</b>. Responda a este arquivo com /import em outro grupo para copiá-las para lá.
+import-usage = Responda a um arquivo gerado pelo /export para copiar as configurações dele para este grupo.
+import-invalid = Este arquivo não é uma exportação de configurações válida.
+import-unsupported = Este arquivo foi gerado por uma versão mais nova do bot (formato { $version }), então não consigo importá-lo.
+import-success =
+    As configurações de <b>{ $chatName }</b> foram substituídas pelas do arquivo.
+    <b>Importado:</b> { $sections }
+topics = Tópicos
+topics-help =
+    <b>Tópicos</b>
+
+    Gerencia os tópicos de grupos com tópicos ativados. Cada comando age no tópico em que é enviado, incluindo o Geral.
+
+    <b>— Comandos:</b>
+    <b>/newtopic (nome):</b> Cria um tópico.
+    <b>/renametopic (nome):</b> Renomeia o tópico atual.
+    <b>/closetopic:</b> Fecha o tópico atual, para que apenas administradores possam postar nele.
+    <b>/reopentopic:</b> Reabre o tópico atual.
+    <b>/topicmedias (on/off/default):</b> Escolhe se os links enviados no tópico atual são baixados automaticamente, independente da configuração do grupo.
+
+    <b>Nota:</b>
+    Comandos também podem ser desativados em um único tópico, com <code>/disable topic (comandos)</code>. Gerenciar tópicos exige a permissão de gerenciá-los, tanto sua quanto minha.
+topics-forum-only = Este grupo não tem os tópicos ativados.
+topics-failed = Não consegui fazer isso. Verifique se posso gerenciar tópicos aqui.
+newtopic-usage =
+    Especifique o nome do tópico, com até 128 caracteres.
+
+    <b>Uso:</b> <code>/newtopic (nome)</code>
+newtopic-success = O tópico <b>{ $name }</b> foi criado.
+newtopic-welcome = Bem-vindos ao <b>{ $name }</b>!
+closetopic-success = Este tópico agora está <b>fechado.</b>
+reopentopic-success = Este tópico agora está <b>aberto.</b>
+renametopic-usage =
+    Especifique o novo nome do tópico, com até 128 caracteres.
+
+    <b>Uso:</b> <code>/renametopic (nome)</code>
+renametopic-success = Este tópico agora se chama <b>{ $name }</b>.
+topicmedias-usage =
+    Especifique <code>on</code>, <code>off</code> ou <code>default</code>, para seguir a configuração do grupo.
+
+    <b>Uso:</b> <code>/topicmedias (on/off/default)</code>
+topicmedias-status = Os links enviados neste tópico { $state ->
+    [true] <b>são baixados</b> automaticamente.
+    [false] <b>não são baixados</b> automaticamente.
+   *[default] seguem a <b>configuração do grupo</b> para downloads automáticos.
+}
+topicmedias-log = Downloads automáticos do tópico: { $state ->
+    [true] ✅
+    [false] ☑️
+   *[default] —
+}
+edits = Edições
+edits-help =
+    <b>Edições</b>
+
+    Trata mensagens editadas como novas, para que corrigir um erro de digitação em um comando ou adicionar um link a uma mensagem ainda receba resposta.
+
+    <b>— Comandos:</b>
+    <b>/edits (on/off):</b> Escolhe se as mensagens editadas são tratadas. Sem argumentos, mostra a configuração atual.
+
+    <b>Nota:</b>
+    Só o que a edição adiciona é tratado: um comando que eu ainda não respondi ou novos links de mídia, para que mídias que já enviei não sejam baixadas de novo. Os bloqueios e a lista negra ainda verificam a mensagem editada inteira. Mensagens com mais de dois dias são ignoradas. Em grupos, apenas administradores podem alterar isso.
+edits-usage =
+    Especifique <code>on</code> ou <code>off</code>.
+
+    <b>Uso:</b> <code>/edits (on/off)</code>
+edits-status = Mensagens editadas { $enabled ->
+    [true] <b>são tratadas</b> como novas.
+   *[false] <b>são ignoradas.</b>
+}
+edits-log = Tratar mensagens editadas: { $enabled ->
+    [true] ✅
+   *[false] ☑️
+}
@@ -33,8 +33,13 @@ func CheckAFKMiddleware(next bot.HandlerFunc) bot.HandlerFunc {
 
 		mentionedUserID := getUserIDFromMessage(message)
 
-		userID := message.From.ID
-		userAFK := user_is_away(userID)
+		// Messages sent on behalf of a chat come from a placeholder bot
+		// shared by every chat, not from an AFK user.
+		var userID int64
+		if message.SenderChat == nil {
+			userID = message.From.ID
+		}
+		userAFK := userID != 0 && user_is_away(userID)
 		mentionedAFK := user_is_away(mentionedUserID)
 
 		if !userAFK && !mentionedAFK {
@@ -129,7 +134,7 @@ func handleMentionedAFK(ctx context.Context, b *bot.Bot, update *models.Update,
 }
 
 func getUserIDFromMessage(message *models.Message) int64 {
-	if m := message.ReplyToMessage; m != nil && m.From != nil {
+	if m := message.ReplyToMessage; m != nil && m.From != nil && m.SenderChat == nil {
 		return m.From.ID
 	}
 
@@ -153,6 +158,10 @@ func getUserIDFromMessage(message *models.Message) int64 {
 }
 
 func setAFKHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update.Message.SenderChat != nil {
+		return
+	}
+
 	reason := extractReason(update.Message.Text)
 	err := set_user_away(update.Message.From.ID, reason, time.Now().UTC())
 	if err != nil {
@@ -0,0 +1,65 @@
+package antiraid
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/angelomds42/EleineBot/internal/database"
+)
+
+type antiraidSettings struct {
+	Action    string
+	Duration  time.Duration
+	Threshold int
+	// Until is when the active anti-raid mode ends, zero when it's off.
+	Until time.Time
+}
+
+func (s antiraidSettings) active() bool {
+	return time.Now().Before(s.Until)
+}
+
+func getAntiraidSettings(chatID int64) (antiraidSettings, error) {
+	settings := antiraidSettings{Action: "kick", Duration: defaultDuration}
+	var duration, until int64
+	err := database.DB.QueryRow(
+		"SELECT action, duration, threshold, until FROM antiraidSettings WHERE chat_id = ?;", chatID,
+	).Scan(&settings.Action, &duration, &settings.Threshold, &until)
+	if err == sql.ErrNoRows {
+		return settings, nil
+	}
+	settings.Duration = time.Duration(duration) * time.Second
+	if until > 0 {
+		settings.Until = time.Unix(until, 0)
+	}
+	return settings, err
+}
+
+func setAntiraidOption(chatID int64, option string, value any) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO antiraidSettings (chat_id, `+option+`) VALUES (?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET `+option+` = excluded.`+option+`;
+	`, chatID, value)
+	return err
+}
+
+// getEndedAntiraids returns the chats whose anti-raid mode expired before now.
+func getEndedAntiraids(now time.Time) ([]int64, error) {
+	rows, err := database.DB.Query(
+		"SELECT chat_id FROM antiraidSettings WHERE until > 0 AND until <= ?;", now.Unix(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chats []int64
+	for rows.Next() {
+		var chatID int64
+		if err := rows.Scan(&chatID); err != nil {
+			return nil, err
+		}
+		chats = append(chats, chatID)
+	}
+	return chats, rows.Err()
+}
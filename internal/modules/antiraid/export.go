@@ -0,0 +1,50 @@
+package antiraid
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+type antiraidSection struct {
+	Action string `json:"action"`
+	// Duration is in seconds.
+	Duration  int64 `json:"duration"`
+	Threshold int   `json:"threshold"`
+}
+
+// exportAntiraid returns the anti-raid settings of chatID, without whether
+// the mode is active there.
+func exportAntiraid(chatID int64) (any, error) {
+	settings, err := getAntiraidSettings(chatID)
+	return antiraidSection{
+		Action:    settings.Action,
+		Duration:  int64(settings.Duration.Seconds()),
+		Threshold: settings.Threshold,
+	}, err
+}
+
+func importAntiraid(tx *sql.Tx, chatID int64, data json.RawMessage) error {
+	section := antiraidSection{Action: "kick", Duration: int64(defaultDuration.Seconds())}
+	if err := json.Unmarshal(data, &section); err != nil {
+		return err
+	}
+	if section.Action != "ban" {
+		section.Action = "kick"
+	}
+	if duration := time.Duration(section.Duration) * time.Second; duration < minDuration || duration > maxDuration {
+		section.Duration = int64(defaultDuration.Seconds())
+	}
+	if section.Threshold < 2 {
+		section.Threshold = 0
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO antiraidSettings (chat_id, action, duration, threshold) VALUES (?, ?, ?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET
+			action = excluded.action,
+			duration = excluded.duration,
+			threshold = excluded.threshold;
+	`, chatID, section.Action, section.Duration, section.Threshold)
+	return err
+}
@@ -0,0 +1,414 @@
+package antiraid
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/modules/moderation"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+const (
+	defaultDuration = 6 * time.Hour
+	minDuration     = time.Minute
+	maxDuration     = 7 * 24 * time.Hour
+	joinWindow      = time.Minute
+	expiryInterval  = 30 * time.Second
+)
+
+// recentJoins keeps the join times of each chat within the last joinWindow,
+// to find out when the joins per minute exceed the configured threshold.
+var (
+	recentJoins      = make(map[int64][]time.Time)
+	recentJoinsMutex sync.Mutex
+)
+
+// recordJoins adds count joins to chatID and reports whether the chat
+// reached threshold joins within the last minute.
+func recordJoins(chatID int64, count, threshold int) bool {
+	recentJoinsMutex.Lock()
+	defer recentJoinsMutex.Unlock()
+
+	now := time.Now()
+	joins := recentJoins[chatID][:0]
+	for _, joinedAt := range recentJoins[chatID] {
+		if now.Sub(joinedAt) < joinWindow {
+			joins = append(joins, joinedAt)
+		}
+	}
+	for range count {
+		joins = append(joins, now)
+	}
+
+	if len(joins) >= threshold {
+		delete(recentJoins, chatID)
+		return true
+	}
+	recentJoins[chatID] = joins
+	return false
+}
+
+// CheckAntiraidMiddleware removes every new member while the anti-raid mode
+// is active, and turns it on when too many users join at once.
+func CheckAntiraidMiddleware(next bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		message := update.Message
+		if message == nil || len(message.NewChatMembers) == 0 || message.Chat.Type == models.ChatTypePrivate {
+			next(ctx, b, update)
+			return
+		}
+
+		settings, err := getAntiraidSettings(message.Chat.ID)
+		if err != nil {
+			slog.Error("Couldn't get anti-raid settings",
+				"ChatID", message.Chat.ID,
+				"Error", err.Error())
+			next(ctx, b, update)
+			return
+		}
+
+		if !settings.active() && settings.Threshold == 0 {
+			next(ctx, b, update)
+			return
+		}
+
+		// Members added by an admin were already vetted by them, and don't
+		// count towards a raid either.
+		if message.From != nil && utils.IsChatAdmin(ctx, b, message.Chat.ID, message.From.ID) {
+			next(ctx, b, update)
+			return
+		}
+
+		if !settings.active() {
+			if !recordJoins(message.Chat.ID, len(message.NewChatMembers), settings.Threshold) {
+				next(ctx, b, update)
+				return
+			}
+			settings.Until = time.Now().Add(settings.Duration)
+			if err := setAntiraidOption(message.Chat.ID, "until", settings.Until.Unix()); err != nil {
+				slog.Error("Couldn't enable anti-raid",
+					"ChatID", message.Chat.ID,
+					"Error", err.Error())
+				next(ctx, b, update)
+				return
+			}
+			notifyRaid(ctx, b, message, settings)
+		}
+
+		removed := false
+		for _, member := range message.NewChatMembers {
+			if member.IsBot || moderation.IsApproved(message.Chat.ID, member.ID) {
+				continue
+			}
+			if removeRaider(ctx, b, message.Chat.ID, member.ID, settings.Action) {
+				removed = true
+			}
+		}
+
+		if !removed {
+			next(ctx, b, update)
+			return
+		}
+		b.DeleteMessage(ctx, &bot.DeleteMessageParams{ChatID: message.Chat.ID, MessageID: message.ID})
+	}
+}
+
+func removeRaider(ctx context.Context, b *bot.Bot, chatID, userID int64, action string) bool {
+	if _, err := b.BanChatMember(ctx, &bot.BanChatMemberParams{ChatID: chatID, UserID: userID}); err != nil {
+		slog.Error("Couldn't remove raider",
+			"ChatID", chatID,
+			"UserID", userID,
+			"Error", err.Error())
+		return false
+	}
+	if action == "kick" {
+		b.UnbanChatMember(ctx, &bot.UnbanChatMemberParams{ChatID: chatID, UserID: userID, OnlyIfBanned: true})
+	}
+	return true
+}
+
+// notifyRaid warns the chat that the anti-raid mode was turned on
+// automatically, tagging the admins with invisible mentions.
+func notifyRaid(ctx context.Context, b *bot.Bot, message *models.Message, settings antiraidSettings) {
+	i18n := localization.Get(&models.Update{Message: message})
+	update := &models.Update{Message: message}
+
+	var mentions strings.Builder
+	admins, err := b.GetChatAdministrators(ctx, &bot.GetChatAdministratorsParams{ChatID: message.Chat.ID})
+	if err != nil {
+		slog.Error("Couldn't get chat administrators",
+			"ChatID", message.Chat.ID,
+			"Error", err.Error())
+	}
+	for _, admin := range admins {
+		var user *models.User
+		switch admin.Type {
+		case models.ChatMemberTypeOwner:
+			user = admin.Owner.User
+		case models.ChatMemberTypeAdministrator:
+			user = &admin.Administrator.User
+		}
+		if user == nil || user.IsBot {
+			continue
+		}
+		fmt.Fprintf(&mentions, "<a href='tg://user?id=%d'>​</a>", user.ID)
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, 0, i18n("antiraid-triggered", map[string]any{
+		"threshold": settings.Threshold,
+		"duration":  localization.HumanizeTimeSince(settings.Duration, update),
+		"action":    settings.Action,
+	})+mentions.String())
+
+	moderation.SendLog(ctx, b, message.Chat, moderation.LogEntry{
+		Action:  "antiraid",
+		Details: i18n("antiraid-log-auto", map[string]any{"threshold": settings.Threshold}),
+		Until:   int(settings.Until.Unix()),
+	})
+}
+
+// endAntiraid turns off the anti-raid mode of a chat that ran for its whole
+// duration.
+func endAntiraid(ctx context.Context, b *bot.Bot, chatID int64) {
+	if err := setAntiraidOption(chatID, "until", 0); err != nil {
+		slog.Error("Couldn't disable anti-raid",
+			"ChatID", chatID,
+			"Error", err.Error())
+		return
+	}
+	i18n := localization.Get(&models.Update{Message: &models.Message{
+		Chat: models.Chat{ID: chatID, Type: models.ChatTypeSupergroup},
+	}})
+	utils.SendMessage(ctx, b, chatID, 0, i18n("antiraid-ended"))
+}
+
+// parseDuration reads a duration argument, keeping it within the allowed range.
+func parseDuration(arg string) (time.Duration, bool) {
+	duration, err := utils.ParseCustomDuration(arg)
+	if err != nil || duration < minDuration || duration > maxDuration {
+		return 0, false
+	}
+	return duration, true
+}
+
+func antiraidHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightRestrictMembers)
+	if !ok {
+		return
+	}
+
+	settings, err := getAntiraidSettings(chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get anti-raid settings",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	// Without arguments the command toggles the mode.
+	enable, duration := !settings.active(), settings.Duration
+	if fields := strings.Fields(message.Text); len(fields) > 1 {
+		var ok bool
+		if enable, ok = utils.ParseSwitch(fields[1]); !ok {
+			if duration, ok = parseDuration(fields[1]); !ok {
+				utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("antiraid-usage"))
+				return
+			}
+			enable = true
+		}
+	}
+
+	if enable && !moderation.CheckChatBotRight(ctx, b, message, chat, moderation.RightRestrictMembers) {
+		return
+	}
+
+	var until int64
+	if enable {
+		until = time.Now().Add(duration).Unix()
+	}
+	if err := setAntiraidOption(chat.ID, "until", until); err != nil {
+		slog.Error("Couldn't update anti-raid",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	if !enable {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("antiraid-disabled"))
+		moderation.SendLog(ctx, b, chat, moderation.LogEntry{
+			Action:  "antiraid",
+			Actor:   message.From,
+			Details: i18n("antiraid-log-off"),
+			Message: message,
+		})
+		return
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("antiraid-enabled", map[string]any{
+		"duration": localization.HumanizeTimeSince(duration, update),
+		"action":   settings.Action,
+	}))
+	moderation.SendLog(ctx, b, chat, moderation.LogEntry{
+		Action:  "antiraid",
+		Actor:   message.From,
+		Details: i18n("antiraid-log-on"),
+		Until:   int(until),
+		Message: message,
+	})
+}
+
+func raidTimeHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightRestrictMembers)
+	if !ok {
+		return
+	}
+
+	fields := strings.Fields(message.Text)
+	if len(fields) < 2 {
+		settings, err := getAntiraidSettings(chat.ID)
+		if err != nil {
+			slog.Error("Couldn't get anti-raid settings",
+				"ChatID", chat.ID,
+				"Error", err.Error())
+			return
+		}
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("raidtime-current", map[string]any{
+			"duration": localization.HumanizeTimeSince(settings.Duration, update),
+		}))
+		return
+	}
+
+	duration, ok := parseDuration(fields[1])
+	if !ok {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("raidtime-usage"))
+		return
+	}
+
+	if err := setAntiraidOption(chat.ID, "duration", int64(duration.Seconds())); err != nil {
+		slog.Error("Couldn't set anti-raid duration",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("raidtime-set", map[string]any{
+		"duration": localization.HumanizeTimeSince(duration, update),
+	}))
+}
+
+func raidActionHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightRestrictMembers)
+	if !ok {
+		return
+	}
+
+	fields := strings.Fields(message.Text)
+	if len(fields) < 2 {
+		settings, err := getAntiraidSettings(chat.ID)
+		if err != nil {
+			slog.Error("Couldn't get anti-raid settings",
+				"ChatID", chat.ID,
+				"Error", err.Error())
+			return
+		}
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("raidaction-current", map[string]any{
+			"action": settings.Action,
+		}))
+		return
+	}
+
+	action := strings.ToLower(fields[1])
+	if action != "kick" && action != "ban" {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("raidaction-usage"))
+		return
+	}
+
+	if err := setAntiraidOption(chat.ID, "action", action); err != nil {
+		slog.Error("Couldn't set anti-raid action",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("raidaction-set", map[string]any{
+		"action": action,
+	}))
+}
+
+func autoAntiraidHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightRestrictMembers)
+	if !ok {
+		return
+	}
+
+	fields := strings.Fields(message.Text)
+	if len(fields) < 2 {
+		settings, err := getAntiraidSettings(chat.ID)
+		if err != nil {
+			slog.Error("Couldn't get anti-raid settings",
+				"ChatID", chat.ID,
+				"Error", err.Error())
+			return
+		}
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("autoantiraid-current", map[string]any{
+			"threshold": settings.Threshold,
+		}))
+		return
+	}
+
+	var threshold int
+	switch arg := strings.ToLower(fields[1]); arg {
+	case "off", "no", "false", "0":
+	default:
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 2 {
+			utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("autoantiraid-usage"))
+			return
+		}
+		threshold = n
+	}
+
+	if err := setAntiraidOption(chat.ID, "threshold", threshold); err != nil {
+		slog.Error("Couldn't set anti-raid threshold",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("autoantiraid-current", map[string]any{
+		"threshold": threshold,
+	}))
+}
+
+func Load(b *bot.Bot) {
+	b.RegisterHandler(bot.HandlerTypeMessageText, "antiraid", bot.MatchTypeCommand, antiraidHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "raidtime", bot.MatchTypeCommand, raidTimeHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "raidaction", bot.MatchTypeCommand, raidActionHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "autoantiraid", bot.MatchTypeCommand, autoAntiraidHandler)
+
+	utils.Schedule(b, "antiraid", expiryInterval, getEndedAntiraids, endAntiraid)
+
+	utils.RegisterChatSection("antiraid", utils.ChatSection{Export: exportAntiraid, Import: importAntiraid})
+	utils.SaveHelp("antiraid")
+}
@@ -0,0 +1,76 @@
+package blocklist
+
+import (
+	"database/sql"
+
+	"github.com/angelomds42/EleineBot/internal/database"
+)
+
+type blocklistEntry struct {
+	Pattern  string `json:"pattern"`
+	Kind     string `json:"kind"`
+	Action   string `json:"action,omitempty"`
+	Duration string `json:"duration,omitempty"`
+}
+
+func getBlocklist(chatID int64) ([]blocklistEntry, error) {
+	rows, err := database.DB.Query(
+		"SELECT pattern, kind, COALESCE(action, ''), COALESCE(duration, '') FROM blocklist WHERE chat_id = ? ORDER BY pattern;",
+		chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []blocklistEntry
+	for rows.Next() {
+		var entry blocklistEntry
+		if err := rows.Scan(&entry.Pattern, &entry.Kind, &entry.Action, &entry.Duration); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func insertBlocklist(chatID int64, entry blocklistEntry) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO blocklist (chat_id, pattern, kind, action, duration)
+		VALUES (?, ?, ?, NULLIF(?, ''), NULLIF(?, ''))
+		ON CONFLICT(chat_id, pattern) DO UPDATE SET
+			kind = excluded.kind,
+			action = excluded.action,
+			duration = excluded.duration;
+	`, chatID, entry.Pattern, entry.Kind, entry.Action, entry.Duration)
+	return err
+}
+
+func deleteBlocklist(chatID int64, pattern string) (bool, error) {
+	result, err := database.DB.Exec("DELETE FROM blocklist WHERE chat_id = ? AND pattern = ?;", chatID, pattern)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func getBlocklistMode(chatID int64) (action, duration string, err error) {
+	err = database.DB.QueryRow(
+		"SELECT action, COALESCE(duration, '') FROM blocklistSettings WHERE chat_id = ?;", chatID,
+	).Scan(&action, &duration)
+	if err == sql.ErrNoRows {
+		return "delete", "", nil
+	}
+	return action, duration, err
+}
+
+func setBlocklistMode(chatID int64, action, duration string) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO blocklistSettings (chat_id, action, duration)
+		VALUES (?, ?, NULLIF(?, ''))
+		ON CONFLICT(chat_id) DO UPDATE SET
+			action = excluded.action,
+			duration = excluded.duration;
+	`, chatID, action, duration)
+	return err
+}
@@ -0,0 +1,63 @@
+package blocklist
+
+import (
+	"database/sql"
+	"encoding/json"
+	"slices"
+
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+type blocklistSection struct {
+	Action   string           `json:"action"`
+	Duration string           `json:"duration,omitempty"`
+	Entries  []blocklistEntry `json:"entries"`
+}
+
+func exportBlocklist(chatID int64) (any, error) {
+	entries, err := getBlocklist(chatID)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []blocklistEntry{}
+	}
+
+	action, duration, err := getBlocklistMode(chatID)
+	return blocklistSection{Action: action, Duration: duration, Entries: entries}, err
+}
+
+func importBlocklist(tx *sql.Tx, chatID int64, data json.RawMessage) error {
+	section := blocklistSection{Action: "delete"}
+	if err := json.Unmarshal(data, &section); err != nil {
+		return err
+	}
+	if !slices.Contains(blocklistActions, section.Action) {
+		section.Action, section.Duration = "delete", ""
+	}
+	// An entry that doesn't compile would break the blocklist of the chat.
+	if _, err := utils.CompileMatcher(section.Entries, entryExpression); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM blocklist WHERE chat_id = ?;", chatID); err != nil {
+		return err
+	}
+	for _, entry := range section.Entries {
+		if entry.Pattern == "" || (entry.Action != "" && !slices.Contains(blocklistActions, entry.Action)) {
+			continue
+		}
+		if _, err := tx.Exec(`
+			INSERT OR REPLACE INTO blocklist (chat_id, pattern, kind, action, duration)
+			VALUES (?, ?, ?, NULLIF(?, ''), NULLIF(?, ''));
+		`, chatID, entry.Pattern, entry.Kind, entry.Action, entry.Duration); err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.Exec(`
+		INSERT OR REPLACE INTO blocklistSettings (chat_id, action, duration)
+		VALUES (?, ?, NULLIF(?, ''));
+	`, chatID, section.Action, section.Duration)
+	return err
+}
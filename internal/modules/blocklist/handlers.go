@@ -0,0 +1,380 @@
+package blocklist
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/modules/edits"
+	"github.com/angelomds42/EleineBot/internal/modules/moderation"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+const regexPrefix = "re:"
+
+var blocklistActions = []string{"delete", "mute", "tban", "ban"}
+
+var blocklistCache = utils.NewMatcherCache(getBlocklist, entryExpression)
+
+func entryExpression(entry blocklistEntry) string {
+	switch entry.Kind {
+	case "regex":
+		return entry.Pattern
+	case "wildcard":
+		return utils.WordExpression(strings.ReplaceAll(regexp.QuoteMeta(entry.Pattern), `\*`, `\S*`))
+	default:
+		return utils.WordExpression(regexp.QuoteMeta(entry.Pattern))
+	}
+}
+
+func CheckBlocklistMiddleware(next bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		message := update.Message
+		if edited := edits.EditedMessage(ctx); edited != nil {
+			message = edited
+		}
+		if message == nil || message.From == nil || message.Chat.Type == models.ChatTypePrivate {
+			next(ctx, b, update)
+			return
+		}
+
+		if message.IsAutomaticForward || (message.SenderChat != nil && message.SenderChat.ID == message.Chat.ID) {
+			next(ctx, b, update)
+			return
+		}
+
+		compiled, err := blocklistCache.Get(message.Chat.ID)
+		if err != nil {
+			slog.Error("Couldn't load blocklist",
+				"ChatID", message.Chat.ID,
+				"Error", err.Error())
+			next(ctx, b, update)
+			return
+		}
+
+		entry := compiled.Match(message.Text)
+		if entry == nil {
+			entry = compiled.Match(message.Caption)
+		}
+
+		if entry == nil || moderation.IsSenderExempt(ctx, b, message) {
+			next(ctx, b, update)
+			return
+		}
+
+		applyBlocklistAction(ctx, b, message, entry)
+	}
+}
+
+func applyBlocklistAction(ctx context.Context, b *bot.Bot, message *models.Message, entry *blocklistEntry) {
+	action, duration := entry.Action, entry.Duration
+	if action == "" {
+		var err error
+		action, duration, err = getBlocklistMode(message.Chat.ID)
+		if err != nil {
+			slog.Error("Couldn't get blocklist mode",
+				"ChatID", message.Chat.ID,
+				"Error", err.Error())
+			action = "delete"
+		}
+	}
+
+	if _, err := b.DeleteMessage(ctx, &bot.DeleteMessageParams{
+		ChatID:    message.Chat.ID,
+		MessageID: message.ID,
+	}); err != nil {
+		slog.Error("Couldn't delete blocklisted message",
+			"ChatID", message.Chat.ID,
+			"MessageID", message.ID,
+			"Error", err.Error())
+	}
+
+	var until int
+	if duration != "" {
+		if d, err := utils.ParseCustomDuration(duration); err == nil {
+			until = int(time.Now().Add(d).Unix())
+		}
+	}
+
+	// Channels can't be muted, and their bans are always permanent.
+	senderID, sender := message.From.ID, utils.MentionUser(message.From)
+	if message.SenderChat != nil {
+		if action == "mute" {
+			return
+		}
+		senderID, sender, until = message.SenderChat.ID, utils.EscapeHTML(message.SenderChat.Title), 0
+	}
+
+	var err error
+	switch action {
+	case "mute":
+		_, err = b.RestrictChatMember(ctx, &bot.RestrictChatMemberParams{
+			ChatID:      message.Chat.ID,
+			UserID:      senderID,
+			Permissions: &models.ChatPermissions{},
+			UntilDate:   until,
+		})
+	case "tban", "ban":
+		if message.SenderChat != nil {
+			_, err = b.BanChatSenderChat(ctx, &bot.BanChatSenderChatParams{
+				ChatID:       message.Chat.ID,
+				SenderChatID: int(senderID),
+			})
+			break
+		}
+		_, err = b.BanChatMember(ctx, &bot.BanChatMemberParams{
+			ChatID:    message.Chat.ID,
+			UserID:    senderID,
+			UntilDate: until,
+		})
+	default:
+		return
+	}
+	if err != nil {
+		slog.Error("Couldn't apply blocklist action",
+			"ChatID", message.Chat.ID,
+			"UserID", senderID,
+			"Action", action,
+			"Error", err.Error())
+		return
+	}
+
+	i18n := localization.Get(&models.Update{Message: message})
+	respData := map[string]any{"user": sender}
+	if action == "tban" {
+		action = "ban"
+	}
+	respKey := "blocklist-action-" + action
+	if until > 0 {
+		respKey += "-temp"
+		respData["untilDate"] = time.Unix(int64(until), 0).Format("02/01/2006 15:04")
+	}
+	utils.SendMessage(ctx, b, message.Chat.ID, 0, i18n(respKey, respData))
+}
+
+func parseAction(args []string) (action, duration string, ok bool) {
+	if len(args) == 0 {
+		return "", "", true
+	}
+
+	action = strings.ToLower(args[0])
+	if !slices.Contains(blocklistActions, action) {
+		return "", "", false
+	}
+
+	// Only mute and tban take a duration, which a ban would silently drop.
+	if len(args) > 2 || len(args) > 1 && action != "mute" && action != "tban" {
+		return "", "", false
+	}
+	if len(args) > 1 {
+		if _, err := utils.ParseCustomDuration(args[1]); err != nil {
+			return "", "", false
+		}
+		duration = args[1]
+	}
+
+	if action == "tban" && duration == "" {
+		return "", "", false
+	}
+	return action, duration, true
+}
+
+func parseEntry(pattern string) (blocklistEntry, error) {
+	if expression, ok := strings.CutPrefix(pattern, regexPrefix); ok {
+		if _, err := regexp.Compile(expression); err != nil || expression == "" {
+			return blocklistEntry{}, fmt.Errorf("invalid regex: %s", expression)
+		}
+		return blocklistEntry{Pattern: expression, Kind: "regex"}, nil
+	}
+
+	pattern = strings.ToLower(pattern)
+	if strings.Contains(pattern, "*") {
+		return blocklistEntry{Pattern: pattern, Kind: "wildcard"}, nil
+	}
+	return blocklistEntry{Pattern: pattern, Kind: "word"}, nil
+}
+
+// checkBlocklistRights returns the group a blocklist command acts on,
+// checking that both the user and the bot can delete messages there.
+func checkBlocklistRights(ctx context.Context, b *bot.Bot, message *models.Message) (models.Chat, bool) {
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightDeleteMessages)
+	return chat, ok && moderation.CheckChatBotRight(ctx, b, message, chat, moderation.RightDeleteMessages)
+}
+
+func addBlocklistHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := checkBlocklistRights(ctx, b, message)
+	if !ok {
+		return
+	}
+
+	args := utils.SplitArgs(message.Text)[1:]
+	if len(args) == 0 {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("addblocklist-usage"))
+		return
+	}
+
+	entry, err := parseEntry(args[0])
+	if err != nil {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("blocklist-invalid-regex"))
+		return
+	}
+
+	action, duration, ok := parseAction(args[1:])
+	if !ok {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("blocklist-invalid-action"))
+		return
+	}
+	entry.Action, entry.Duration = action, duration
+
+	if err := insertBlocklist(chat.ID, entry); err != nil {
+		slog.Error("Couldn't insert blocklist",
+			"ChatID", chat.ID,
+			"Pattern", entry.Pattern,
+			"Error", err.Error())
+		return
+	}
+	blocklistCache.Invalidate(chat.ID)
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID,
+		i18n("blocklist-added", map[string]any{"pattern": utils.EscapeHTML(entry.Pattern)}))
+}
+
+func rmBlocklistHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := checkBlocklistRights(ctx, b, message)
+	if !ok {
+		return
+	}
+
+	args := utils.SplitArgs(message.Text)[1:]
+	if len(args) == 0 {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("rmblocklist-usage"))
+		return
+	}
+
+	pattern := args[0]
+	if entry, err := parseEntry(pattern); err == nil {
+		pattern = entry.Pattern
+	}
+
+	removed, err := deleteBlocklist(chat.ID, pattern)
+	if err != nil {
+		slog.Error("Couldn't delete blocklist",
+			"ChatID", chat.ID,
+			"Pattern", pattern,
+			"Error", err.Error())
+		return
+	}
+
+	if !removed {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID,
+			i18n("blocklist-not-found", map[string]any{"pattern": utils.EscapeHTML(pattern)}))
+		return
+	}
+	blocklistCache.Invalidate(chat.ID)
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID,
+		i18n("blocklist-removed", map[string]any{"pattern": utils.EscapeHTML(pattern)}))
+}
+
+func formatAction(action, duration string) string {
+	if duration != "" {
+		return action + " " + duration
+	}
+	return action
+}
+
+func blocklistHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, "")
+	if !ok {
+		return
+	}
+
+	entries, err := getBlocklist(chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get blocklist",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	if len(entries) == 0 {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("blocklist-empty"))
+		return
+	}
+
+	action, duration, err := getBlocklistMode(chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get blocklist mode",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	text := i18n("blocklist-list", map[string]any{"action": formatAction(action, duration)})
+	for _, entry := range entries {
+		text += fmt.Sprintf("\n- <code>%s</code> (%s)", utils.EscapeHTML(entry.Pattern), entry.Kind)
+		if entry.Action != "" {
+			text += " → <i>" + formatAction(entry.Action, entry.Duration) + "</i>"
+		}
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, text)
+}
+
+func blocklistModeHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := checkBlocklistRights(ctx, b, message)
+	if !ok {
+		return
+	}
+
+	args := strings.Fields(message.Text)[1:]
+	action, duration, ok := parseAction(args)
+	if len(args) == 0 || !ok {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("blocklistmode-usage"))
+		return
+	}
+
+	if err := setBlocklistMode(chat.ID, action, duration); err != nil {
+		slog.Error("Couldn't set blocklist mode",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID,
+		i18n("blocklistmode-changed", map[string]any{"action": formatAction(action, duration)}))
+}
+
+func Load(b *bot.Bot) {
+	b.RegisterHandler(bot.HandlerTypeMessageText, "addblocklist", bot.MatchTypeCommand, addBlocklistHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "rmblocklist", bot.MatchTypeCommand, rmBlocklistHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "blocklist", bot.MatchTypeCommand, blocklistHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "blocklistmode", bot.MatchTypeCommand, blocklistModeHandler)
+
+	utils.RegisterChatSection("blocklist", utils.ChatSection{
+		Export:   exportBlocklist,
+		Import:   importBlocklist,
+		Imported: blocklistCache.Invalidate,
+	})
+	utils.SaveHelp("blocklist")
+}
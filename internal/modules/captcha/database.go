@@ -0,0 +1,105 @@
+package captcha
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/angelomds42/EleineBot/internal/database"
+)
+
+type captchaSettings struct {
+	Enabled bool
+	Mode    string
+	Timeout time.Duration
+}
+
+type pendingCaptcha struct {
+	ChatID    int64
+	UserID    int64
+	MessageID int
+	Answer    string
+	Attempts  int
+}
+
+func getCaptchaSettings(chatID int64) (captchaSettings, error) {
+	settings := captchaSettings{Mode: "button"}
+	var timeout int64
+	err := database.DB.QueryRow(
+		"SELECT enabled, mode, timeout FROM captchaSettings WHERE chat_id = ?;", chatID,
+	).Scan(&settings.Enabled, &settings.Mode, &timeout)
+	if err == sql.ErrNoRows {
+		settings.Timeout = defaultTimeout
+		return settings, nil
+	}
+	settings.Timeout = time.Duration(timeout) * time.Second
+	return settings, err
+}
+
+func setCaptchaOption(chatID int64, option string, value any) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO captchaSettings (chat_id, `+option+`) VALUES (?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET `+option+` = excluded.`+option+`;
+	`, chatID, value)
+	return err
+}
+
+func insertPendingCaptcha(pending pendingCaptcha, expiresAt time.Time) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO captchaPending (chat_id, user_id, message_id, answer, attempts, expires_at)
+		VALUES (?, ?, ?, ?, 0, ?)
+		ON CONFLICT(chat_id, user_id) DO UPDATE SET
+			message_id = excluded.message_id,
+			answer = excluded.answer,
+			attempts = 0,
+			expires_at = excluded.expires_at;
+	`, pending.ChatID, pending.UserID, pending.MessageID, pending.Answer, expiresAt.Unix())
+	return err
+}
+
+// getPendingCaptcha returns nil when the user has no pending captcha in the chat.
+func getPendingCaptcha(chatID, userID int64) (*pendingCaptcha, error) {
+	pending := pendingCaptcha{ChatID: chatID, UserID: userID}
+	err := database.DB.QueryRow(
+		"SELECT message_id, answer, attempts FROM captchaPending WHERE chat_id = ? AND user_id = ?;",
+		chatID, userID,
+	).Scan(&pending.MessageID, &pending.Answer, &pending.Attempts)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pending, nil
+}
+
+func incrementAttempts(chatID, userID int64) error {
+	_, err := database.DB.Exec(
+		"UPDATE captchaPending SET attempts = attempts + 1 WHERE chat_id = ? AND user_id = ?;",
+		chatID, userID)
+	return err
+}
+
+func deletePendingCaptcha(chatID, userID int64) error {
+	_, err := database.DB.Exec("DELETE FROM captchaPending WHERE chat_id = ? AND user_id = ?;", chatID, userID)
+	return err
+}
+
+func getExpiredCaptchas(now time.Time) ([]pendingCaptcha, error) {
+	rows, err := database.DB.Query(
+		"SELECT chat_id, user_id, COALESCE(message_id, 0), answer, attempts FROM captchaPending WHERE expires_at <= ?;",
+		now.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var expired []pendingCaptcha
+	for rows.Next() {
+		var pending pendingCaptcha
+		if err := rows.Scan(&pending.ChatID, &pending.UserID, &pending.MessageID, &pending.Answer, &pending.Attempts); err != nil {
+			return nil, err
+		}
+		expired = append(expired, pending)
+	}
+	return expired, rows.Err()
+}
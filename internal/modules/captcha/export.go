@@ -0,0 +1,44 @@
+package captcha
+
+import (
+	"database/sql"
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+type captchaSection struct {
+	Enabled bool   `json:"enabled"`
+	Mode    string `json:"mode"`
+	// Timeout is in seconds.
+	Timeout int64 `json:"timeout"`
+}
+
+// exportCaptcha returns the captcha settings of chatID. The pending
+// challenges belong to the chat and aren't exported.
+func exportCaptcha(chatID int64) (any, error) {
+	settings, err := getCaptchaSettings(chatID)
+	return captchaSection{
+		Enabled: settings.Enabled,
+		Mode:    settings.Mode,
+		Timeout: int64(settings.Timeout.Seconds()),
+	}, err
+}
+
+func importCaptcha(tx *sql.Tx, chatID int64, data json.RawMessage) error {
+	section := captchaSection{Mode: "button", Timeout: int64(defaultTimeout.Seconds())}
+	if err := json.Unmarshal(data, &section); err != nil {
+		return err
+	}
+	if !slices.Contains(captchaModes, section.Mode) {
+		section.Mode = "button"
+	}
+	if timeout := time.Duration(section.Timeout) * time.Second; timeout < minTimeout || timeout > maxTimeout {
+		section.Timeout = int64(defaultTimeout.Seconds())
+	}
+
+	_, err := tx.Exec(
+		"INSERT OR REPLACE INTO captchaSettings (chat_id, enabled, mode, timeout) VALUES (?, ?, ?, ?);",
+		chatID, section.Enabled, section.Mode, section.Timeout)
+	return err
+}
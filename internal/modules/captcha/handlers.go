@@ -0,0 +1,401 @@
+package captcha
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/modules/moderation"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+const (
+	defaultTimeout = 5 * time.Minute
+	minTimeout     = 30 * time.Second
+	maxTimeout     = 24 * time.Hour
+	maxAttempts    = 3
+	expiryInterval = 15 * time.Second
+)
+
+var (
+	captchaModes  = []string{"button", "math", "emoji"}
+	captchaEmojis = []string{"🍎", "🚗", "🐶", "⚽", "🎸", "🌙", "🍕", "🚀", "🐱", "🌵", "🎈", "📚"}
+)
+
+// NewChallenge builds a question of the given mode and the keyboard with the
+// possible answers, returning the correct one to be stored. Each button sends
+// callbackPrefix followed by the chosen answer.
+func NewChallenge(
+	i18n func(string, ...map[string]any) string,
+	mode string,
+	callbackPrefix string,
+) (question, answer string, markup *models.InlineKeyboardMarkup) {
+	callbackData := func(choice string) string {
+		return callbackPrefix + " " + choice
+	}
+
+	switch mode {
+	case "math":
+		a, b := rand.Intn(20)+1, rand.Intn(20)+1
+		result := a + b
+		options := []int{result}
+		for len(options) < 4 {
+			if option := result + rand.Intn(21) - 10; option > 0 && !slices.Contains(options, option) {
+				options = append(options, option)
+			}
+		}
+		rand.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+
+		row := make([]models.InlineKeyboardButton, 0, len(options))
+		for _, option := range options {
+			row = append(row, models.InlineKeyboardButton{Text: strconv.Itoa(option), CallbackData: callbackData(strconv.Itoa(option))})
+		}
+		return fmt.Sprintf("%d + %d", a, b), strconv.Itoa(result), &models.InlineKeyboardMarkup{
+			InlineKeyboard: [][]models.InlineKeyboardButton{row},
+		}
+	case "emoji":
+		indexes := rand.Perm(len(captchaEmojis))[:6]
+		correct := indexes[rand.Intn(len(indexes))]
+
+		rows := make([][]models.InlineKeyboardButton, 0, 2)
+		for i := 0; i < len(indexes); i += 3 {
+			row := make([]models.InlineKeyboardButton, 0, 3)
+			for _, index := range indexes[i : i+3] {
+				row = append(row, models.InlineKeyboardButton{Text: captchaEmojis[index], CallbackData: callbackData(strconv.Itoa(index))})
+			}
+			rows = append(rows, row)
+		}
+		return captchaEmojis[correct], strconv.Itoa(correct), &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+	}
+
+	return "", "ok", &models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{{
+		{Text: i18n("captcha-verify-button"), CallbackData: callbackData("ok")},
+	}}}
+}
+
+func startCaptcha(ctx context.Context, b *bot.Bot, update *models.Update, user models.User, settings captchaSettings) {
+	chatID := update.Message.Chat.ID
+	i18n := localization.Get(update)
+
+	if _, err := b.RestrictChatMember(ctx, &bot.RestrictChatMemberParams{
+		ChatID:      chatID,
+		UserID:      user.ID,
+		Permissions: &models.ChatPermissions{},
+	}); err != nil {
+		slog.Error("Couldn't restrict new member",
+			"ChatID", chatID,
+			"UserID", user.ID,
+			"Error", err.Error())
+		return
+	}
+
+	question, answer, markup := NewChallenge(i18n, settings.Mode, fmt.Sprintf("captcha %d", user.ID))
+	text := i18n("captcha-"+settings.Mode, map[string]any{
+		"userID":        strconv.FormatInt(user.ID, 10),
+		"userFirstName": utils.EscapeHTML(user.FirstName),
+		"question":      question,
+		"timeout":       localization.HumanizeTimeSince(settings.Timeout, update),
+	})
+
+	sent, err := utils.SendMessageWithResult(ctx, b, chatID, 0, text, utils.WithReplyMarkupSend(markup))
+	if err != nil {
+		return
+	}
+
+	if err := insertPendingCaptcha(pendingCaptcha{
+		ChatID:    chatID,
+		UserID:    user.ID,
+		MessageID: sent.ID,
+		Answer:    answer,
+	}, time.Now().Add(settings.Timeout)); err != nil {
+		slog.Error("Couldn't save pending captcha",
+			"ChatID", chatID,
+			"UserID", user.ID,
+			"Error", err.Error())
+	}
+}
+
+// ChatMode returns the captcha mode chosen in chatID.
+func ChatMode(chatID int64) string {
+	settings, err := getCaptchaSettings(chatID)
+	if err != nil {
+		slog.Error("Couldn't get captcha settings",
+			"ChatID", chatID,
+			"Error", err.Error())
+	}
+	return settings.Mode
+}
+
+func CheckCaptchaMiddleware(next bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		message := update.Message
+		if message == nil || message.Chat.Type == models.ChatTypePrivate {
+			next(ctx, b, update)
+			return
+		}
+
+		if message.LeftChatMember != nil {
+			if err := deletePendingCaptcha(message.Chat.ID, message.LeftChatMember.ID); err != nil {
+				slog.Error("Couldn't delete pending captcha",
+					"ChatID", message.Chat.ID,
+					"UserID", message.LeftChatMember.ID,
+					"Error", err.Error())
+			}
+		}
+
+		if len(message.NewChatMembers) == 0 {
+			next(ctx, b, update)
+			return
+		}
+
+		settings, err := getCaptchaSettings(message.Chat.ID)
+		if err != nil {
+			slog.Error("Couldn't get captcha settings",
+				"ChatID", message.Chat.ID,
+				"Error", err.Error())
+		}
+
+		if settings.Enabled {
+			// Members added by an admin were already vetted by them.
+			addedByAdmin := message.From != nil && moderation.IsAdmin(ctx, b, message.Chat.ID, message.From.ID)
+			for _, member := range message.NewChatMembers {
+				if member.IsBot || (addedByAdmin && member.ID != message.From.ID) ||
+					moderation.IsApproved(message.Chat.ID, member.ID) {
+					continue
+				}
+				startCaptcha(ctx, b, update, member, settings)
+			}
+		}
+
+		next(ctx, b, update)
+	}
+}
+
+func captchaCallback(ctx context.Context, b *bot.Bot, update *models.Update) {
+	i18n := localization.Get(update)
+	message := update.CallbackQuery.Message.Message
+
+	parts := strings.Fields(update.CallbackQuery.Data)
+	if len(parts) != 3 {
+		return
+	}
+	userID, _ := strconv.ParseInt(parts[1], 10, 64)
+	if update.CallbackQuery.From.ID != userID {
+		utils.SendCallbackReply(ctx, b, update.CallbackQuery.ID, i18n("denied-button-alert"))
+		return
+	}
+
+	pending, err := getPendingCaptcha(message.Chat.ID, userID)
+	if err != nil {
+		slog.Error("Couldn't get pending captcha",
+			"ChatID", message.Chat.ID,
+			"UserID", userID,
+			"Error", err.Error())
+		return
+	}
+	if pending == nil {
+		utils.SendCallbackReply(ctx, b, update.CallbackQuery.ID, i18n("captcha-expired"))
+		return
+	}
+
+	if parts[2] != pending.Answer {
+		if pending.Attempts+1 >= maxAttempts {
+			failCaptcha(ctx, b, *pending)
+			utils.SendCallbackReply(ctx, b, update.CallbackQuery.ID, i18n("captcha-failed"))
+			return
+		}
+		if err := incrementAttempts(message.Chat.ID, userID); err != nil {
+			slog.Error("Couldn't update captcha attempts",
+				"ChatID", message.Chat.ID,
+				"UserID", userID,
+				"Error", err.Error())
+		}
+		utils.SendCallbackReply(ctx, b, update.CallbackQuery.ID, i18n("captcha-wrong", map[string]any{
+			"attempts": maxAttempts - pending.Attempts - 1,
+		}))
+		return
+	}
+
+	chat, err := b.GetChat(ctx, &bot.GetChatParams{ChatID: message.Chat.ID})
+	if err != nil {
+		slog.Error("Couldn't get chat permissions",
+			"ChatID", message.Chat.ID,
+			"Error", err.Error())
+		return
+	}
+	if _, err := b.RestrictChatMember(ctx, &bot.RestrictChatMemberParams{
+		ChatID:      message.Chat.ID,
+		UserID:      userID,
+		Permissions: chat.Permissions,
+	}); err != nil {
+		slog.Error("Couldn't unrestrict member",
+			"ChatID", message.Chat.ID,
+			"UserID", userID,
+			"Error", err.Error())
+		return
+	}
+
+	if err := deletePendingCaptcha(message.Chat.ID, userID); err != nil {
+		slog.Error("Couldn't delete pending captcha",
+			"ChatID", message.Chat.ID,
+			"UserID", userID,
+			"Error", err.Error())
+	}
+	b.DeleteMessage(ctx, &bot.DeleteMessageParams{ChatID: message.Chat.ID, MessageID: message.ID})
+	b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
+		CallbackQueryID: update.CallbackQuery.ID,
+		Text:            i18n("captcha-solved"),
+	})
+}
+
+// failCaptcha kicks the user, leaving them free to join again later.
+func failCaptcha(ctx context.Context, b *bot.Bot, pending pendingCaptcha) {
+	if _, err := b.BanChatMember(ctx, &bot.BanChatMemberParams{ChatID: pending.ChatID, UserID: pending.UserID}); err != nil {
+		slog.Error("Couldn't kick captcha user",
+			"ChatID", pending.ChatID,
+			"UserID", pending.UserID,
+			"Error", err.Error())
+	} else {
+		b.UnbanChatMember(ctx, &bot.UnbanChatMemberParams{ChatID: pending.ChatID, UserID: pending.UserID, OnlyIfBanned: true})
+	}
+
+	if pending.MessageID != 0 {
+		b.DeleteMessage(ctx, &bot.DeleteMessageParams{ChatID: pending.ChatID, MessageID: pending.MessageID})
+	}
+	if err := deletePendingCaptcha(pending.ChatID, pending.UserID); err != nil {
+		slog.Error("Couldn't delete pending captcha",
+			"ChatID", pending.ChatID,
+			"UserID", pending.UserID,
+			"Error", err.Error())
+	}
+}
+
+func captchaHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightRestrictMembers)
+	if !ok {
+		return
+	}
+
+	fields := strings.Fields(message.Text)
+	if len(fields) < 2 {
+		settings, err := getCaptchaSettings(chat.ID)
+		if err != nil {
+			slog.Error("Couldn't get captcha settings",
+				"ChatID", chat.ID,
+				"Error", err.Error())
+			return
+		}
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("captcha-status", map[string]any{
+			"enabled": strconv.FormatBool(settings.Enabled),
+			"mode":    settings.Mode,
+			"timeout": localization.HumanizeTimeSince(settings.Timeout, update),
+		}))
+		return
+	}
+
+	enabled, ok := utils.ParseSwitch(fields[1])
+	if !ok {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("captcha-usage"))
+		return
+	}
+
+	if enabled && !moderation.CheckChatBotRight(ctx, b, message, chat, moderation.RightRestrictMembers) {
+		return
+	}
+
+	if err := setCaptchaOption(chat.ID, "enabled", enabled); err != nil {
+		slog.Error("Couldn't toggle captcha",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	respKey := "captcha-disabled"
+	if enabled {
+		respKey = "captcha-enabled"
+	}
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n(respKey))
+}
+
+func captchaModeHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightRestrictMembers)
+	if !ok {
+		return
+	}
+
+	fields := strings.Fields(message.Text)
+	if len(fields) < 2 || !slices.Contains(captchaModes, strings.ToLower(fields[1])) {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("captchamode-usage"))
+		return
+	}
+
+	mode := strings.ToLower(fields[1])
+	if err := setCaptchaOption(chat.ID, "mode", mode); err != nil {
+		slog.Error("Couldn't set captcha mode",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("captchamode-changed", map[string]any{"mode": mode}))
+}
+
+func captchaTimeHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightRestrictMembers)
+	if !ok {
+		return
+	}
+
+	fields := strings.Fields(message.Text)
+	if len(fields) < 2 {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("captchatime-usage"))
+		return
+	}
+
+	timeout, err := utils.ParseCustomDuration(fields[1])
+	if err != nil || timeout < minTimeout || timeout > maxTimeout {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("captchatime-usage"))
+		return
+	}
+
+	if err := setCaptchaOption(chat.ID, "timeout", int64(timeout.Seconds())); err != nil {
+		slog.Error("Couldn't set captcha timeout",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("captchatime-changed", map[string]any{
+		"timeout": localization.HumanizeTimeSince(timeout, update),
+	}))
+}
+
+func Load(b *bot.Bot) {
+	b.RegisterHandler(bot.HandlerTypeMessageText, "captcha", bot.MatchTypeCommand, captchaHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "captchamode", bot.MatchTypeCommand, captchaModeHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "captchatime", bot.MatchTypeCommand, captchaTimeHandler)
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "captcha", bot.MatchTypePrefix, captchaCallback)
+
+	utils.Schedule(b, "captcha", expiryInterval, getExpiredCaptchas, failCaptcha)
+
+	utils.RegisterChatSection("captcha", utils.ChatSection{Export: exportCaptcha, Import: importCaptcha})
+	utils.SaveHelp("captcha")
+}
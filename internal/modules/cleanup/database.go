@@ -0,0 +1,111 @@
+package cleanup
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/angelomds42/EleineBot/internal/database"
+)
+
+type cleanupSettings struct {
+	Commands   bool
+	ReplyDelay time.Duration
+}
+
+// scheduledDeletion is a message the bot sent that will be deleted at a
+// given time.
+type scheduledDeletion struct {
+	ChatID    int64
+	MessageID int
+}
+
+func getCleanupSettings(chatID int64) (cleanupSettings, error) {
+	var settings cleanupSettings
+	var delay int64
+	err := database.DB.QueryRow(
+		"SELECT clean_commands, reply_delay FROM cleanupSettings WHERE chat_id = ?;", chatID,
+	).Scan(&settings.Commands, &delay)
+	if err == sql.ErrNoRows {
+		return settings, nil
+	}
+	settings.ReplyDelay = time.Duration(delay) * time.Second
+	return settings, err
+}
+
+func setCleanupOption(chatID int64, option string, value any) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO cleanupSettings (chat_id, `+option+`) VALUES (?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET `+option+` = excluded.`+option+`;
+	`, chatID, value)
+	return err
+}
+
+func getCleanServices(chatID int64) (map[string]bool, error) {
+	rows, err := database.DB.Query("SELECT type FROM cleanService WHERE chat_id = ?;", chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	services := make(map[string]bool)
+	for rows.Next() {
+		var serviceType string
+		if err := rows.Scan(&serviceType); err != nil {
+			return nil, err
+		}
+		services[serviceType] = true
+	}
+	return services, rows.Err()
+}
+
+func setCleanServices(chatID int64, serviceTypes []string, enabled bool) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "DELETE FROM cleanService WHERE chat_id = ? AND type = ?;"
+	if enabled {
+		query = "INSERT OR IGNORE INTO cleanService (chat_id, type) VALUES (?, ?);"
+	}
+	for _, serviceType := range serviceTypes {
+		if _, err := tx.Exec(query, chatID, serviceType); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func scheduleDeletion(chatID int64, messageID int, deleteAt time.Time) error {
+	_, err := database.DB.Exec(
+		"INSERT OR REPLACE INTO scheduledDeletions (chat_id, message_id, delete_at) VALUES (?, ?, ?);",
+		chatID, messageID, deleteAt.Unix())
+	return err
+}
+
+// getDueDeletions returns the messages that should have been deleted by now.
+func getDueDeletions(now time.Time) ([]scheduledDeletion, error) {
+	rows, err := database.DB.Query(
+		"SELECT chat_id, message_id FROM scheduledDeletions WHERE delete_at <= ?;", now.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deletions []scheduledDeletion
+	for rows.Next() {
+		var deletion scheduledDeletion
+		if err := rows.Scan(&deletion.ChatID, &deletion.MessageID); err != nil {
+			return nil, err
+		}
+		deletions = append(deletions, deletion)
+	}
+	return deletions, rows.Err()
+}
+
+func deleteScheduledDeletion(chatID int64, messageID int) error {
+	_, err := database.DB.Exec(
+		"DELETE FROM scheduledDeletions WHERE chat_id = ? AND message_id = ?;", chatID, messageID)
+	return err
+}
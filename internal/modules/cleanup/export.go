@@ -0,0 +1,64 @@
+package cleanup
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+type cleanupSection struct {
+	Services []string `json:"services"`
+	Commands bool     `json:"commands"`
+	// ReplyDelay is in seconds, 0 when replies are kept.
+	ReplyDelay int64 `json:"reply_delay"`
+}
+
+func exportCleanup(chatID int64) (any, error) {
+	services, err := getCleanServices(chatID)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := getCleanupSettings(chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	section := cleanupSection{
+		Services:   []string{},
+		Commands:   settings.Commands,
+		ReplyDelay: int64(settings.ReplyDelay.Seconds()),
+	}
+	for _, serviceType := range serviceTypes {
+		if services[serviceType.name] {
+			section.Services = append(section.Services, serviceType.name)
+		}
+	}
+	return section, nil
+}
+
+func importCleanup(tx *sql.Tx, chatID int64, data json.RawMessage) error {
+	var section cleanupSection
+	if err := json.Unmarshal(data, &section); err != nil {
+		return err
+	}
+	if delay := time.Duration(section.ReplyDelay) * time.Second; delay != 0 && (delay < minReplyDelay || delay > maxReplyDelay) {
+		section.ReplyDelay = 0
+	}
+
+	if _, err := tx.Exec("DELETE FROM cleanService WHERE chat_id = ?;", chatID); err != nil {
+		return err
+	}
+	for _, serviceType := range section.Services {
+		if !isServiceType(serviceType) {
+			continue
+		}
+		if _, err := tx.Exec("INSERT OR IGNORE INTO cleanService (chat_id, type) VALUES (?, ?);", chatID, serviceType); err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.Exec(
+		"INSERT OR REPLACE INTO cleanupSettings (chat_id, clean_commands, reply_delay) VALUES (?, ?, ?);",
+		chatID, section.Commands, section.ReplyDelay)
+	return err
+}
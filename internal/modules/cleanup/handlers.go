@@ -0,0 +1,452 @@
+package cleanup
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/modules/moderation"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+const (
+	deletionInterval = 5 * time.Second
+	minReplyDelay    = 5 * time.Second
+	maxReplyDelay    = 24 * time.Hour
+)
+
+var serviceTypes = []struct {
+	name  string
+	match func(*models.Message) bool
+}{
+	{"join", func(m *models.Message) bool { return len(m.NewChatMembers) > 0 }},
+	{"leave", func(m *models.Message) bool { return m.LeftChatMember != nil }},
+	{"pin", func(m *models.Message) bool { return m.PinnedMessage != nil }},
+	{"title", func(m *models.Message) bool { return m.NewChatTitle != "" }},
+	{"photo", func(m *models.Message) bool { return len(m.NewChatPhoto) > 0 || m.DeleteChatPhoto }},
+	{"videochat", func(m *models.Message) bool {
+		return m.VoiceChatScheduled != nil || m.VoiceChatStarted != nil ||
+			m.VoiceChatEnded != nil || m.VoiceChatParticipantsInvited != nil
+	}},
+	{"topics", func(m *models.Message) bool {
+		return m.ForumTopicCreated != nil || m.ForumTopicEdited != nil ||
+			m.ForumTopicClosed != nil || m.ForumTopicReopened != nil ||
+			m.GeneralForumTopicHidden != nil || m.GeneralForumTopicUnhidden != nil
+	}},
+	{"other", func(m *models.Message) bool {
+		return m.MessageAutoDeleteTimerChanged != nil || m.BoostAdded != nil ||
+			m.ChatBackgroundSet != nil || m.GiveawayCreated != nil ||
+			m.GiveawayCompleted != nil || m.ProximityAlertTriggered != nil
+	}},
+}
+
+// replyDelays are the steps the /config buttons move the reply delay through.
+var replyDelays = []time.Duration{
+	0,
+	10 * time.Second,
+	30 * time.Second,
+	time.Minute,
+	5 * time.Minute,
+	15 * time.Minute,
+	time.Hour,
+}
+
+func serviceType(message *models.Message) string {
+	for _, serviceType := range serviceTypes {
+		if serviceType.match(message) {
+			return serviceType.name
+		}
+	}
+	return ""
+}
+
+func isServiceType(name string) bool {
+	for _, serviceType := range serviceTypes {
+		if serviceType.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func deleteMessage(ctx context.Context, b *bot.Bot, chatID int64, messageID int) {
+	if _, err := b.DeleteMessage(ctx, &bot.DeleteMessageParams{
+		ChatID:    chatID,
+		MessageID: messageID,
+	}); err != nil {
+		slog.Debug("Couldn't delete message",
+			"ChatID", chatID,
+			"MessageID", messageID,
+			"Error", err.Error())
+	}
+}
+
+// CleanServiceMiddleware deletes the service messages of the types enabled in
+// the chat, once every other handler has seen them.
+func CleanServiceMiddleware(next bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		next(ctx, b, update)
+
+		message := update.Message
+		if message == nil || message.Chat.Type == models.ChatTypePrivate {
+			return
+		}
+
+		serviceType := serviceType(message)
+		if serviceType == "" {
+			return
+		}
+
+		services, err := getCleanServices(message.Chat.ID)
+		if err != nil {
+			slog.Error("Couldn't get clean service settings",
+				"ChatID", message.Chat.ID,
+				"Error", err.Error())
+			return
+		}
+		if services[serviceType] {
+			deleteMessage(ctx, b, message.Chat.ID, message.ID)
+		}
+	}
+}
+
+// CleanCommandsMiddleware deletes the commands sent to the bot after they're
+// handled, in the chats that enabled it. Commands no handler matched, like
+// those of other bots, are left alone.
+func CleanCommandsMiddleware(next bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		ctx, handled := utils.TrackHandled(ctx)
+		next(ctx, b, update)
+
+		message := update.Message
+		if message == nil || message.Chat.Type == models.ChatTypePrivate || !strings.HasPrefix(message.Text, "/") || !handled() {
+			return
+		}
+
+		settings, err := getCleanupSettings(message.Chat.ID)
+		if err != nil {
+			slog.Error("Couldn't get cleanup settings",
+				"ChatID", message.Chat.ID,
+				"Error", err.Error())
+			return
+		}
+		if settings.Commands {
+			deleteMessage(ctx, b, message.Chat.ID, message.ID)
+		}
+	}
+}
+
+// scheduleReplyDeletion schedules the deletion of a reply of the bot in the
+// chats with a reply delay.
+func scheduleReplyDeletion(ctx context.Context, b *bot.Bot, reply *models.Message) {
+	if reply.Chat.Type == models.ChatTypePrivate {
+		return
+	}
+
+	settings, err := getCleanupSettings(reply.Chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get cleanup settings",
+			"ChatID", reply.Chat.ID,
+			"Error", err.Error())
+		return
+	}
+	if settings.ReplyDelay == 0 {
+		return
+	}
+
+	if err := scheduleDeletion(reply.Chat.ID, reply.ID, time.Now().Add(settings.ReplyDelay)); err != nil {
+		slog.Error("Couldn't schedule message deletion",
+			"ChatID", reply.Chat.ID,
+			"MessageID", reply.ID,
+			"Error", err.Error())
+	}
+}
+
+func deleteScheduledMessage(ctx context.Context, b *bot.Bot, deletion scheduledDeletion) {
+	deleteMessage(ctx, b, deletion.ChatID, deletion.MessageID)
+	if err := deleteScheduledDeletion(deletion.ChatID, deletion.MessageID); err != nil {
+		slog.Error("Couldn't remove scheduled deletion",
+			"ChatID", deletion.ChatID,
+			"MessageID", deletion.MessageID,
+			"Error", err.Error())
+	}
+}
+
+func serviceState(enabled bool) string {
+	if enabled {
+		return "✅"
+	}
+	return "☑️"
+}
+
+func replyDelayText(update *models.Update, delay time.Duration) string {
+	if delay == 0 {
+		return localization.Get(update)("cleanreplies-never")
+	}
+	return localization.HumanizeTimeSince(delay, update)
+}
+
+func cleanServiceHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightDeleteMessages)
+	if !ok {
+		return
+	}
+
+	if fields := strings.Fields(message.Text); len(fields) > 1 {
+		name := strings.ToLower(fields[1])
+		enabled, ok := false, len(fields) == 3
+		if ok {
+			enabled, ok = utils.ParseSwitch(fields[2])
+		}
+		if !ok || (name != "all" && !isServiceType(name)) {
+			utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("cleanservice-usage"))
+			return
+		}
+
+		if enabled && !moderation.CheckChatBotRight(ctx, b, message, chat, moderation.RightDeleteMessages) {
+			return
+		}
+
+		types := []string{name}
+		if name == "all" {
+			types = types[:0]
+			for _, serviceType := range serviceTypes {
+				types = append(types, serviceType.name)
+			}
+		}
+		if err := setCleanServices(chat.ID, types, enabled); err != nil {
+			slog.Error("Couldn't update clean service settings",
+				"ChatID", chat.ID,
+				"Type", name,
+				"Error", err.Error())
+			return
+		}
+	}
+
+	services, err := getCleanServices(chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get clean service settings",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	var text strings.Builder
+	text.WriteString(i18n("cleanservice-status"))
+	for _, serviceType := range serviceTypes {
+		text.WriteString("\n" + serviceState(services[serviceType.name]) + " <code>" + serviceType.name + "</code> — " +
+			i18n("cleanservice-"+serviceType.name))
+	}
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, text.String())
+}
+
+func cleanCommandsHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightDeleteMessages)
+	if !ok {
+		return
+	}
+
+	settings, err := getCleanupSettings(chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get cleanup settings",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	if fields := strings.Fields(message.Text); len(fields) > 1 {
+		enabled, ok := utils.ParseSwitch(fields[1])
+		if !ok {
+			utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("cleancommands-usage"))
+			return
+		}
+
+		if enabled && !moderation.CheckChatBotRight(ctx, b, message, chat, moderation.RightDeleteMessages) {
+			return
+		}
+
+		if err := setCleanupOption(chat.ID, "clean_commands", enabled); err != nil {
+			slog.Error("Couldn't update cleanup settings",
+				"ChatID", chat.ID,
+				"Error", err.Error())
+			return
+		}
+		settings.Commands = enabled
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("cleancommands-status", map[string]any{
+		"enabled": strconv.FormatBool(settings.Commands),
+	}))
+}
+
+func cleanRepliesHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightDeleteMessages)
+	if !ok {
+		return
+	}
+
+	settings, err := getCleanupSettings(chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get cleanup settings",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	if fields := strings.Fields(message.Text); len(fields) > 1 {
+		var delay time.Duration
+		if enabled, ok := utils.ParseSwitch(fields[1]); !ok || enabled {
+			delay, err = utils.ParseCustomDuration(fields[1])
+			if err != nil || delay < minReplyDelay || delay > maxReplyDelay {
+				utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("cleanreplies-usage"))
+				return
+			}
+		}
+
+		if delay > 0 && !moderation.CheckChatBotRight(ctx, b, message, chat, moderation.RightDeleteMessages) {
+			return
+		}
+
+		if err := setCleanupOption(chat.ID, "reply_delay", int64(delay.Seconds())); err != nil {
+			slog.Error("Couldn't update cleanup settings",
+				"ChatID", chat.ID,
+				"Error", err.Error())
+			return
+		}
+		settings.ReplyDelay = delay
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("cleanreplies-status", map[string]any{
+		"delay": replyDelayText(update, settings.ReplyDelay),
+	}))
+}
+
+// stepReplyDelay moves delay to the previous or next of replyDelays.
+func stepReplyDelay(delay time.Duration, forward bool) time.Duration {
+	if forward {
+		for _, step := range replyDelays {
+			if step > delay {
+				return step
+			}
+		}
+		return delay
+	}
+	for i := len(replyDelays) - 1; i >= 0; i-- {
+		if replyDelays[i] < delay {
+			return replyDelays[i]
+		}
+	}
+	return delay
+}
+
+// cleanupConfigCallback shows the cleanup page of /config, where admins
+// toggle the service messages to delete and the deletion of commands, and
+// move the delay before the bot's replies are deleted.
+func cleanupConfigCallback(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.CallbackQuery.Message.Message
+	i18n := localization.Get(update)
+
+	chat := moderation.CallbackChat(ctx, b, update.CallbackQuery)
+	if !moderation.CheckChatRightCallback(ctx, b, update.CallbackQuery, chat.ID, moderation.RightDeleteMessages) {
+		return
+	}
+
+	services, err := getCleanServices(chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get clean service settings",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+	settings, err := getCleanupSettings(chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get cleanup settings",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	switch option := strings.TrimPrefix(update.CallbackQuery.Data, "cleanupConfig "); option {
+	case "commands":
+		settings.Commands = !settings.Commands
+		err = setCleanupOption(chat.ID, "clean_commands", settings.Commands)
+	case "replies-", "replies+":
+		settings.ReplyDelay = stepReplyDelay(settings.ReplyDelay, option == "replies+")
+		err = setCleanupOption(chat.ID, "reply_delay", int64(settings.ReplyDelay.Seconds()))
+	case "info":
+		utils.SendCallbackReply(ctx, b, update.CallbackQuery.ID, i18n("cleanreplies-alert"))
+		return
+	default:
+		if isServiceType(option) {
+			services[option] = !services[option]
+			err = setCleanServices(chat.ID, []string{option}, services[option])
+		}
+	}
+	if err != nil {
+		slog.Error("Couldn't update cleanup settings",
+			"ChatID", chat.ID,
+			"Data", update.CallbackQuery.Data,
+			"Error", err.Error())
+		return
+	}
+
+	buttons := make([][]models.InlineKeyboardButton, 0, len(serviceTypes)/2+4)
+	for i := 0; i < len(serviceTypes); i += 2 {
+		row := make([]models.InlineKeyboardButton, 0, 2)
+		for _, serviceType := range serviceTypes[i:min(i+2, len(serviceTypes))] {
+			row = append(row, models.InlineKeyboardButton{
+				Text:         serviceState(services[serviceType.name]) + " " + i18n("cleanservice-"+serviceType.name),
+				CallbackData: "cleanupConfig " + serviceType.name,
+			})
+		}
+		buttons = append(buttons, row)
+	}
+	buttons = append(buttons,
+		[]models.InlineKeyboardButton{
+			{Text: serviceState(settings.Commands) + " " + i18n("cleancommands-button"), CallbackData: "cleanupConfig commands"},
+		},
+		[]models.InlineKeyboardButton{
+			{Text: "➖", CallbackData: "cleanupConfig replies-"},
+			{
+				Text:         i18n("cleanreplies-button", map[string]any{"delay": replyDelayText(update, settings.ReplyDelay)}),
+				CallbackData: "cleanupConfig info",
+			},
+			{Text: "➕", CallbackData: "cleanupConfig replies+"},
+		},
+		[]models.InlineKeyboardButton{
+			{Text: i18n("back-button"), CallbackData: "config"},
+		},
+	)
+
+	utils.EditMessage(ctx, b, message.Chat.ID, message.ID,
+		i18n("config-cleanup"),
+		utils.WithReplyMarkup(&models.InlineKeyboardMarkup{InlineKeyboard: buttons}))
+}
+
+func Load(b *bot.Bot) {
+	b.RegisterHandler(bot.HandlerTypeMessageText, "cleanservice", bot.MatchTypeCommand, cleanServiceHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "cleancommands", bot.MatchTypeCommand, cleanCommandsHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "cleanreplies", bot.MatchTypeCommand, cleanRepliesHandler)
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "cleanupConfig", bot.MatchTypePrefix, cleanupConfigCallback)
+
+	utils.OnReply(scheduleReplyDeletion)
+	utils.Schedule(b, "cleanup", deletionInterval, getDueDeletions, deleteScheduledMessage)
+
+	utils.RegisterChatSection("cleanup", utils.ChatSection{Export: exportCleanup, Import: importCleanup})
+	utils.SaveHelp("cleanup")
+}
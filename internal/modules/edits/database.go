@@ -0,0 +1,62 @@
+package edits
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/angelomds42/EleineBot/internal/database"
+)
+
+func getEditsEnabled(chatID int64) (bool, error) {
+	var enabled bool
+	err := database.DB.QueryRow("SELECT enabled FROM editSettings WHERE chat_id = ?;", chatID).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return enabled, err
+}
+
+func setEditsEnabled(chatID int64, enabled bool) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO editSettings (chat_id, enabled) VALUES (?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET enabled = excluded.enabled;
+	`, chatID, enabled)
+	return err
+}
+
+// getAnsweredTriggers returns what a message already triggered, and whether
+// it was recorded at all.
+func getAnsweredTriggers(chatID int64, messageID int) (messageTriggers, bool, error) {
+	var commands, links string
+	err := database.DB.QueryRow("SELECT commands, links FROM editTriggers WHERE chat_id = ? AND message_id = ?;",
+		chatID, messageID).Scan(&commands, &links)
+	if err == sql.ErrNoRows {
+		return messageTriggers{}, false, nil
+	}
+	if err != nil {
+		return messageTriggers{}, false, err
+	}
+	return messageTriggers{commands: splitTriggers(commands), links: splitTriggers(links)}, true, nil
+}
+
+func saveAnsweredTriggers(chatID int64, messageID int, date int, triggers messageTriggers) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO editTriggers (chat_id, message_id, commands, links, date) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(chat_id, message_id) DO UPDATE SET commands = excluded.commands, links = excluded.links;
+	`, chatID, messageID, strings.Join(triggers.commands, "\n"), strings.Join(triggers.links, "\n"), date)
+	return err
+}
+
+// deleteAnsweredTriggers deletes what the messages sent before date
+// triggered.
+func deleteAnsweredTriggers(date int64) error {
+	_, err := database.DB.Exec("DELETE FROM editTriggers WHERE date < ?;", date)
+	return err
+}
+
+func splitTriggers(joined string) []string {
+	if joined == "" {
+		return nil
+	}
+	return strings.Split(joined, "\n")
+}
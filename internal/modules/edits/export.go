@@ -0,0 +1,27 @@
+package edits
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+type editsSection struct {
+	Enabled bool `json:"enabled"`
+}
+
+func exportEdits(chatID int64) (any, error) {
+	enabled, err := getEditsEnabled(chatID)
+	return editsSection{Enabled: enabled}, err
+}
+
+func importEdits(tx *sql.Tx, chatID int64, data json.RawMessage) error {
+	var section editsSection
+	if err := json.Unmarshal(data, &section); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`
+		INSERT INTO editSettings (chat_id, enabled) VALUES (?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET enabled = excluded.enabled;
+	`, chatID, section.Enabled)
+	return err
+}
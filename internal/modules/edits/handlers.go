@@ -0,0 +1,294 @@
+package edits
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/modules/moderation"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+const (
+	// triggersTTL bounds how long after being sent a message's edits are
+	// told apart from what it already triggered.
+	triggersTTL = 48 * time.Hour
+	// triggersInterval is how often the expired triggers are deleted.
+	triggersInterval = time.Hour
+)
+
+// editsCache holds whether each chat processes edited messages, since it's
+// looked up for every message.
+var (
+	editsCache      = make(map[int64]bool)
+	editsCacheMutex sync.RWMutex
+)
+
+func getCachedEditsEnabled(chatID int64) (bool, error) {
+	editsCacheMutex.RLock()
+	enabled, ok := editsCache[chatID]
+	editsCacheMutex.RUnlock()
+	if ok {
+		return enabled, nil
+	}
+
+	enabled, err := getEditsEnabled(chatID)
+	if err != nil {
+		return false, err
+	}
+
+	editsCacheMutex.Lock()
+	editsCache[chatID] = enabled
+	editsCacheMutex.Unlock()
+	return enabled, nil
+}
+
+func invalidateEdits(chatID int64) {
+	editsCacheMutex.Lock()
+	delete(editsCache, chatID)
+	editsCacheMutex.Unlock()
+}
+
+// messageTriggers is what a message can trigger a reply with: the command it
+// starts with, along with its arguments other than media links, and the media
+// links in it.
+type messageTriggers struct {
+	commands []string
+	links    []string
+}
+
+func parseTriggers(text string) messageTriggers {
+	var triggers messageTriggers
+	if strings.HasPrefix(text, "/") {
+		command := strings.Join(strings.Fields(utils.MediaLinkRegex.ReplaceAllString(text, " ")), " ")
+		if command != "" {
+			triggers.commands = []string{command}
+		}
+	}
+	for _, link := range utils.MediaLinkRegex.FindAllString(text, -1) {
+		triggers.links = append(triggers.links, strings.TrimSpace(link))
+	}
+	return triggers
+}
+
+// without returns the triggers that aren't in answered.
+func (t messageTriggers) without(answered messageTriggers) messageTriggers {
+	return messageTriggers{
+		commands: missingItems(t.commands, answered.commands),
+		links:    missingItems(t.links, answered.links),
+	}
+}
+
+func missingItems(items, known []string) []string {
+	var missing []string
+	for _, item := range items {
+		if !slices.Contains(known, item) && !slices.Contains(missing, item) {
+			missing = append(missing, item)
+		}
+	}
+	return missing
+}
+
+// recordTriggers records what a new message triggers, so its edits don't
+// trigger it again.
+func recordTriggers(message *models.Message) {
+	triggers := parseTriggers(message.Text)
+	if len(triggers.commands) == 0 && len(triggers.links) == 0 {
+		return
+	}
+	if err := saveAnsweredTriggers(message.Chat.ID, message.ID, message.Date, triggers); err != nil {
+		slog.Error("Couldn't save message triggers",
+			"ChatID", message.Chat.ID,
+			"Error", err.Error())
+	}
+}
+
+// editedTriggers returns the edited message to handle as a new one, holding
+// only what it didn't trigger yet, or nil when the edit adds nothing.
+func editedTriggers(edited *models.Message) *models.Message {
+	answered, found, err := getAnsweredTriggers(edited.Chat.ID, edited.ID)
+	if err != nil {
+		slog.Error("Couldn't get message triggers",
+			"ChatID", edited.Chat.ID,
+			"Error", err.Error())
+		return nil
+	}
+	// What older messages triggered is forgotten, so their edits are left
+	// alone rather than risking to trigger it again.
+	if !found && time.Since(time.Unix(int64(edited.Date), 0)) > triggersTTL {
+		return nil
+	}
+
+	current := parseTriggers(edited.Text)
+	added := current.without(answered)
+	if len(added.commands) == 0 && len(added.links) == 0 {
+		return nil
+	}
+
+	answered.commands = append(answered.commands, added.commands...)
+	answered.links = append(answered.links, added.links...)
+	if err := saveAnsweredTriggers(edited.Chat.ID, edited.ID, edited.Date, answered); err != nil {
+		slog.Error("Couldn't save message triggers",
+			"ChatID", edited.Chat.ID,
+			"Error", err.Error())
+		return nil
+	}
+
+	if len(added.commands) > 0 {
+		return edited
+	}
+
+	// The command was already answered, so it's kept only for the links
+	// that follow it, like /dl with a new link.
+	message := *edited
+	message.Text = strings.Join(append(current.commands, added.links...), " ")
+	message.Entities = nil
+	if len(current.commands) > 0 && len(edited.Entities) > 0 &&
+		edited.Entities[0].Type == models.MessageEntityTypeBotCommand && edited.Entities[0].Offset == 0 {
+		message.Entities = edited.Entities[:1]
+	}
+	return &message
+}
+
+func deleteExpiredTriggers(now time.Time) {
+	if err := deleteAnsweredTriggers(now.Add(-triggersTTL).Unix()); err != nil {
+		slog.Error("Couldn't delete expired message triggers",
+			"Error", err.Error())
+	}
+}
+
+type dispatchedKey struct{}
+
+func isDispatched(ctx context.Context) bool {
+	return EditedMessage(ctx) != nil
+}
+
+// EditedMessage returns the whole edited message when handling an edit
+// dispatched as a new message, which only holds what the edit added. The
+// checks on the content of messages, like the locks, look at it instead.
+func EditedMessage(ctx context.Context) *models.Message {
+	edited, _ := ctx.Value(dispatchedKey{}).(*models.Message)
+	return edited
+}
+
+// SkipDispatched makes the edits dispatched as new messages bypass mw, so
+// that they don't go through it a second time.
+func SkipDispatched(mw bot.Middleware) bot.Middleware {
+	return func(next bot.HandlerFunc) bot.HandlerFunc {
+		handler := mw(next)
+		return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+			if isDispatched(ctx) {
+				next(ctx, b, update)
+				return
+			}
+			handler(ctx, b, update)
+		}
+	}
+}
+
+// EditedMessageMiddleware handles edited messages like new ones in the chats
+// that enable it, limited to the commands and media links the edit adds.
+func EditedMessageMiddleware(next bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		message := update.Message
+		if message == nil {
+			message = update.EditedMessage
+		}
+		if message == nil || isDispatched(ctx) {
+			next(ctx, b, update)
+			return
+		}
+
+		enabled, err := getCachedEditsEnabled(message.Chat.ID)
+		if err != nil {
+			slog.Error("Couldn't get edit settings",
+				"ChatID", message.Chat.ID,
+				"Error", err.Error())
+		}
+		if !enabled {
+			next(ctx, b, update)
+			return
+		}
+
+		if update.EditedMessage == nil {
+			recordTriggers(message)
+			next(ctx, b, update)
+			return
+		}
+
+		// Handlers only match new messages, so the edit is dispatched as one.
+		if dispatched := editedTriggers(message); dispatched != nil {
+			b.ProcessUpdate(context.WithValue(ctx, dispatchedKey{}, message), &models.Update{ID: update.ID, Message: dispatched})
+		}
+	}
+}
+
+func editsHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, _ := moderation.ConnectedChat(ctx, b, message)
+	if chat.Type != models.ChatTypePrivate && !moderation.CheckChatRight(ctx, b, message, chat, "") {
+		return
+	}
+
+	fields := strings.Fields(message.Text)
+	if len(fields) < 2 {
+		enabled, err := getEditsEnabled(chat.ID)
+		if err != nil {
+			slog.Error("Couldn't get edit settings",
+				"ChatID", chat.ID,
+				"Error", err.Error())
+			return
+		}
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("edits-status", map[string]any{
+			"enabled": strconv.FormatBool(enabled),
+		}))
+		return
+	}
+
+	enabled, ok := utils.ParseSwitch(fields[1])
+	if !ok {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("edits-usage"))
+		return
+	}
+
+	if err := setEditsEnabled(chat.ID, enabled); err != nil {
+		slog.Error("Couldn't update edit settings",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+	invalidateEdits(chat.ID)
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("edits-status", map[string]any{
+		"enabled": strconv.FormatBool(enabled),
+	}))
+	if chat.Type != models.ChatTypePrivate {
+		moderation.SendLog(ctx, b, chat, moderation.LogEntry{
+			Action: "config",
+			Actor:  message.From,
+			Details: i18n("edits-log", map[string]any{
+				"enabled": strconv.FormatBool(enabled),
+			}),
+			Message: message,
+		})
+	}
+}
+
+func Load(b *bot.Bot) {
+	b.RegisterHandler(bot.HandlerTypeMessageText, "edits", bot.MatchTypeCommand, editsHandler)
+
+	utils.Every(triggersInterval, deleteExpiredTriggers)
+
+	utils.RegisterChatSection("edits", utils.ChatSection{Export: exportEdits, Import: importEdits, Imported: invalidateEdits})
+	utils.SaveHelp("edits")
+}
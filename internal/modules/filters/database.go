@@ -0,0 +1,56 @@
+package filters
+
+import (
+	"github.com/angelomds42/EleineBot/internal/database"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+type chatFilter struct {
+	Trigger string        `json:"trigger"`
+	Mode    string        `json:"mode"`
+	Content utils.Content `json:"content"`
+}
+
+func getFilters(chatID int64) ([]chatFilter, error) {
+	rows, err := database.DB.Query(`
+		SELECT trigger, mode, COALESCE(text, ''), COALESCE(media_type, ''), COALESCE(file_id, '')
+		FROM filters WHERE chat_id = ? ORDER BY trigger;
+	`, chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var filters []chatFilter
+	for rows.Next() {
+		var filter chatFilter
+		if err := rows.Scan(&filter.Trigger, &filter.Mode,
+			&filter.Content.Text, &filter.Content.MediaType, &filter.Content.FileID); err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return filters, rows.Err()
+}
+
+func saveFilter(chatID int64, filter chatFilter) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO filters (chat_id, trigger, mode, text, media_type, file_id)
+		VALUES (?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''))
+		ON CONFLICT(chat_id, trigger) DO UPDATE SET
+			mode = excluded.mode,
+			text = excluded.text,
+			media_type = excluded.media_type,
+			file_id = excluded.file_id;
+	`, chatID, filter.Trigger, filter.Mode, filter.Content.Text, filter.Content.MediaType, filter.Content.FileID)
+	return err
+}
+
+func deleteFilter(chatID int64, trigger string) (bool, error) {
+	result, err := database.DB.Exec("DELETE FROM filters WHERE chat_id = ? AND trigger = ?;", chatID, trigger)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
@@ -0,0 +1,46 @@
+package filters
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+func exportFilters(chatID int64) (any, error) {
+	filters, err := getFilters(chatID)
+	if filters == nil {
+		filters = []chatFilter{}
+	}
+	return filters, err
+}
+
+func importFilters(tx *sql.Tx, chatID int64, data json.RawMessage) error {
+	var filters []chatFilter
+	if err := json.Unmarshal(data, &filters); err != nil {
+		return err
+	}
+	// A trigger that doesn't compile would break the filters of the chat.
+	if _, err := utils.CompileMatcher(filters, filterExpression); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM filters WHERE chat_id = ?;", chatID); err != nil {
+		return err
+	}
+	for _, filter := range filters {
+		if filter.Trigger == "" || (filter.Content.Text == "" && filter.Content.FileID == "") {
+			continue
+		}
+		if filter.Mode == "" {
+			filter.Mode = "contains"
+		}
+		if _, err := tx.Exec(`
+			INSERT OR REPLACE INTO filters (chat_id, trigger, mode, text, media_type, file_id)
+			VALUES (?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''));
+		`, chatID, filter.Trigger, filter.Mode, filter.Content.Text, filter.Content.MediaType, filter.Content.FileID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package filters
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/modules/moderation"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+// filterModes maps the prefix accepted before a trigger to its matching mode.
+var filterModes = map[string]string{
+	"exact:":  "exact",
+	"prefix:": "prefix",
+	"re:":     "regex",
+}
+
+var filtersCache = utils.NewMatcherCache(getFilters, filterExpression)
+
+func filterExpression(filter chatFilter) string {
+	trigger := regexp.QuoteMeta(filter.Trigger)
+	switch filter.Mode {
+	case "regex":
+		return filter.Trigger
+	case "exact":
+		return `^\s*` + trigger + `\s*$`
+	case "prefix":
+		return utils.PrefixExpression(trigger)
+	default:
+		return utils.WordExpression(trigger)
+	}
+}
+
+func CheckFiltersMiddleware(next bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		message := update.Message
+		if message == nil || message.From == nil || message.Chat.Type == models.ChatTypePrivate {
+			next(ctx, b, update)
+			return
+		}
+
+		text := message.Text
+		if text == "" {
+			text = message.Caption
+		}
+		if text == "" || strings.HasPrefix(text, "/") || utils.CheckDisabledCommand("filters", message.Chat.ID, utils.MessageThread(message)) {
+			next(ctx, b, update)
+			return
+		}
+
+		compiled, err := filtersCache.Get(message.Chat.ID)
+		if err != nil {
+			slog.Error("Couldn't load filters",
+				"ChatID", message.Chat.ID,
+				"Error", err.Error())
+			next(ctx, b, update)
+			return
+		}
+
+		if filter := compiled.Match(text); filter != nil {
+			sendFilter(ctx, b, message, *filter)
+		}
+
+		next(ctx, b, update)
+	}
+}
+
+func sendFilter(ctx context.Context, b *bot.Bot, message *models.Message, filter chatFilter) {
+	content := filter.Content
+	text, rows := utils.ParseButtons(content.Text)
+	content.Text = utils.FormatPlaceholders(text, utils.UserPlaceholders(message.From, message.Chat))
+
+	var markup *models.InlineKeyboardMarkup
+	if len(rows) > 0 {
+		markup = &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+	}
+
+	if _, err := utils.SendContent(ctx, b, message.Chat.ID, message.ID, content, markup); err != nil {
+		slog.Error("Couldn't send filter",
+			"ChatID", message.Chat.ID,
+			"Trigger", filter.Trigger,
+			"Error", err.Error())
+	}
+}
+
+// parseTrigger reads the matching mode from the prefix of trigger.
+func parseTrigger(trigger string) (chatFilter, error) {
+	filter := chatFilter{Trigger: trigger, Mode: "contains"}
+	for prefix, mode := range filterModes {
+		if rest, ok := strings.CutPrefix(trigger, prefix); ok {
+			filter.Trigger, filter.Mode = rest, mode
+			break
+		}
+	}
+
+	if filter.Mode == "regex" {
+		if _, err := regexp.Compile(filter.Trigger); err != nil || filter.Trigger == "" {
+			return chatFilter{}, fmt.Errorf("invalid regex: %s", filter.Trigger)
+		}
+		return filter, nil
+	}
+
+	filter.Trigger = strings.ToLower(strings.TrimSpace(filter.Trigger))
+	return filter, nil
+}
+
+func filterHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightChangeInfo)
+	if !ok {
+		return
+	}
+
+	args := utils.SplitArgs(message.Text)
+	if len(args) < 2 {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("filter-usage"))
+		return
+	}
+
+	filter, err := parseTrigger(args[1])
+	if err != nil {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("filter-invalid-regex"))
+		return
+	}
+	if filter.Trigger == "" {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("filter-usage"))
+		return
+	}
+
+	filter.Content, ok = utils.ExtractContent(message, 1)
+	if !ok {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("filter-usage"))
+		return
+	}
+
+	if err := saveFilter(chat.ID, filter); err != nil {
+		slog.Error("Couldn't save filter",
+			"ChatID", chat.ID,
+			"Trigger", filter.Trigger,
+			"Error", err.Error())
+		return
+	}
+	filtersCache.Invalidate(chat.ID)
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("filter-saved", map[string]any{
+		"trigger": utils.EscapeHTML(filter.Trigger),
+		"mode":    filter.Mode,
+	}))
+}
+
+func stopHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightChangeInfo)
+	if !ok {
+		return
+	}
+
+	args := utils.SplitArgs(message.Text)
+	if len(args) < 2 {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("stop-usage"))
+		return
+	}
+
+	trigger := args[1]
+	if filter, err := parseTrigger(trigger); err == nil {
+		trigger = filter.Trigger
+	}
+
+	removed, err := deleteFilter(chat.ID, trigger)
+	if err != nil {
+		slog.Error("Couldn't delete filter",
+			"ChatID", chat.ID,
+			"Trigger", trigger,
+			"Error", err.Error())
+		return
+	}
+
+	respKey := "filter-not-found"
+	if removed {
+		filtersCache.Invalidate(chat.ID)
+		respKey = "filter-deleted"
+	}
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n(respKey, map[string]any{
+		"trigger": utils.EscapeHTML(trigger),
+	}))
+}
+
+func filtersHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.ConnectedGroup(ctx, b, message)
+	if !ok {
+		return
+	}
+
+	filters, err := getFilters(chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get filters",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	if len(filters) == 0 {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("filters-empty"))
+		return
+	}
+
+	text := i18n("filters-list")
+	for _, filter := range filters {
+		text += fmt.Sprintf("\n- <code>%s</code> (%s)", utils.EscapeHTML(filter.Trigger), filter.Mode)
+	}
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, text)
+}
+
+func Load(b *bot.Bot) {
+	b.RegisterHandler(bot.HandlerTypeMessageText, "filter", bot.MatchTypeCommand, filterHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "stop", bot.MatchTypeCommand, stopHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "filters", bot.MatchTypeCommand, filtersHandler)
+
+	utils.RegisterChatSection("filters", utils.ChatSection{
+		Export:   exportFilters,
+		Import:   importFilters,
+		Imported: filtersCache.Invalidate,
+	})
+	utils.SaveHelp("filters")
+	utils.RegisterDisableable("filters", "filters")
+}
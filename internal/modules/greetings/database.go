@@ -0,0 +1,68 @@
+package greetings
+
+import (
+	"database/sql"
+
+	"github.com/angelomds42/EleineBot/internal/database"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+type greeting struct {
+	Enabled       bool
+	Content       utils.Content
+	Clean         bool
+	LastMessageID int
+}
+
+// getGreeting returns nil when the chat has no greeting of the given type.
+func getGreeting(chatID int64, greetingType string) (*greeting, error) {
+	var g greeting
+	err := database.DB.QueryRow(`
+		SELECT enabled, COALESCE(text, ''), COALESCE(media_type, ''), COALESCE(file_id, ''),
+			clean, COALESCE(last_message_id, 0)
+		FROM greetings WHERE chat_id = ? AND type = ?;
+	`, chatID, greetingType).Scan(&g.Enabled, &g.Content.Text, &g.Content.MediaType, &g.Content.FileID,
+		&g.Clean, &g.LastMessageID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func setGreetingContent(chatID int64, greetingType string, content utils.Content) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO greetings (chat_id, type, enabled, text, media_type, file_id)
+		VALUES (?, ?, 1, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''))
+		ON CONFLICT(chat_id, type) DO UPDATE SET
+			enabled = 1,
+			text = excluded.text,
+			media_type = excluded.media_type,
+			file_id = excluded.file_id;
+	`, chatID, greetingType, content.Text, content.MediaType, content.FileID)
+	return err
+}
+
+func resetGreetingContent(chatID int64, greetingType string) error {
+	_, err := database.DB.Exec(
+		"UPDATE greetings SET text = NULL, media_type = NULL, file_id = NULL WHERE chat_id = ? AND type = ?;",
+		chatID, greetingType)
+	return err
+}
+
+func setGreetingOption(chatID int64, greetingType, option string, value bool) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO greetings (chat_id, type, `+option+`) VALUES (?, ?, ?)
+		ON CONFLICT(chat_id, type) DO UPDATE SET `+option+` = excluded.`+option+`;
+	`, chatID, greetingType, value)
+	return err
+}
+
+func setLastMessageID(chatID int64, greetingType string, messageID int) error {
+	_, err := database.DB.Exec(
+		"UPDATE greetings SET last_message_id = ? WHERE chat_id = ? AND type = ?;",
+		messageID, chatID, greetingType)
+	return err
+}
@@ -0,0 +1,54 @@
+package greetings
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+type exportedGreeting struct {
+	Enabled bool          `json:"enabled"`
+	Content utils.Content `json:"content"`
+	Clean   bool          `json:"clean"`
+}
+
+// exportGreetings returns the greetings of chatID by type. The last message
+// sent isn't exported, as it belongs to the chat.
+func exportGreetings(chatID int64) (any, error) {
+	greetings := make(map[string]exportedGreeting)
+	for _, greetingType := range []string{welcomeType, goodbyeType} {
+		g, err := getGreeting(chatID, greetingType)
+		if err != nil {
+			return nil, err
+		}
+		if g != nil {
+			greetings[greetingType] = exportedGreeting{Enabled: g.Enabled, Content: g.Content, Clean: g.Clean}
+		}
+	}
+	return greetings, nil
+}
+
+func importGreetings(tx *sql.Tx, chatID int64, data json.RawMessage) error {
+	var greetings map[string]exportedGreeting
+	if err := json.Unmarshal(data, &greetings); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM greetings WHERE chat_id = ?;", chatID); err != nil {
+		return err
+	}
+	for _, greetingType := range []string{welcomeType, goodbyeType} {
+		g, ok := greetings[greetingType]
+		if !ok {
+			continue
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO greetings (chat_id, type, enabled, text, media_type, file_id, clean)
+			VALUES (?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), ?);
+		`, chatID, greetingType, g.Enabled, g.Content.Text, g.Content.MediaType, g.Content.FileID, g.Clean); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+package greetings
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/modules/moderation"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+const (
+	welcomeType = "welcome"
+	goodbyeType = "goodbye"
+)
+
+func buildGreeting(
+	ctx context.Context,
+	b *bot.Bot,
+	i18n func(string, ...map[string]any) string,
+	chat models.Chat,
+	user *models.User,
+	greetingType string,
+	content utils.Content,
+) (utils.Content, *models.InlineKeyboardMarkup) {
+	if content.Text == "" && content.FileID == "" {
+		content.Text = i18n(greetingType + "-default")
+	}
+
+	text, rows := utils.ParseButtons(content.Text)
+	if strings.Contains(text, "{rules}") {
+		text = strings.ReplaceAll(text, "{rules}", "")
+		if botUser, err := b.GetMe(ctx); err == nil {
+			rows = append(rows, []models.InlineKeyboardButton{{
+				Text: i18n("greeting-rules-button"),
+				URL:  fmt.Sprintf("https://t.me/%s?start=rules_%d", botUser.Username, chat.ID),
+			}})
+		}
+	}
+
+	values := utils.UserPlaceholders(user, chat)
+	if strings.Contains(text, "{count}") {
+		if count, err := b.GetChatMemberCount(ctx, &bot.GetChatMemberCountParams{ChatID: chat.ID}); err == nil {
+			values["count"] = strconv.Itoa(count)
+		}
+	}
+	content.Text = strings.TrimSpace(utils.FormatPlaceholders(text, values))
+
+	var markup *models.InlineKeyboardMarkup
+	if len(rows) > 0 {
+		markup = &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+	}
+	return content, markup
+}
+
+func sendGreeting(ctx context.Context, b *bot.Bot, update *models.Update, user *models.User, greetingType string) {
+	chat := update.Message.Chat
+	g, err := getGreeting(chat.ID, greetingType)
+	if err != nil {
+		slog.Error("Couldn't get greeting",
+			"ChatID", chat.ID,
+			"Type", greetingType,
+			"Error", err.Error())
+		return
+	}
+	if g == nil || !g.Enabled {
+		return
+	}
+
+	content, markup := buildGreeting(ctx, b, localization.Get(update), chat, user, greetingType, g.Content)
+	sent, err := utils.SendContent(ctx, b, chat.ID, 0, content, markup)
+	if err != nil {
+		slog.Error("Couldn't send greeting",
+			"ChatID", chat.ID,
+			"Type", greetingType,
+			"Error", err.Error())
+		return
+	}
+
+	if !g.Clean {
+		return
+	}
+	if g.LastMessageID != 0 {
+		b.DeleteMessage(ctx, &bot.DeleteMessageParams{ChatID: chat.ID, MessageID: g.LastMessageID})
+	}
+	if err := setLastMessageID(chat.ID, greetingType, sent.ID); err != nil {
+		slog.Error("Couldn't save last greeting",
+			"ChatID", chat.ID,
+			"Type", greetingType,
+			"Error", err.Error())
+	}
+}
+
+func newMembersHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	for _, member := range update.Message.NewChatMembers {
+		if member.ID == b.ID() {
+			continue
+		}
+		entry := moderation.LogEntry{Action: "join", Target: &member, Message: update.Message}
+		if update.Message.From != nil && update.Message.From.ID != member.ID {
+			entry.Actor = update.Message.From
+		}
+		moderation.SendLog(ctx, b, update.Message.Chat, entry)
+		sendGreeting(ctx, b, update, &member, welcomeType)
+	}
+}
+
+func leftMemberHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update.Message.LeftChatMember.ID == b.ID() {
+		return
+	}
+	entry := moderation.LogEntry{Action: "leave", Target: update.Message.LeftChatMember}
+	if update.Message.From != nil && update.Message.From.ID != update.Message.LeftChatMember.ID {
+		entry.Actor = update.Message.From
+	}
+	moderation.SendLog(ctx, b, update.Message.Chat, entry)
+	sendGreeting(ctx, b, update, update.Message.LeftChatMember, goodbyeType)
+}
+
+func greetingHandler(greetingType string) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		message := update.Message
+		i18n := localization.Get(update)
+
+		chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightChangeInfo)
+		if !ok {
+			return
+		}
+
+		typeName := i18n("greeting-" + greetingType)
+		if fields := strings.Fields(message.Text); len(fields) > 1 {
+			enabled, ok := utils.ParseSwitch(fields[1])
+			if !ok {
+				utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n(greetingType+"-usage"))
+				return
+			}
+			if err := setGreetingOption(chat.ID, greetingType, "enabled", enabled); err != nil {
+				slog.Error("Couldn't toggle greeting",
+					"ChatID", chat.ID,
+					"Type", greetingType,
+					"Error", err.Error())
+				return
+			}
+
+			respKey := "greeting-disabled"
+			if enabled {
+				respKey = "greeting-enabled"
+			}
+			utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n(respKey, map[string]any{"type": typeName}))
+			return
+		}
+
+		g, err := getGreeting(chat.ID, greetingType)
+		if err != nil {
+			slog.Error("Couldn't get greeting",
+				"ChatID", chat.ID,
+				"Type", greetingType,
+				"Error", err.Error())
+			return
+		}
+		if g == nil {
+			g = &greeting{}
+		}
+
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("greeting-status", map[string]any{
+			"type":    typeName,
+			"enabled": strconv.FormatBool(g.Enabled),
+			"clean":   strconv.FormatBool(g.Clean),
+		}))
+
+		content, markup := buildGreeting(ctx, b, i18n, chat, message.From, greetingType, g.Content)
+		if _, err := utils.SendContent(ctx, b, message.Chat.ID, message.ID, content, markup); err != nil {
+			slog.Error("Couldn't send greeting preview",
+				"ChatID", chat.ID,
+				"Type", greetingType,
+				"Error", err.Error())
+		}
+	}
+}
+
+func setGreetingHandler(greetingType string) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		message := update.Message
+		i18n := localization.Get(update)
+
+		chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightChangeInfo)
+		if !ok {
+			return
+		}
+
+		content, ok := utils.ExtractContent(message, 0)
+		if !ok {
+			utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("set"+greetingType+"-usage"))
+			return
+		}
+
+		if err := setGreetingContent(chat.ID, greetingType, content); err != nil {
+			slog.Error("Couldn't save greeting",
+				"ChatID", chat.ID,
+				"Type", greetingType,
+				"Error", err.Error())
+			return
+		}
+
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("greeting-saved", map[string]any{
+			"type": i18n("greeting-" + greetingType),
+		}))
+	}
+}
+
+func resetGreetingHandler(greetingType string) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		message := update.Message
+		i18n := localization.Get(update)
+
+		chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightChangeInfo)
+		if !ok {
+			return
+		}
+
+		if err := resetGreetingContent(chat.ID, greetingType); err != nil {
+			slog.Error("Couldn't reset greeting",
+				"ChatID", chat.ID,
+				"Type", greetingType,
+				"Error", err.Error())
+			return
+		}
+
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("greeting-reset", map[string]any{
+			"type": i18n("greeting-" + greetingType),
+		}))
+	}
+}
+
+func cleanWelcomeHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightChangeInfo)
+	if !ok {
+		return
+	}
+
+	fields := strings.Fields(message.Text)
+	if len(fields) < 2 {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("cleanwelcome-usage"))
+		return
+	}
+
+	clean, ok := utils.ParseSwitch(fields[1])
+	if !ok {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("cleanwelcome-usage"))
+		return
+	}
+
+	if clean && !moderation.CheckChatBotRight(ctx, b, message, chat, moderation.RightDeleteMessages) {
+		return
+	}
+
+	if err := setGreetingOption(chat.ID, welcomeType, "clean", clean); err != nil {
+		slog.Error("Couldn't toggle clean welcome",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	respKey := "cleanwelcome-disabled"
+	if clean {
+		respKey = "cleanwelcome-enabled"
+	}
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n(respKey))
+}
+
+func Load(b *bot.Bot) {
+	b.RegisterHandlerMatchFunc(func(update *models.Update) bool {
+		return update.Message != nil && len(update.Message.NewChatMembers) > 0
+	}, newMembersHandler)
+	b.RegisterHandlerMatchFunc(func(update *models.Update) bool {
+		return update.Message != nil && update.Message.LeftChatMember != nil
+	}, leftMemberHandler)
+
+	for _, greetingType := range []string{welcomeType, goodbyeType} {
+		b.RegisterHandler(bot.HandlerTypeMessageText, greetingType, bot.MatchTypeCommand, greetingHandler(greetingType))
+		b.RegisterHandler(bot.HandlerTypeMessageText, "set"+greetingType, bot.MatchTypeCommand, setGreetingHandler(greetingType))
+		b.RegisterHandler(bot.HandlerTypeMessageText, "reset"+greetingType, bot.MatchTypeCommand, resetGreetingHandler(greetingType))
+	}
+	b.RegisterHandler(bot.HandlerTypeMessageText, "cleanwelcome", bot.MatchTypeCommand, cleanWelcomeHandler)
+
+	utils.RegisterChatSection("greetings", utils.ChatSection{Export: exportGreetings, Import: importGreetings})
+	utils.SaveHelp("greetings")
+}
@@ -0,0 +1,120 @@
+package joinrequests
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/angelomds42/EleineBot/internal/database"
+)
+
+type joinSettings struct {
+	Enabled         bool
+	ApproveUsername bool
+	DeclineGbanned  bool
+	Captcha         bool
+}
+
+// joinRequest is a request waiting for an admin, or for the requester to
+// solve the captcha sent to them in private.
+type joinRequest struct {
+	ChatID    int64
+	UserID    int64
+	FirstName string
+	// ReviewChatID and MessageID locate the message with the buttons: the
+	// review posted to admins, or the captcha sent to the requester.
+	ReviewChatID int64
+	MessageID    int
+	Answer       string
+	Attempts     int
+}
+
+func getJoinSettings(chatID int64) (joinSettings, error) {
+	settings := joinSettings{DeclineGbanned: true}
+	err := database.DB.QueryRow(
+		"SELECT enabled, approve_username, decline_gbanned, captcha FROM joinRequestSettings WHERE chat_id = ?;", chatID,
+	).Scan(&settings.Enabled, &settings.ApproveUsername, &settings.DeclineGbanned, &settings.Captcha)
+	if err == sql.ErrNoRows {
+		return settings, nil
+	}
+	return settings, err
+}
+
+func setJoinOption(chatID int64, option string, value any) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO joinRequestSettings (chat_id, `+option+`) VALUES (?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET `+option+` = excluded.`+option+`;
+	`, chatID, value)
+	return err
+}
+
+// saveJoinRequest stores a pending request. A zero expiresAt means it waits
+// for an admin without a deadline.
+func saveJoinRequest(request joinRequest, expiresAt time.Time) error {
+	var expires int64
+	if !expiresAt.IsZero() {
+		expires = expiresAt.Unix()
+	}
+	_, err := database.DB.Exec(`
+		INSERT INTO joinRequests (chat_id, user_id, first_name, review_chat_id, message_id, answer, attempts, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, 0, ?)
+		ON CONFLICT(chat_id, user_id) DO UPDATE SET
+			first_name = excluded.first_name,
+			review_chat_id = excluded.review_chat_id,
+			message_id = excluded.message_id,
+			answer = excluded.answer,
+			attempts = 0,
+			expires_at = excluded.expires_at;
+	`, request.ChatID, request.UserID, request.FirstName, request.ReviewChatID, request.MessageID, request.Answer, expires)
+	return err
+}
+
+// getJoinRequest returns nil when the user has no pending request in the chat.
+func getJoinRequest(chatID, userID int64) (*joinRequest, error) {
+	request := joinRequest{ChatID: chatID, UserID: userID}
+	err := database.DB.QueryRow(`
+		SELECT first_name, review_chat_id, message_id, answer, attempts
+		FROM joinRequests WHERE chat_id = ? AND user_id = ?;
+	`, chatID, userID).Scan(&request.FirstName, &request.ReviewChatID, &request.MessageID, &request.Answer, &request.Attempts)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func incrementJoinAttempts(chatID, userID int64) error {
+	_, err := database.DB.Exec(
+		"UPDATE joinRequests SET attempts = attempts + 1 WHERE chat_id = ? AND user_id = ?;",
+		chatID, userID)
+	return err
+}
+
+func deleteJoinRequest(chatID, userID int64) error {
+	_, err := database.DB.Exec("DELETE FROM joinRequests WHERE chat_id = ? AND user_id = ?;", chatID, userID)
+	return err
+}
+
+// getExpiredJoinRequests returns the requests whose captcha wasn't solved
+// before now.
+func getExpiredJoinRequests(now time.Time) ([]joinRequest, error) {
+	rows, err := database.DB.Query(`
+		SELECT chat_id, user_id, first_name, review_chat_id, message_id
+		FROM joinRequests WHERE expires_at > 0 AND expires_at <= ?;
+	`, now.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []joinRequest
+	for rows.Next() {
+		var request joinRequest
+		if err := rows.Scan(&request.ChatID, &request.UserID, &request.FirstName, &request.ReviewChatID, &request.MessageID); err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, rows.Err()
+}
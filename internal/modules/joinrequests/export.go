@@ -0,0 +1,32 @@
+package joinrequests
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+type joinRequestsSection struct {
+	Enabled         bool `json:"enabled"`
+	ApproveUsername bool `json:"approve_username"`
+	DeclineGbanned  bool `json:"decline_gbanned"`
+	Captcha         bool `json:"captcha"`
+}
+
+// exportJoinRequests returns the join request settings of chatID. Pending
+// requests belong to the chat and aren't exported.
+func exportJoinRequests(chatID int64) (any, error) {
+	settings, err := getJoinSettings(chatID)
+	return joinRequestsSection(settings), err
+}
+
+func importJoinRequests(tx *sql.Tx, chatID int64, data json.RawMessage) error {
+	section := joinRequestsSection{DeclineGbanned: true}
+	if err := json.Unmarshal(data, &section); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`
+		INSERT OR REPLACE INTO joinRequestSettings (chat_id, enabled, approve_username, decline_gbanned, captcha)
+		VALUES (?, ?, ?, ?, ?);
+	`, chatID, section.Enabled, section.ApproveUsername, section.DeclineGbanned, section.Captcha)
+	return err
+}
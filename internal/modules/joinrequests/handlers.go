@@ -0,0 +1,377 @@
+package joinrequests
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/modules/captcha"
+	"github.com/angelomds42/EleineBot/internal/modules/moderation"
+	"github.com/angelomds42/EleineBot/internal/modules/sudoers"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+const (
+	// captchaTimeout is kept within the five minutes Telegram gives bots to
+	// message a requester who never started them.
+	captchaTimeout = 5 * time.Minute
+	maxAttempts    = 3
+	expiryInterval = 15 * time.Second
+)
+
+// joinOptions maps the rules toggled by /joinrequests to their columns.
+var joinOptions = map[string]string{
+	"username": "approve_username",
+	"gban":     "decline_gbanned",
+	"captcha":  "captcha",
+}
+
+func chatI18n(chat models.Chat) func(string, ...map[string]any) string {
+	return localization.Get(&models.Update{Message: &models.Message{Chat: chat}})
+}
+
+// decideRequest approves or declines the request of user to join chat and
+// logs it. admin is nil when a rule decided, and reason names that rule.
+func decideRequest(
+	ctx context.Context,
+	b *bot.Bot,
+	chat models.Chat,
+	user models.User,
+	approve bool,
+	admin *models.User,
+	reason string,
+) error {
+	var err error
+	action := "joindecline"
+	if approve {
+		action = "joinapprove"
+		_, err = b.ApproveChatJoinRequest(ctx, &bot.ApproveChatJoinRequestParams{ChatID: chat.ID, UserID: user.ID})
+	} else {
+		_, err = b.DeclineChatJoinRequest(ctx, &bot.DeclineChatJoinRequestParams{ChatID: chat.ID, UserID: user.ID})
+	}
+
+	if err := deleteJoinRequest(chat.ID, user.ID); err != nil {
+		slog.Error("Couldn't delete join request",
+			"ChatID", chat.ID,
+			"UserID", user.ID,
+			"Error", err.Error())
+	}
+	if err != nil {
+		slog.Error("Couldn't answer join request",
+			"ChatID", chat.ID,
+			"UserID", user.ID,
+			"Error", err.Error())
+		return err
+	}
+
+	entry := moderation.LogEntry{Action: action, Actor: admin, Target: &user}
+	if reason != "" {
+		entry.Details = chatI18n(chat)("joinrequest-reason-" + reason)
+	}
+	moderation.SendLog(ctx, b, chat, entry)
+	return nil
+}
+
+// postJoinRequest sends the request to the log channel, or to the group when
+// there's none, for an admin to approve or decline it.
+func postJoinRequest(ctx context.Context, b *bot.Bot, request *models.ChatJoinRequest) {
+	i18n := chatI18n(request.Chat)
+
+	reviewChatID := moderation.LogChannel(request.Chat.ID)
+	if reviewChatID == 0 {
+		reviewChatID = request.Chat.ID
+	}
+
+	text := i18n("joinrequest-new", map[string]any{
+		"chatName":      utils.EscapeHTML(request.Chat.Title),
+		"userID":        strconv.FormatInt(request.From.ID, 10),
+		"userFirstName": utils.EscapeHTML(request.From.FirstName),
+	})
+	if request.From.Username != "" {
+		text += "\n" + i18n("joinrequest-username", map[string]any{"username": request.From.Username})
+	}
+	if request.Bio != "" {
+		text += "\n" + i18n("joinrequest-bio", map[string]any{"bio": utils.EscapeHTML(request.Bio)})
+	}
+
+	callbackData := func(action string) string {
+		return fmt.Sprintf("joinreq %s %d %d", action, request.Chat.ID, request.From.ID)
+	}
+	sent, err := utils.SendMessageWithResult(ctx, b, reviewChatID, 0, text,
+		utils.WithReplyMarkupSend(&models.InlineKeyboardMarkup{
+			InlineKeyboard: [][]models.InlineKeyboardButton{{
+				{Text: i18n("joinrequest-approve-button"), CallbackData: callbackData("approve")},
+				{Text: i18n("joinrequest-decline-button"), CallbackData: callbackData("decline")},
+			}},
+		}))
+	if err != nil {
+		return
+	}
+
+	if err := saveJoinRequest(joinRequest{
+		ChatID:       request.Chat.ID,
+		UserID:       request.From.ID,
+		FirstName:    request.From.FirstName,
+		ReviewChatID: reviewChatID,
+		MessageID:    sent.ID,
+	}, time.Time{}); err != nil {
+		slog.Error("Couldn't save join request",
+			"ChatID", request.Chat.ID,
+			"UserID", request.From.ID,
+			"Error", err.Error())
+	}
+}
+
+// sendJoinCaptcha sends the requester a captcha in private, approving them
+// once it's solved. It reports false if they couldn't be messaged.
+func sendJoinCaptcha(ctx context.Context, b *bot.Bot, request *models.ChatJoinRequest) bool {
+	update := &models.Update{Message: &models.Message{
+		Chat: models.Chat{ID: request.UserChatID, Type: models.ChatTypePrivate},
+	}}
+	i18n := localization.Get(update)
+
+	mode := captcha.ChatMode(request.Chat.ID)
+	question, answer, markup := captcha.NewChallenge(i18n, mode, fmt.Sprintf("joincaptcha %d", request.Chat.ID))
+	text := i18n("joinrequest-captcha-"+mode, map[string]any{
+		"chatName": utils.EscapeHTML(request.Chat.Title),
+		"question": question,
+		"timeout":  localization.HumanizeTimeSince(captchaTimeout, update),
+	})
+
+	sent, err := utils.SendMessageWithResult(ctx, b, request.UserChatID, 0, text, utils.WithReplyMarkupSend(markup))
+	if err != nil {
+		return false
+	}
+
+	if err := saveJoinRequest(joinRequest{
+		ChatID:       request.Chat.ID,
+		UserID:       request.From.ID,
+		FirstName:    request.From.FirstName,
+		ReviewChatID: request.UserChatID,
+		MessageID:    sent.ID,
+		Answer:       answer,
+	}, time.Now().Add(captchaTimeout)); err != nil {
+		slog.Error("Couldn't save join request",
+			"ChatID", request.Chat.ID,
+			"UserID", request.From.ID,
+			"Error", err.Error())
+	}
+	return true
+}
+
+func joinRequestHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	request := update.ChatJoinRequest
+
+	settings, err := getJoinSettings(request.Chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get join request settings",
+			"ChatID", request.Chat.ID,
+			"Error", err.Error())
+		return
+	}
+	if !settings.Enabled {
+		return
+	}
+
+	switch {
+	case settings.DeclineGbanned && sudoers.IsGbanned(request.From.ID):
+		decideRequest(ctx, b, request.Chat, request.From, false, nil, "gban")
+	case settings.ApproveUsername && request.From.Username != "":
+		decideRequest(ctx, b, request.Chat, request.From, true, nil, "username")
+	case settings.Captcha && sendJoinCaptcha(ctx, b, request):
+	default:
+		postJoinRequest(ctx, b, request)
+	}
+}
+
+func reviewCallback(ctx context.Context, b *bot.Bot, update *models.Update) {
+	cb := update.CallbackQuery
+	message := cb.Message.Message
+
+	parts := strings.Fields(cb.Data)
+	if len(parts) != 4 {
+		return
+	}
+	chatID, _ := strconv.ParseInt(parts[2], 10, 64)
+	userID, _ := strconv.ParseInt(parts[3], 10, 64)
+
+	if !moderation.CheckChatRightCallback(ctx, b, cb, chatID, moderation.RightInviteUsers) {
+		return
+	}
+
+	chat := moderation.GroupChat(ctx, b, chatID)
+	i18n := chatI18n(chat)
+
+	request, err := getJoinRequest(chatID, userID)
+	if err != nil {
+		slog.Error("Couldn't get join request",
+			"ChatID", chatID,
+			"UserID", userID,
+			"Error", err.Error())
+		return
+	}
+	if request == nil {
+		utils.SendCallbackReply(ctx, b, cb.ID, i18n("joinrequest-gone"))
+		return
+	}
+
+	approve := parts[1] == "approve"
+	respKey := "joinrequest-declined"
+	if approve {
+		respKey = "joinrequest-approved"
+	}
+	// The request fails when the user withdrew it or an admin answered it
+	// from Telegram itself.
+	user := models.User{ID: userID, FirstName: request.FirstName}
+	if err := decideRequest(ctx, b, chat, user, approve, &cb.From, ""); err != nil {
+		respKey = "joinrequest-gone"
+	}
+
+	utils.EditMessage(ctx, b, message.Chat.ID, message.ID, i18n(respKey, map[string]any{
+		"chatName":       utils.EscapeHTML(chat.Title),
+		"userID":         strconv.FormatInt(userID, 10),
+		"userFirstName":  utils.EscapeHTML(request.FirstName),
+		"adminID":        strconv.FormatInt(cb.From.ID, 10),
+		"adminFirstName": utils.EscapeHTML(cb.From.FirstName),
+	}))
+}
+
+func captchaCallback(ctx context.Context, b *bot.Bot, update *models.Update) {
+	cb := update.CallbackQuery
+	message := cb.Message.Message
+	i18n := localization.Get(update)
+
+	parts := strings.Fields(cb.Data)
+	if len(parts) != 3 {
+		return
+	}
+	chatID, _ := strconv.ParseInt(parts[1], 10, 64)
+
+	request, err := getJoinRequest(chatID, cb.From.ID)
+	if err != nil {
+		slog.Error("Couldn't get join request",
+			"ChatID", chatID,
+			"UserID", cb.From.ID,
+			"Error", err.Error())
+		return
+	}
+	if request == nil {
+		utils.SendCallbackReply(ctx, b, cb.ID, i18n("captcha-expired"))
+		return
+	}
+
+	chat := moderation.GroupChat(ctx, b, chatID)
+	chatName := map[string]any{"chatName": utils.EscapeHTML(chat.Title)}
+
+	if parts[2] != request.Answer {
+		if request.Attempts+1 >= maxAttempts {
+			decideRequest(ctx, b, chat, cb.From, false, nil, "captcha-failed")
+			utils.EditMessage(ctx, b, message.Chat.ID, message.ID, i18n("joinrequest-captcha-failed", chatName))
+			return
+		}
+		if err := incrementJoinAttempts(chatID, cb.From.ID); err != nil {
+			slog.Error("Couldn't update join request attempts",
+				"ChatID", chatID,
+				"UserID", cb.From.ID,
+				"Error", err.Error())
+		}
+		utils.SendCallbackReply(ctx, b, cb.ID, i18n("captcha-wrong", map[string]any{
+			"attempts": maxAttempts - request.Attempts - 1,
+		}))
+		return
+	}
+
+	respKey := "joinrequest-captcha-solved"
+	if err := decideRequest(ctx, b, chat, cb.From, true, nil, "captcha-solved"); err != nil {
+		respKey = "joinrequest-gone"
+	}
+	utils.EditMessage(ctx, b, message.Chat.ID, message.ID, i18n(respKey, chatName))
+}
+
+// expireCaptcha declines a requester who didn't solve the captcha in time.
+func expireCaptcha(ctx context.Context, b *bot.Bot, request joinRequest) {
+	chat := moderation.GroupChat(ctx, b, request.ChatID)
+	decideRequest(ctx, b, chat, models.User{ID: request.UserID, FirstName: request.FirstName}, false, nil, "captcha-expired")
+
+	i18n := chatI18n(models.Chat{ID: request.ReviewChatID, Type: models.ChatTypePrivate})
+	utils.EditMessage(ctx, b, request.ReviewChatID, request.MessageID, i18n("joinrequest-captcha-expired", map[string]any{
+		"chatName": utils.EscapeHTML(chat.Title),
+	}))
+}
+
+func joinRequestsHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightInviteUsers)
+	if !ok {
+		return
+	}
+
+	fields := strings.Fields(message.Text)
+	if len(fields) > 1 {
+		option, arg := "enabled", fields[1]
+		if column, ok := joinOptions[strings.ToLower(fields[1])]; ok {
+			if len(fields) < 3 {
+				utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("joinrequests-usage"))
+				return
+			}
+			option, arg = column, fields[2]
+		}
+
+		enabled, ok := utils.ParseSwitch(arg)
+		if !ok {
+			utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("joinrequests-usage"))
+			return
+		}
+
+		if option == "enabled" && enabled && !moderation.CheckChatBotRight(ctx, b, message, chat, moderation.RightInviteUsers) {
+			return
+		}
+
+		if err := setJoinOption(chat.ID, option, enabled); err != nil {
+			slog.Error("Couldn't update join request settings",
+				"ChatID", chat.ID,
+				"Option", option,
+				"Error", err.Error())
+			return
+		}
+	}
+
+	settings, err := getJoinSettings(chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get join request settings",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("joinrequests-status", map[string]any{
+		"enabled":    strconv.FormatBool(settings.Enabled),
+		"username":   strconv.FormatBool(settings.ApproveUsername),
+		"gban":       strconv.FormatBool(settings.DeclineGbanned),
+		"captcha":    strconv.FormatBool(settings.Captcha),
+		"logChannel": strconv.FormatBool(moderation.LogChannel(chat.ID) != 0),
+	}))
+}
+
+func Load(b *bot.Bot) {
+	b.RegisterHandlerMatchFunc(func(update *models.Update) bool {
+		return update.ChatJoinRequest != nil
+	}, joinRequestHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "joinrequests", bot.MatchTypeCommand, joinRequestsHandler)
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "joinreq", bot.MatchTypePrefix, reviewCallback)
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "joincaptcha", bot.MatchTypePrefix, captchaCallback)
+
+	utils.Schedule(b, "joinrequests", expiryInterval, getExpiredJoinRequests, expireCaptcha)
+
+	utils.RegisterChatSection("joinrequests", utils.ChatSection{Export: exportJoinRequests, Import: importJoinRequests})
+	utils.SaveHelp("joinrequests")
+}
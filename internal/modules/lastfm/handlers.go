@@ -106,10 +106,11 @@ func lastfm(ctx context.Context, b *bot.Bot, update *models.Update, methodType s
 		text += fmt.Sprintf("\n\n🎙<b>%s</b>", recentTracks.Artist)
 	}
 
-	_, _ = b.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID:    update.Message.Chat.ID,
-		Text:      text,
-		ParseMode: models.ParseModeHTML,
+	reply, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:          update.Message.Chat.ID,
+		MessageThreadID: utils.ChatThread(ctx, update.Message.Chat.ID),
+		Text:            text,
+		ParseMode:       models.ParseModeHTML,
 		LinkPreviewOptions: &models.LinkPreviewOptions{
 			PreferLargeMedia: bot.True(),
 			ShowAboveText:    bot.True(),
@@ -118,6 +119,9 @@ func lastfm(ctx context.Context, b *bot.Bot, update *models.Update, methodType s
 			MessageID: update.Message.ID,
 		},
 	})
+	if err == nil {
+		utils.NotifyReply(ctx, b, reply)
+	}
 }
 
 func Load(b *bot.Bot) {
@@ -134,6 +138,8 @@ func Load(b *bot.Bot) {
 	b.RegisterHandler(bot.HandlerTypeMessageText, "lart", bot.MatchTypeCommand, artistHandler)
 
 	utils.SaveHelp("lastfm")
-	utils.DisableableCommands = append(utils.DisableableCommands,
-		"lastfm", "lmu", "lt", "np", "album", "lalb", "alb", "artist", "lart", "art")
+	utils.RegisterDisableable("lastfm", "lastfm", "album", "artist")
+	utils.RegisterAliases("lastfm", "lmu", "lt", "np")
+	utils.RegisterAliases("album", "alb", "lalb")
+	utils.RegisterAliases("artist", "art", "lart")
 }
@@ -7,26 +7,56 @@ import (
 
 	"github.com/angelomds42/EleineBot/internal/modules/afk"
 	"github.com/angelomds42/EleineBot/internal/modules/android"
+	"github.com/angelomds42/EleineBot/internal/modules/antiraid"
+	"github.com/angelomds42/EleineBot/internal/modules/blocklist"
+	"github.com/angelomds42/EleineBot/internal/modules/captcha"
+	"github.com/angelomds42/EleineBot/internal/modules/cleanup"
+	"github.com/angelomds42/EleineBot/internal/modules/edits"
+	"github.com/angelomds42/EleineBot/internal/modules/filters"
+	"github.com/angelomds42/EleineBot/internal/modules/greetings"
+	"github.com/angelomds42/EleineBot/internal/modules/joinrequests"
 	"github.com/angelomds42/EleineBot/internal/modules/lastfm"
+	"github.com/angelomds42/EleineBot/internal/modules/locks"
 	"github.com/angelomds42/EleineBot/internal/modules/medias"
 	"github.com/angelomds42/EleineBot/internal/modules/menu"
 	"github.com/angelomds42/EleineBot/internal/modules/misc"
 	"github.com/angelomds42/EleineBot/internal/modules/moderation"
+	"github.com/angelomds42/EleineBot/internal/modules/nightmode"
+	"github.com/angelomds42/EleineBot/internal/modules/notes"
+	"github.com/angelomds42/EleineBot/internal/modules/reports"
+	"github.com/angelomds42/EleineBot/internal/modules/rules"
 	"github.com/angelomds42/EleineBot/internal/modules/stickers"
+	"github.com/angelomds42/EleineBot/internal/modules/sudoers"
+	"github.com/angelomds42/EleineBot/internal/modules/topics"
 	"github.com/go-telegram/bot"
 )
 
 var (
 	packageLoadersMutex sync.Mutex
 	packageLoaders      = map[string]func(*bot.Bot){
-		"afk":        afk.Load,
-		"moderation": moderation.Load,
-		"lastfm":     lastfm.Load,
-		"medias":     medias.Load,
-		"menu":       menu.Load,
-		"misc":       misc.Load,
-		"stickers":   stickers.Load,
-		"android":    android.Load,
+		"afk":          afk.Load,
+		"moderation":   moderation.Load,
+		"lastfm":       lastfm.Load,
+		"medias":       medias.Load,
+		"menu":         menu.Load,
+		"misc":         misc.Load,
+		"stickers":     stickers.Load,
+		"android":      android.Load,
+		"locks":        locks.Load,
+		"blocklist":    blocklist.Load,
+		"greetings":    greetings.Load,
+		"captcha":      captcha.Load,
+		"notes":        notes.Load,
+		"filters":      filters.Load,
+		"rules":        rules.Load,
+		"reports":      reports.Load,
+		"sudoers":      sudoers.Load,
+		"antiraid":     antiraid.Load,
+		"nightmode":    nightmode.Load,
+		"joinrequests": joinrequests.Load,
+		"cleanup":      cleanup.Load,
+		"topics":       topics.Load,
+		"edits":        edits.Load,
 	}
 )
 
@@ -0,0 +1,33 @@
+package locks
+
+import (
+	"github.com/angelomds42/EleineBot/internal/database"
+)
+
+func getLocks(chatID int64) (map[string]bool, error) {
+	rows, err := database.DB.Query("SELECT type FROM locks WHERE chat_id = ?;", chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	locks := make(map[string]bool)
+	for rows.Next() {
+		var lockType string
+		if err := rows.Scan(&lockType); err != nil {
+			return nil, err
+		}
+		locks[lockType] = true
+	}
+	return locks, rows.Err()
+}
+
+func insertLock(chatID int64, lockType string) error {
+	_, err := database.DB.Exec("INSERT OR IGNORE INTO locks (chat_id, type) VALUES (?, ?);", chatID, lockType)
+	return err
+}
+
+func deleteLock(chatID int64, lockType string) error {
+	_, err := database.DB.Exec("DELETE FROM locks WHERE chat_id = ? AND type = ?;", chatID, lockType)
+	return err
+}
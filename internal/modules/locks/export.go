@@ -0,0 +1,42 @@
+package locks
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+// exportLocks returns the lock types enabled in chatID.
+func exportLocks(chatID int64) (any, error) {
+	locks, err := getLocks(chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	types := []string{}
+	for _, lockType := range lockTypes {
+		if locks[lockType.name] {
+			types = append(types, lockType.name)
+		}
+	}
+	return types, nil
+}
+
+func importLocks(tx *sql.Tx, chatID int64, data json.RawMessage) error {
+	var types []string
+	if err := json.Unmarshal(data, &types); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM locks WHERE chat_id = ?;", chatID); err != nil {
+		return err
+	}
+	for _, lockType := range types {
+		if !isLockType(lockType) {
+			continue
+		}
+		if _, err := tx.Exec("INSERT OR IGNORE INTO locks (chat_id, type) VALUES (?, ?);", chatID, lockType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+package locks
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/modules/edits"
+	"github.com/angelomds42/EleineBot/internal/modules/moderation"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+var lockTypes = []struct {
+	name  string
+	match func(*models.Message) bool
+}{
+	{"links", hasLink},
+	{"forwards", func(m *models.Message) bool { return m.ForwardOrigin != nil }},
+	{"stickers", func(m *models.Message) bool { return m.Sticker != nil }},
+	{"gifs", func(m *models.Message) bool { return m.Animation != nil }},
+	{"photos", func(m *models.Message) bool { return len(m.Photo) > 0 }},
+	{"videos", func(m *models.Message) bool { return m.Video != nil }},
+	{"documents", func(m *models.Message) bool { return m.Document != nil && m.Animation == nil }},
+	{"audios", func(m *models.Message) bool { return m.Audio != nil }},
+	{"voice", func(m *models.Message) bool { return m.Voice != nil }},
+	{"videonotes", func(m *models.Message) bool { return m.VideoNote != nil }},
+	{"polls", func(m *models.Message) bool { return m.Poll != nil }},
+	{"inline", func(m *models.Message) bool { return m.ViaBot != nil }},
+	{"contacts", func(m *models.Message) bool { return m.Contact != nil }},
+	{"location", func(m *models.Message) bool { return m.Location != nil || m.Venue != nil }},
+	{"games", func(m *models.Message) bool { return m.Game != nil }},
+	{"dice", func(m *models.Message) bool { return m.Dice != nil }},
+}
+
+func hasLink(m *models.Message) bool {
+	for _, entities := range [][]models.MessageEntity{m.Entities, m.CaptionEntities} {
+		for _, entity := range entities {
+			if entity.Type == models.MessageEntityTypeURL || entity.Type == models.MessageEntityTypeTextLink {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isLockType(name string) bool {
+	for _, lockType := range lockTypes {
+		if lockType.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isLocked(message *models.Message, locks map[string]bool) bool {
+	for _, lockType := range lockTypes {
+		if locks[lockType.name] && lockType.match(message) {
+			return true
+		}
+	}
+	return false
+}
+
+func CheckLocksMiddleware(next bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		message := update.Message
+		if edited := edits.EditedMessage(ctx); edited != nil {
+			message = edited
+		}
+		if message == nil || message.From == nil || message.Chat.Type == models.ChatTypePrivate {
+			next(ctx, b, update)
+			return
+		}
+
+		if message.IsAutomaticForward || (message.SenderChat != nil && message.SenderChat.ID == message.Chat.ID) {
+			next(ctx, b, update)
+			return
+		}
+
+		locks, err := getLocks(message.Chat.ID)
+		if err != nil {
+			slog.Error("Couldn't get chat locks",
+				"ChatID", message.Chat.ID,
+				"Error", err.Error())
+			next(ctx, b, update)
+			return
+		}
+
+		if !isLocked(message, locks) || moderation.IsSenderExempt(ctx, b, message) {
+			next(ctx, b, update)
+			return
+		}
+
+		if _, err := b.DeleteMessage(ctx, &bot.DeleteMessageParams{
+			ChatID:    message.Chat.ID,
+			MessageID: message.ID,
+		}); err != nil {
+			slog.Error("Couldn't delete locked message",
+				"ChatID", message.Chat.ID,
+				"MessageID", message.ID,
+				"Error", err.Error())
+		}
+	}
+}
+
+func parseLockArgs(text string) (types []string, invalid string) {
+	for _, arg := range strings.Fields(text)[1:] {
+		arg = strings.ToLower(arg)
+		if arg == "all" {
+			types = types[:0]
+			for _, lockType := range lockTypes {
+				types = append(types, lockType.name)
+			}
+			return types, ""
+		}
+		if !isLockType(arg) {
+			return nil, arg
+		}
+		if !slices.Contains(types, arg) {
+			types = append(types, arg)
+		}
+	}
+	return types, ""
+}
+
+func availableLockTypes() string {
+	names := make([]string, 0, len(lockTypes))
+	for _, lockType := range lockTypes {
+		names = append(names, "<code>"+lockType.name+"</code>")
+	}
+	return strings.Join(names, ", ")
+}
+
+func lockCommand(lock bool) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		message := update.Message
+		i18n := localization.Get(update)
+
+		chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightChangeInfo)
+		if !ok || !moderation.CheckChatBotRight(ctx, b, message, chat, moderation.RightDeleteMessages) {
+			return
+		}
+
+		usageKey := "unlock-usage"
+		if lock {
+			usageKey = "lock-usage"
+		}
+
+		types, invalid := parseLockArgs(message.Text)
+		if invalid != "" {
+			utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("lock-invalid-type", map[string]any{
+				"type":  utils.EscapeHTML(invalid),
+				"types": availableLockTypes(),
+			}))
+			return
+		}
+
+		if len(types) == 0 {
+			utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n(usageKey, map[string]any{
+				"types": availableLockTypes(),
+			}))
+			return
+		}
+
+		for _, lockType := range types {
+			var err error
+			if lock {
+				err = insertLock(chat.ID, lockType)
+			} else {
+				err = deleteLock(chat.ID, lockType)
+			}
+			if err != nil {
+				slog.Error("Couldn't update lock",
+					"ChatID", chat.ID,
+					"Type", lockType,
+					"Error", err.Error())
+				return
+			}
+		}
+
+		respKey := "unlock-success"
+		if lock {
+			respKey = "lock-success"
+		}
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n(respKey, map[string]any{
+			"types": "<code>" + strings.Join(types, "</code>, <code>") + "</code>",
+		}))
+	}
+}
+
+func lockState(locked bool) string {
+	if locked {
+		return "🔒"
+	}
+	return "🔓"
+}
+
+func locksHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.ConnectedGroup(ctx, b, message)
+	if !ok {
+		return
+	}
+
+	locks, err := getLocks(chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get chat locks",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	text := i18n("locks-list")
+	for _, lockType := range lockTypes {
+		text += "\n" + lockState(locks[lockType.name]) + " <code>" + lockType.name + "</code>"
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, text)
+}
+
+func locksConfigCallback(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.CallbackQuery.Message.Message
+
+	chat := moderation.CallbackChat(ctx, b, update.CallbackQuery)
+	if !moderation.CheckChatRightCallback(ctx, b, update.CallbackQuery, chat.ID, moderation.RightChangeInfo) {
+		return
+	}
+
+	locks, err := getLocks(chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get chat locks",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	if lockType := strings.TrimPrefix(update.CallbackQuery.Data, "locksConfig "); isLockType(lockType) {
+		if locks[lockType] {
+			err = deleteLock(chat.ID, lockType)
+		} else {
+			err = insertLock(chat.ID, lockType)
+		}
+		if err != nil {
+			slog.Error("Couldn't update lock",
+				"ChatID", chat.ID,
+				"Type", lockType,
+				"Error", err.Error())
+			return
+		}
+		locks[lockType] = !locks[lockType]
+	}
+
+	i18n := localization.Get(update)
+	buttons := make([][]models.InlineKeyboardButton, 0, len(lockTypes)/2+1)
+	for i := 0; i < len(lockTypes); i += 2 {
+		row := make([]models.InlineKeyboardButton, 0, 2)
+		for _, lockType := range lockTypes[i:min(i+2, len(lockTypes))] {
+			row = append(row, models.InlineKeyboardButton{
+				Text:         lockState(locks[lockType.name]) + " " + i18n("lock-"+lockType.name),
+				CallbackData: "locksConfig " + lockType.name,
+			})
+		}
+		buttons = append(buttons, row)
+	}
+	buttons = append(buttons, []models.InlineKeyboardButton{{
+		Text:         i18n("back-button"),
+		CallbackData: "config",
+	}})
+
+	utils.EditMessage(ctx, b, message.Chat.ID, message.ID,
+		i18n("config-locks"),
+		utils.WithReplyMarkup(&models.InlineKeyboardMarkup{InlineKeyboard: buttons}))
+}
+
+func Load(b *bot.Bot) {
+	b.RegisterHandler(bot.HandlerTypeMessageText, "lock", bot.MatchTypeCommand, lockCommand(true))
+	b.RegisterHandler(bot.HandlerTypeMessageText, "unlock", bot.MatchTypeCommand, lockCommand(false))
+	b.RegisterHandler(bot.HandlerTypeMessageText, "locks", bot.MatchTypeCommand, locksHandler)
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "locksConfig", bot.MatchTypePrefix, locksConfigCallback)
+
+	utils.RegisterChatSection("locks", utils.ChatSection{Export: exportLocks, Import: importLocks})
+	utils.SaveHelp("locks")
+}
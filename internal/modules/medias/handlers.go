@@ -32,7 +32,7 @@ import (
 )
 
 var (
-	mediaRegex = regexp.MustCompile(regexMedia)
+	mediaRegex = utils.MediaLinkRegex
 	cmdRegex   = regexp.MustCompile(`^/(?:s)?dl`)
 	handlerMap = []struct {
 		pattern *regexp.Regexp
@@ -50,7 +50,6 @@ var (
 )
 
 const (
-	regexMedia     = `(?:http(?:s)?://)?(?:m|vm|vt|www|mobile)?(?:.)?(?:(?:instagram|twitter|x|tiktok|reddit|bsky|threads|xiaohongshu|xhslink)\.(?:com|net|app)|youtube\.com/shorts)/(?:\S*)`
 	maxSizeCaption = 1024
 )
 
@@ -81,17 +80,22 @@ func mediaDownloadHandler(ctx context.Context, b *bot.Bot, update *models.Update
 	applyCaption(mediaItems[0], caption)
 
 	b.SendChatAction(ctx, &bot.SendChatActionParams{
-		ChatID: update.Message.Chat.ID,
-		Action: models.ChatActionUploadDocument,
+		ChatID:          update.Message.Chat.ID,
+		MessageThreadID: utils.ChatThread(ctx, update.Message.Chat.ID),
+		Action:          models.ChatActionUploadDocument,
 	})
 	replied, err := b.SendMediaGroup(ctx, &bot.SendMediaGroupParams{
 		ChatID:          update.Message.Chat.ID,
+		MessageThreadID: utils.ChatThread(ctx, update.Message.Chat.ID),
 		Media:           mediaItems,
 		ReplyParameters: &models.ReplyParameters{MessageID: update.Message.ID},
 	})
 	if err != nil {
 		return
 	}
+	for _, message := range replied {
+		utils.NotifyReply(ctx, b, message)
+	}
 	_ = downloader.SetMediaCache(replied, result)
 }
 
@@ -99,10 +103,13 @@ func shouldProcessMedia(update *models.Update, text string) bool {
 	if cmdRegex.MatchString(text) || update.Message.Chat.Type == models.ChatTypePrivate {
 		return true
 	}
+	// A forum topic may override the setting of the group.
 	var mediasAuto bool
-	err := database.DB.QueryRow(
-		"SELECT mediasAuto FROM groups WHERE id = ?;", update.Message.Chat.ID,
-	).Scan(&mediasAuto)
+	err := database.DB.QueryRow(`
+		SELECT COALESCE(t.mediasAuto, g.mediasAuto) FROM groups g
+		LEFT JOIN topicSettings t ON t.chat_id = g.id AND t.thread_id = ?
+		WHERE g.id = ?;
+	`, utils.MessageThread(update.Message), update.Message.Chat.ID).Scan(&mediasAuto)
 	return err == nil && mediasAuto
 }
 
@@ -276,7 +283,7 @@ func youtubeDownloadCallback(ctx context.Context, b *bot.Bot, update *models.Upd
 		action = models.ChatActionUploadVoice
 	}
 	utils.EditMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("uploading"))
-	b.SendChatAction(ctx, &bot.SendChatActionParams{ChatID: msg.Chat.ID, Action: action})
+	b.SendChatAction(ctx, &bot.SendChatActionParams{ChatID: msg.Chat.ID, MessageThreadID: utils.ChatThread(ctx, msg.Chat.ID), Action: action})
 
 	thumbURL := strings.Replace(video.Thumbnails[len(video.Thumbnails)-1].URL, "sddefault", "maxresdefault", 1)
 	thumbBytes, _ := downloader.FetchBytesFromURL(thumbURL)
@@ -287,7 +294,8 @@ func youtubeDownloadCallback(ctx context.Context, b *bot.Bot, update *models.Upd
 	var replied *models.Message
 	if parts[0] == "_aud" {
 		replied, err = b.SendAudio(ctx, &bot.SendAudioParams{
-			ChatID: update.CallbackQuery.Message.Message.Chat.ID,
+			ChatID:          update.CallbackQuery.Message.Message.Chat.ID,
+			MessageThreadID: utils.ChatThread(ctx, update.CallbackQuery.Message.Message.Chat.ID),
 			Audio: &models.InputFileUpload{
 				Filename: filenameBase,
 				Data:     bytes.NewBuffer(fileBytes),
@@ -303,6 +311,7 @@ func youtubeDownloadCallback(ctx context.Context, b *bot.Bot, update *models.Upd
 		format := video.Formats.Itag(partsToInt(parts[2]))[0]
 		replied, err = b.SendVideo(ctx, &bot.SendVideoParams{
 			ChatID:            update.CallbackQuery.Message.Message.Chat.ID,
+			MessageThreadID:   utils.ChatThread(ctx, update.CallbackQuery.Message.Message.Chat.ID),
 			Video:             &models.InputFileUpload{Filename: filenameBase, Data: bytes.NewBuffer(fileBytes)},
 			Width:             format.Width,
 			Height:            format.Height,
@@ -320,6 +329,7 @@ func youtubeDownloadCallback(ctx context.Context, b *bot.Bot, update *models.Upd
 	}
 
 	b.DeleteMessage(ctx, &bot.DeleteMessageParams{ChatID: msg.Chat.ID, MessageID: msg.ID})
+	utils.NotifyReply(ctx, b, replied)
 	if err := downloader.SetYoutubeCache(replied, parts[1]); err != nil {
 		slog.Error("cache set failed", "error", err)
 	}
@@ -363,4 +373,6 @@ func Load(b *bot.Bot) {
 	b.RegisterHandlerRegexp(bot.HandlerTypeCallbackQueryData, regexp.MustCompile(`^(_(vid|aud))`), youtubeDownloadCallback)
 
 	utils.SaveHelp("medias")
+	utils.RegisterDisableable("medias", "dl", "ytdl")
+	utils.RegisterAliases("dl", "sdl")
 }
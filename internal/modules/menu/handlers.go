@@ -36,9 +36,11 @@ func startHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	chatID := update.Message.Chat.ID
 	msgID := update.Message.ID
 	fields := strings.Fields(update.Message.Text)
-	if len(fields) > 1 && fields[1] == "privacy" {
-		privacyHandler(ctx, b, update)
-		return
+	if len(fields) > 1 {
+		if handler, args, ok := utils.GetStartPayload(fields[1]); ok {
+			handler(ctx, b, update, args)
+			return
+		}
 	}
 
 	if update.Message.Chat.Type == models.ChatTypeGroup || update.Message.Chat.Type == models.ChatTypeSupergroup {
@@ -196,4 +198,8 @@ func Load(b *bot.Bot) {
 	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "helpMenu", bot.MatchTypeExact, helpMenuCallback)
 	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "helpMessage", bot.MatchTypePrefix, helpMessageCallback)
 	b.RegisterHandler(bot.HandlerTypeMessageText, "help", bot.MatchTypeCommand, helpHandler)
+
+	utils.RegisterStartPayload("privacy", func(ctx context.Context, b *bot.Bot, update *models.Update, _ string) {
+		privacyHandler(ctx, b, update)
+	})
 }
@@ -307,6 +307,7 @@ func Load(b *bot.Bot) {
 	b.RegisterHandler(bot.HandlerTypeMessageText, "tr", bot.MatchTypeCommand, translateHandler)
 
 	utils.SaveHelp("misc")
-	utils.DisableableCommands = append(utils.DisableableCommands,
-		"tr", "translate", "weather", "clima")
+	utils.RegisterDisableable("misc", "translate", "weather")
+	utils.RegisterAliases("translate", "tr")
+	utils.RegisterAliases("weather", "clima")
 }
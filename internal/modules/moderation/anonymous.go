@@ -0,0 +1,118 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+// anonymousTimeout is how long an anonymous admin has to confirm a command.
+const anonymousTimeout = 5 * time.Minute
+
+type anonymousKey struct {
+	chatID    int64
+	messageID int
+}
+
+type anonymousCommand struct {
+	message *models.Message
+	right   AdminRight
+	expires time.Time
+}
+
+// anonymousCommands holds the commands sent by anonymous admins until one of
+// the admins confirms them.
+var (
+	anonymousCommands      = make(map[anonymousKey]anonymousCommand)
+	anonymousCommandsMutex sync.Mutex
+)
+
+// askAnonymousAdmin asks the admins to confirm msg, a command sent on behalf
+// of the group, which runs as whoever confirms it once their rights are
+// checked.
+func askAnonymousAdmin(ctx context.Context, b *bot.Bot, msg *models.Message, right AdminRight) {
+	i18n := localization.Get(&models.Update{Message: msg})
+
+	now := time.Now()
+	anonymousCommandsMutex.Lock()
+	for key, pending := range anonymousCommands {
+		if now.After(pending.expires) {
+			delete(anonymousCommands, key)
+		}
+	}
+	anonymousCommands[anonymousKey{msg.Chat.ID, msg.ID}] = anonymousCommand{
+		message: msg,
+		right:   right,
+		expires: now.Add(anonymousTimeout),
+	}
+	anonymousCommandsMutex.Unlock()
+
+	utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("anonymous-admin-prompt"),
+		utils.WithReplyMarkupSend(&models.InlineKeyboardMarkup{
+			InlineKeyboard: [][]models.InlineKeyboardButton{{{
+				Text:         i18n("anonymous-admin-button"),
+				CallbackData: fmt.Sprintf("anonAdmin %d", msg.ID),
+			}}},
+		}))
+}
+
+func anonymousAdminCallback(ctx context.Context, b *bot.Bot, update *models.Update) {
+	cb := update.CallbackQuery
+	i18n := localization.Get(update)
+	prompt := cb.Message.Message
+
+	messageID, _ := strconv.Atoi(strings.TrimPrefix(cb.Data, "anonAdmin "))
+	key := anonymousKey{prompt.Chat.ID, messageID}
+
+	anonymousCommandsMutex.Lock()
+	pending, ok := anonymousCommands[key]
+	anonymousCommandsMutex.Unlock()
+	if !ok || time.Now().After(pending.expires) {
+		utils.SendCallbackReply(ctx, b, cb.ID, i18n("anonymous-admin-expired"))
+		deleteAnonymousPrompt(ctx, b, prompt)
+		return
+	}
+
+	if !CheckChatRightCallback(ctx, b, cb, prompt.Chat.ID, pending.right) {
+		return
+	}
+
+	// Only the first admin to confirm runs the command.
+	anonymousCommandsMutex.Lock()
+	_, ok = anonymousCommands[key]
+	delete(anonymousCommands, key)
+	anonymousCommandsMutex.Unlock()
+	if !ok {
+		return
+	}
+
+	b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{CallbackQueryID: cb.ID})
+	deleteAnonymousPrompt(ctx, b, prompt)
+
+	command := *pending.message
+	command.From = &cb.From
+	command.SenderChat = nil
+	b.ProcessUpdate(ctx, &models.Update{Message: &command})
+}
+
+func deleteAnonymousPrompt(ctx context.Context, b *bot.Bot, prompt *models.Message) {
+	if _, err := b.DeleteMessage(ctx, &bot.DeleteMessageParams{
+		ChatID:    prompt.Chat.ID,
+		MessageID: prompt.ID,
+	}); err != nil {
+		slog.Error("Couldn't delete anonymous admin prompt",
+			"ChatID", prompt.Chat.ID,
+			"MessageID", prompt.ID,
+			"Error", err.Error())
+	}
+}
@@ -0,0 +1,207 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+// approvalsCache holds the approved users of each chat, since every
+// automated-moderation middleware looks them up for each message.
+var (
+	approvalsCache      = make(map[int64]map[int64]bool)
+	approvalsCacheMutex sync.RWMutex
+)
+
+func getCachedApprovals(chatID int64) (map[int64]bool, error) {
+	approvalsCacheMutex.RLock()
+	approved, ok := approvalsCache[chatID]
+	approvalsCacheMutex.RUnlock()
+	if ok {
+		return approved, nil
+	}
+
+	users, err := getApprovedUsers(chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	approved = make(map[int64]bool, len(users))
+	for _, userID := range users {
+		approved[userID] = true
+	}
+
+	approvalsCacheMutex.Lock()
+	approvalsCache[chatID] = approved
+	approvalsCacheMutex.Unlock()
+	return approved, nil
+}
+
+func invalidateApprovals(chatID int64) {
+	approvalsCacheMutex.Lock()
+	delete(approvalsCache, chatID)
+	approvalsCacheMutex.Unlock()
+}
+
+// IsApproved reports whether an admin of chatID approved the user, exempting
+// them from automated moderation.
+func IsApproved(chatID, userID int64) bool {
+	approved, err := getCachedApprovals(chatID)
+	if err != nil {
+		slog.Error("Couldn't get approved users",
+			"ChatID", chatID,
+			"Error", err.Error())
+		return false
+	}
+	return approved[userID]
+}
+
+func approveHandler(approve bool) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		msg := update.Message
+		i18n := localization.Get(update)
+
+		chat, ok := SettingsChat(ctx, b, msg, "")
+		if !ok {
+			return
+		}
+
+		userID, _, errMsg := ParseUserTarget(msg)
+		if errMsg != "" {
+			utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n(errMsg))
+			return
+		}
+
+		if approve && IsAdmin(ctx, b, chat.ID, userID) {
+			utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("approve-admin"))
+			return
+		}
+
+		changed, err := setApproved(chat.ID, userID, approve)
+		if err != nil {
+			slog.Error("Couldn't update approval",
+				"ChatID", chat.ID,
+				"UserID", userID,
+				"Error", err.Error())
+			return
+		}
+		invalidateApprovals(chat.ID)
+
+		action := "unapprove"
+		if approve {
+			action = "approve"
+		}
+		respKey := action + "-success"
+		if !changed {
+			respKey = action + "-unchanged"
+		}
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n(respKey, map[string]any{
+			"userFirstName": getUserName(msg, userID),
+		}))
+
+		if changed {
+			SendLog(ctx, b, chat, LogEntry{Action: action, Actor: msg.From, TargetID: userID, Message: msg})
+		}
+	}
+}
+
+func approvedHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := SettingsChat(ctx, b, msg, "")
+	if !ok {
+		return
+	}
+
+	users, err := getApprovedUsers(chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get approved users",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	if len(users) == 0 {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("approved-empty"))
+		return
+	}
+
+	text := i18n("approved-list")
+	for _, userID := range users {
+		text += fmt.Sprintf("\n- <a href='tg://user?id=%d'>%d</a>", userID, userID)
+	}
+	utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, text)
+}
+
+// isChatOwner reports whether userID created chatID. Clearing every approval
+// at once is reserved to them.
+func isChatOwner(ctx context.Context, b *bot.Bot, chatID, userID int64) bool {
+	member, err := b.GetChatMember(ctx, &bot.GetChatMemberParams{ChatID: chatID, UserID: userID})
+	return err == nil && member.Type == models.ChatMemberTypeOwner
+}
+
+// unapproveAllHandler only works in the group itself, unlike the other
+// approval commands: the confirmation buttons act on the chat they're in.
+func unapproveAllHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.Message
+	i18n := localization.Get(update)
+
+	if checkPrivateChat(msg.Chat) {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("group-only"))
+		return
+	}
+
+	if !isChatOwner(ctx, b, msg.Chat.ID, msg.From.ID) {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("user-not-owner"))
+		return
+	}
+
+	utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("unapproveall-confirm"),
+		utils.WithReplyMarkupSend(&models.InlineKeyboardMarkup{
+			InlineKeyboard: [][]models.InlineKeyboardButton{{
+				{Text: i18n("unapproveall-yes-button"), CallbackData: "unapproveall confirm"},
+				{Text: i18n("unapproveall-no-button"), CallbackData: "unapproveall cancel"},
+			}},
+		}))
+}
+
+func unapproveAllCallback(ctx context.Context, b *bot.Bot, update *models.Update) {
+	i18n := localization.Get(update)
+	msg := update.CallbackQuery.Message.Message
+
+	if !isChatOwner(ctx, b, msg.Chat.ID, update.CallbackQuery.From.ID) {
+		utils.SendCallbackReply(ctx, b, update.CallbackQuery.ID, i18n("user-not-owner"))
+		return
+	}
+
+	if strings.TrimPrefix(update.CallbackQuery.Data, "unapproveall ") != "confirm" {
+		b.DeleteMessage(ctx, &bot.DeleteMessageParams{ChatID: msg.Chat.ID, MessageID: msg.ID})
+		return
+	}
+
+	count, err := deleteApprovals(msg.Chat.ID)
+	if err != nil {
+		slog.Error("Couldn't delete approvals",
+			"ChatID", msg.Chat.ID,
+			"Error", err.Error())
+		return
+	}
+	invalidateApprovals(msg.Chat.ID)
+
+	utils.EditMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("unapproveall-success", map[string]any{"count": count}))
+	SendLog(ctx, b, msg.Chat, LogEntry{
+		Action:  "unapproveall",
+		Actor:   &update.CallbackQuery.From,
+		Details: strconv.FormatInt(count, 10),
+	})
+}
@@ -0,0 +1,290 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+const maxRecentConnections = 5
+
+func chatFromInfo(info *models.ChatFullInfo) models.Chat {
+	return models.Chat{ID: info.ID, Type: info.Type, Title: info.Title, Username: info.Username}
+}
+
+// GroupChat fetches the group chatID, for the buttons that act on it from
+// another chat. Only its ID is known when it can't be fetched.
+func GroupChat(ctx context.Context, b *bot.Bot, chatID int64) models.Chat {
+	info, err := b.GetChat(ctx, &bot.GetChatParams{ChatID: chatID})
+	if err != nil {
+		slog.Error("Couldn't get chat",
+			"ChatID", chatID,
+			"Error", err.Error())
+		return models.Chat{ID: chatID, Type: models.ChatTypeSupergroup}
+	}
+	return chatFromInfo(info)
+}
+
+// resolveConnection returns the group userID is connected to, dropping the
+// connection if they're no longer an admin there.
+func resolveConnection(ctx context.Context, b *bot.Bot, userID, chatID int64) (models.Chat, bool) {
+	if !IsAdmin(ctx, b, chatID, userID) {
+		if _, err := deleteConnection(userID); err != nil {
+			slog.Error("Couldn't delete connection",
+				"UserID", userID,
+				"Error", err.Error())
+		}
+		return models.Chat{}, false
+	}
+
+	info, err := b.GetChat(ctx, &bot.GetChatParams{ChatID: chatID})
+	if err != nil {
+		slog.Error("Couldn't get connected chat",
+			"ChatID", chatID,
+			"Error", err.Error())
+		return models.Chat{}, false
+	}
+	return chatFromInfo(info), true
+}
+
+// ConnectedChat returns the chat a settings command acts on: the chat it was
+// sent in or, when sent in private, the group the user connected to.
+func ConnectedChat(ctx context.Context, b *bot.Bot, msg *models.Message) (models.Chat, bool) {
+	if msg.Chat.Type != models.ChatTypePrivate || msg.From == nil {
+		return msg.Chat, false
+	}
+
+	conn, err := getConnection(msg.From.ID)
+	if err != nil {
+		slog.Error("Couldn't get connection",
+			"UserID", msg.From.ID,
+			"Error", err.Error())
+		return msg.Chat, false
+	}
+	if conn == nil {
+		return msg.Chat, false
+	}
+
+	chat, ok := resolveConnection(ctx, b, msg.From.ID, conn.ChatID)
+	if !ok {
+		return msg.Chat, false
+	}
+	return chat, true
+}
+
+// ConnectedGroup is like ConnectedChat, replying that the command is for
+// groups when sent in private without a connection.
+func ConnectedGroup(ctx context.Context, b *bot.Bot, msg *models.Message) (models.Chat, bool) {
+	chat, _ := ConnectedChat(ctx, b, msg)
+	if chat.Type == models.ChatTypePrivate {
+		i18n := localization.Get(&models.Update{Message: msg})
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("group-only"))
+		return chat, false
+	}
+	return chat, true
+}
+
+// SettingsChat returns the group a settings command acts on, as found by
+// ConnectedGroup, checking that the user holds right there.
+func SettingsChat(ctx context.Context, b *bot.Bot, msg *models.Message, right AdminRight) (models.Chat, bool) {
+	chat, ok := ConnectedGroup(ctx, b, msg)
+	return chat, ok && CheckChatRight(ctx, b, msg, chat, right)
+}
+
+// CallbackChat returns the chat a settings button acts on. The buttons of the
+// last /config sent to a connected user act on the connected group.
+func CallbackChat(ctx context.Context, b *bot.Bot, cb *models.CallbackQuery) models.Chat {
+	message := cb.Message.Message
+	if message.Chat.Type != models.ChatTypePrivate {
+		return message.Chat
+	}
+
+	conn, err := getConnection(cb.From.ID)
+	if err != nil {
+		slog.Error("Couldn't get connection",
+			"UserID", cb.From.ID,
+			"Error", err.Error())
+		return message.Chat
+	}
+	if conn == nil || conn.ConfigMessageID != message.ID {
+		return message.Chat
+	}
+
+	chat, ok := resolveConnection(ctx, b, cb.From.ID, conn.ChatID)
+	if !ok {
+		return message.Chat
+	}
+	return chat
+}
+
+// connectUser connects user to the group identified by chatID, an ID or an
+// @username, returning an error key when it isn't possible.
+func connectUser(ctx context.Context, b *bot.Bot, user *models.User, chatID any) (models.Chat, string) {
+	info, err := b.GetChat(ctx, &bot.GetChatParams{ChatID: chatID})
+	if err != nil {
+		return models.Chat{}, "connect-not-found"
+	}
+	if info.Type != models.ChatTypeGroup && info.Type != models.ChatTypeSupergroup {
+		return models.Chat{}, "connect-not-group"
+	}
+	if !IsAdmin(ctx, b, info.ID, user.ID) {
+		return models.Chat{}, "connect-not-admin"
+	}
+
+	if err := setConnection(user.ID, info.ID, info.Title, time.Now().Unix()); err != nil {
+		slog.Error("Couldn't save connection",
+			"UserID", user.ID,
+			"ChatID", info.ID,
+			"Error", err.Error())
+		return models.Chat{}, "connect-failed"
+	}
+	return chatFromInfo(info), ""
+}
+
+func parseChatArg(arg string) any {
+	if id, err := strconv.ParseInt(arg, 10, 64); err == nil {
+		return id
+	}
+	return "@" + strings.TrimPrefix(arg, "@")
+}
+
+func recentConnectionsKeyboard(userID int64) *models.InlineKeyboardMarkup {
+	recent, err := getRecentConnections(userID, maxRecentConnections)
+	if err != nil {
+		slog.Error("Couldn't get recent connections",
+			"UserID", userID,
+			"Error", err.Error())
+		return nil
+	}
+	if len(recent) == 0 {
+		return nil
+	}
+
+	buttons := make([][]models.InlineKeyboardButton, 0, len(recent))
+	for _, r := range recent {
+		title := r.Title
+		if title == "" {
+			title = strconv.FormatInt(r.ChatID, 10)
+		}
+		buttons = append(buttons, []models.InlineKeyboardButton{{
+			Text:         title,
+			CallbackData: fmt.Sprintf("connect %d", r.ChatID),
+		}})
+	}
+	return &models.InlineKeyboardMarkup{InlineKeyboard: buttons}
+}
+
+func connectHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.Message
+	i18n := localization.Get(update)
+
+	if !checkPrivateChat(msg.Chat) {
+		if !CheckUserAdmin(ctx, b, msg) {
+			return
+		}
+		botUser, err := b.GetMe(ctx)
+		if err != nil {
+			slog.Error("GetMe failed", "error", err)
+			return
+		}
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("connect-group"),
+			utils.WithReplyMarkupSend(&models.InlineKeyboardMarkup{
+				InlineKeyboard: [][]models.InlineKeyboardButton{{
+					{Text: i18n("connect-button"), URL: fmt.Sprintf("https://t.me/%s?start=connect_%d", botUser.Username, msg.Chat.ID)},
+				}},
+			}))
+		return
+	}
+
+	if parts := strings.Fields(msg.Text); len(parts) > 1 {
+		chat, errKey := connectUser(ctx, b, msg.From, parseChatArg(parts[1]))
+		if errKey != "" {
+			utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n(errKey))
+			return
+		}
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("connect-success", map[string]any{
+			"chatName": utils.EscapeHTML(chat.Title),
+		}))
+		return
+	}
+
+	text := i18n("connect-usage")
+	if chat, connected := ConnectedChat(ctx, b, msg); connected {
+		text = i18n("connect-current", map[string]any{"chatName": utils.EscapeHTML(chat.Title)}) + "\n\n" + text
+	}
+
+	var opts []func(*bot.SendMessageParams)
+	if keyboard := recentConnectionsKeyboard(msg.From.ID); keyboard != nil {
+		text += "\n\n" + i18n("connect-recent")
+		opts = append(opts, utils.WithReplyMarkupSend(keyboard))
+	}
+	utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, text, opts...)
+}
+
+// connectStartHandler connects the user to the group in args, from the
+// button sent by /connect in that group.
+func connectStartHandler(ctx context.Context, b *bot.Bot, update *models.Update, args string) {
+	msg := update.Message
+	i18n := localization.Get(update)
+
+	chatID, err := strconv.ParseInt(args, 10, 64)
+	if err != nil {
+		return
+	}
+
+	chat, errKey := connectUser(ctx, b, msg.From, chatID)
+	if errKey != "" {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n(errKey))
+		return
+	}
+	utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("connect-success", map[string]any{
+		"chatName": utils.EscapeHTML(chat.Title),
+	}))
+}
+
+func connectCallback(ctx context.Context, b *bot.Bot, update *models.Update) {
+	i18n := localization.Get(update)
+	message := update.CallbackQuery.Message.Message
+
+	chatID, err := strconv.ParseInt(strings.TrimPrefix(update.CallbackQuery.Data, "connect "), 10, 64)
+	if err != nil {
+		return
+	}
+
+	chat, errKey := connectUser(ctx, b, &update.CallbackQuery.From, chatID)
+	if errKey != "" {
+		utils.SendCallbackReply(ctx, b, update.CallbackQuery.ID, i18n(errKey))
+		return
+	}
+	utils.EditMessage(ctx, b, message.Chat.ID, message.ID, i18n("connect-success", map[string]any{
+		"chatName": utils.EscapeHTML(chat.Title),
+	}))
+}
+
+func disconnectHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.Message
+	i18n := localization.Get(update)
+
+	removed, err := deleteConnection(msg.From.ID)
+	if err != nil {
+		slog.Error("Couldn't delete connection",
+			"UserID", msg.From.ID,
+			"Error", err.Error())
+		return
+	}
+
+	respKey := "disconnect-none"
+	if removed {
+		respKey = "disconnect-success"
+	}
+	utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n(respKey))
+}
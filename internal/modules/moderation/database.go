@@ -1,33 +1,308 @@
 package moderation
 
 import (
+	"database/sql"
+	"fmt"
+	"strings"
+
 	"github.com/angelomds42/EleineBot/internal/database"
 )
 
-func getDisabledCommands(chatID int64) ([]string, error) {
-	rows, err := database.DB.Query("SELECT command FROM commandsDisabled WHERE chat_id = ?", chatID)
+// disabledScope returns the table holding the commands disabled in the forum
+// topic threadID of chatID, or in the whole chat for threadID 0, along with
+// the columns and values identifying its rows.
+func disabledScope(chatID int64, threadID int) (table string, columns []string, values []any) {
+	if threadID == 0 {
+		return "commandsDisabled", []string{"chat_id"}, []any{chatID}
+	}
+	return "topicCommandsDisabled", []string{"chat_id", "thread_id"}, []any{chatID, threadID}
+}
+
+// disabledWhere returns the condition selecting the rows of columns.
+func disabledWhere(columns []string) string {
+	return strings.Join(columns, " = ? AND ") + " = ?"
+}
+
+// getDisabledCommands returns the commands disabled in the forum topic
+// threadID of chatID, or in the whole chat for threadID 0, mapped to whether
+// they're only restricted to admins.
+func getDisabledCommands(chatID int64, threadID int) (map[string]bool, error) {
+	table, columns, values := disabledScope(chatID, threadID)
+	rows, err := database.DB.Query(
+		fmt.Sprintf("SELECT command, admins_only FROM %s WHERE %s;", table, disabledWhere(columns)), values...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var commands []string
+	commands := make(map[string]bool)
 	for rows.Next() {
 		var command string
-		if err := rows.Scan(&command); err != nil {
+		var adminsOnly bool
+		if err := rows.Scan(&command, &adminsOnly); err != nil {
 			return nil, err
 		}
-		commands = append(commands, command)
+		commands[command] = adminsOnly
 	}
 	return commands, nil
 }
 
-func insertDisabledCommand(chatID int64, command string) error {
-	_, err := database.DB.Exec("INSERT INTO commandsDisabled (chat_id, command) VALUES (?, ?);", chatID, command)
+// getTopicsDisabledCommands returns the commands disabled in each forum
+// topic of chatID, like getDisabledCommands.
+func getTopicsDisabledCommands(chatID int64) (map[int]map[string]bool, error) {
+	rows, err := database.DB.Query(
+		"SELECT thread_id, command, admins_only FROM topicCommandsDisabled WHERE chat_id = ?;", chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	topics := make(map[int]map[string]bool)
+	for rows.Next() {
+		var threadID int
+		var command string
+		var adminsOnly bool
+		if err := rows.Scan(&threadID, &command, &adminsOnly); err != nil {
+			return nil, err
+		}
+		if topics[threadID] == nil {
+			topics[threadID] = make(map[string]bool)
+		}
+		topics[threadID][command] = adminsOnly
+	}
+	return topics, rows.Err()
+}
+
+// setCommandsState disables commands in the forum topic threadID of chatID,
+// or in the whole chat for threadID 0, or restricts them to admins,
+// returning how many weren't in that state yet.
+func setCommandsState(chatID int64, threadID int, commands []string, adminsOnly bool) (int64, error) {
+	table, columns, values := disabledScope(chatID, threadID)
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s (%[2]s, command, admins_only) VALUES (%[3]s?, ?)
+		ON CONFLICT(%[2]s, command) DO UPDATE SET admins_only = excluded.admins_only
+		WHERE admins_only != excluded.admins_only;
+	`, table, strings.Join(columns, ", "), strings.Repeat("?, ", len(columns)))
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var changed int64
+	for _, command := range commands {
+		result, err := tx.Exec(query, append(values, command, adminsOnly)...)
+		if err != nil {
+			return 0, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		changed += affected
+	}
+	return changed, tx.Commit()
+}
+
+// deleteDisabledCommands enables commands in the forum topic threadID of
+// chatID, or in the whole chat for threadID 0, returning how many were
+// disabled.
+func deleteDisabledCommands(chatID int64, threadID int, commands []string) (int64, error) {
+	table, columns, values := disabledScope(chatID, threadID)
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s AND command = ?;", table, disabledWhere(columns))
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var deleted int64
+	for _, command := range commands {
+		result, err := tx.Exec(query, append(values, command)...)
+		if err != nil {
+			return 0, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		deleted += affected
+	}
+	return deleted, tx.Commit()
+}
+
+func clearDisabledCommands(chatID int64, threadID int) (int64, error) {
+	table, columns, values := disabledScope(chatID, threadID)
+	result, err := database.DB.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s;", table, disabledWhere(columns)), values...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func setDisabledDelete(chatID int64, enabled bool) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO disabledSettings (chat_id, delete_commands) VALUES (?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET delete_commands = excluded.delete_commands;
+	`, chatID, enabled)
 	return err
 }
 
-func deleteDisabledCommand(command string) error {
-	_, err := database.DB.Exec("DELETE FROM commandsDisabled WHERE command = ?;", command)
+func getUserIDByUsername(username string) (int64, error) {
+	var id int64
+	err := database.DB.QueryRow("SELECT id FROM users WHERE username = ?;", username).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return id, err
+}
+
+func getLogChannel(chatID int64) (int64, error) {
+	var channelID int64
+	err := database.DB.QueryRow("SELECT channel_id FROM logChannels WHERE chat_id = ?;", chatID).Scan(&channelID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return channelID, err
+}
+
+func setLogChannel(chatID, channelID int64) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO logChannels (chat_id, channel_id) VALUES (?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET channel_id = excluded.channel_id;
+	`, chatID, channelID)
+	return err
+}
+
+func deleteLogChannel(chatID int64) (bool, error) {
+	result, err := database.DB.Exec("DELETE FROM logChannels WHERE chat_id = ?;", chatID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func getApprovedUsers(chatID int64) ([]int64, error) {
+	rows, err := database.DB.Query("SELECT user_id FROM approvals WHERE chat_id = ?;", chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []int64
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		users = append(users, userID)
+	}
+	return users, rows.Err()
+}
+
+func setApproved(chatID, userID int64, approved bool) (bool, error) {
+	query := "DELETE FROM approvals WHERE chat_id = ? AND user_id = ?;"
+	if approved {
+		query = "INSERT OR IGNORE INTO approvals (chat_id, user_id) VALUES (?, ?);"
+	}
+	result, err := database.DB.Exec(query, chatID, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func deleteApprovals(chatID int64) (int64, error) {
+	result, err := database.DB.Exec("DELETE FROM approvals WHERE chat_id = ?;", chatID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+type connection struct {
+	ChatID          int64
+	ConfigMessageID int
+}
+
+type recentConnection struct {
+	ChatID int64
+	Title  string
+}
+
+func getConnection(userID int64) (*connection, error) {
+	var c connection
+	err := database.DB.QueryRow(
+		"SELECT chat_id, config_message_id FROM connections WHERE user_id = ?;", userID,
+	).Scan(&c.ChatID, &c.ConfigMessageID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func setConnection(userID, chatID int64, title string, now int64) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT INTO connections (user_id, chat_id, config_message_id) VALUES (?, ?, 0)
+		ON CONFLICT(user_id) DO UPDATE SET chat_id = excluded.chat_id, config_message_id = 0;
+	`, userID, chatID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO connectionHistory (user_id, chat_id, title, last_used) VALUES (?, ?, ?, ?)
+		ON CONFLICT(user_id, chat_id) DO UPDATE SET title = excluded.title, last_used = excluded.last_used;
+	`, userID, chatID, title, now); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func setConnectionConfigMessage(userID int64, messageID int) error {
+	_, err := database.DB.Exec("UPDATE connections SET config_message_id = ? WHERE user_id = ?;", messageID, userID)
 	return err
 }
+
+func deleteConnection(userID int64) (bool, error) {
+	result, err := database.DB.Exec("DELETE FROM connections WHERE user_id = ?;", userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func getRecentConnections(userID int64, limit int) ([]recentConnection, error) {
+	rows, err := database.DB.Query(`
+		SELECT chat_id, title FROM connectionHistory
+		WHERE user_id = ? ORDER BY last_used DESC LIMIT ?;
+	`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recent []recentConnection
+	for rows.Next() {
+		var r recentConnection
+		var title sql.NullString
+		if err := rows.Scan(&r.ChatID, &title); err != nil {
+			return nil, err
+		}
+		r.Title = title.String
+		recent = append(recent, r)
+	}
+	return recent, rows.Err()
+}
@@ -0,0 +1,305 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/database"
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+// maxImportSize bounds the documents /import downloads.
+const maxImportSize = 1 << 20
+
+type chatSection struct {
+	Language      string `json:"language"`
+	MediasAuto    bool   `json:"medias_auto"`
+	MediasCaption bool   `json:"medias_caption"`
+}
+
+type disabledCommand struct {
+	Command    string `json:"command"`
+	AdminsOnly bool   `json:"admins_only"`
+}
+
+// topicDisabledCommands are the commands disabled in a forum topic.
+type topicDisabledCommands struct {
+	ThreadID int               `json:"thread_id"`
+	Commands []disabledCommand `json:"commands"`
+}
+
+type disabledSection struct {
+	Commands       []disabledCommand       `json:"commands"`
+	Topics         []topicDisabledCommands `json:"topics"`
+	DeleteCommands bool                    `json:"delete_commands"`
+}
+
+func exportChatSection(chatID int64) (any, error) {
+	section := chatSection{Language: "en-us", MediasAuto: true, MediasCaption: true}
+	err := database.DB.QueryRow(
+		"SELECT language, mediasAuto, mediasCaption FROM groups WHERE id = ?;", chatID,
+	).Scan(&section.Language, &section.MediasAuto, &section.MediasCaption)
+	if err == sql.ErrNoRows {
+		return section, nil
+	}
+	return section, err
+}
+
+func importChatSection(tx *sql.Tx, chatID int64, data json.RawMessage) error {
+	section := chatSection{MediasAuto: true, MediasCaption: true}
+	if err := json.Unmarshal(data, &section); err != nil {
+		return err
+	}
+	if !slices.Contains(database.AvailableLocales, section.Language) {
+		section.Language = "en-us"
+	}
+	_, err := tx.Exec(`
+		INSERT INTO groups (id, language, mediasAuto, mediasCaption) VALUES (?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			language = excluded.language,
+			mediasAuto = excluded.mediasAuto,
+			mediasCaption = excluded.mediasCaption;
+	`, chatID, section.Language, section.MediasAuto, section.MediasCaption)
+	return err
+}
+
+func exportDisabledSection(chatID int64) (any, error) {
+	commands, err := getDisabledCommands(chatID, 0)
+	if err != nil {
+		return nil, err
+	}
+	topics, err := getTopicsDisabledCommands(chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	section := disabledSection{
+		Commands:       sortedDisabledCommands(commands),
+		Topics:         make([]topicDisabledCommands, 0, len(topics)),
+		DeleteCommands: utils.GetDisabledDelete(chatID),
+	}
+	for threadID, commands := range topics {
+		section.Topics = append(section.Topics, topicDisabledCommands{
+			ThreadID: threadID,
+			Commands: sortedDisabledCommands(commands),
+		})
+	}
+	slices.SortFunc(section.Topics, func(a, b topicDisabledCommands) int {
+		return a.ThreadID - b.ThreadID
+	})
+	return section, nil
+}
+
+// sortedDisabledCommands lists commands, as returned by getDisabledCommands,
+// sorted by name.
+func sortedDisabledCommands(commands map[string]bool) []disabledCommand {
+	disabled := make([]disabledCommand, 0, len(commands))
+	for command, adminsOnly := range commands {
+		disabled = append(disabled, disabledCommand{Command: command, AdminsOnly: adminsOnly})
+	}
+	slices.SortFunc(disabled, func(a, b disabledCommand) int {
+		return strings.Compare(a.Command, b.Command)
+	})
+	return disabled
+}
+
+func importDisabledSection(tx *sql.Tx, chatID int64, data json.RawMessage) error {
+	var section disabledSection
+	if err := json.Unmarshal(data, &section); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM commandsDisabled WHERE chat_id = ?;", chatID); err != nil {
+		return err
+	}
+	for _, disabled := range section.Commands {
+		// Commands that are no longer disableable are dropped.
+		command := utils.ResolveDisableable(disabled.Command)
+		if command == "" {
+			continue
+		}
+		if _, err := tx.Exec(
+			"INSERT OR REPLACE INTO commandsDisabled (chat_id, command, admins_only) VALUES (?, ?, ?);",
+			chatID, command, disabled.AdminsOnly,
+		); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("DELETE FROM topicCommandsDisabled WHERE chat_id = ?;", chatID); err != nil {
+		return err
+	}
+	for _, topic := range section.Topics {
+		if topic.ThreadID <= 0 {
+			continue
+		}
+		for _, disabled := range topic.Commands {
+			command := utils.ResolveDisableable(disabled.Command)
+			if command == "" {
+				continue
+			}
+			if _, err := tx.Exec(
+				"INSERT OR REPLACE INTO topicCommandsDisabled (chat_id, thread_id, command, admins_only) VALUES (?, ?, ?, ?);",
+				chatID, topic.ThreadID, command, disabled.AdminsOnly,
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO disabledSettings (chat_id, delete_commands) VALUES (?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET delete_commands = excluded.delete_commands;
+	`, chatID, section.DeleteCommands)
+	return err
+}
+
+func exportApprovalsSection(chatID int64) (any, error) {
+	users, err := getApprovedUsers(chatID)
+	if users == nil {
+		users = []int64{}
+	}
+	return users, err
+}
+
+func importApprovalsSection(tx *sql.Tx, chatID int64, data json.RawMessage) error {
+	var users []int64
+	if err := json.Unmarshal(data, &users); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM approvals WHERE chat_id = ?;", chatID); err != nil {
+		return err
+	}
+	for _, userID := range users {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO approvals (chat_id, user_id) VALUES (?, ?);", chatID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportChat returns the group an /export or /import acts on, replying why
+// not when there's none or the user lacks right there.
+func exportChat(ctx context.Context, b *bot.Bot, msg *models.Message, right AdminRight) (models.Chat, bool) {
+	chat, _ := ConnectedChat(ctx, b, msg)
+	if checkPrivateChat(chat) {
+		i18n := localization.Get(&models.Update{Message: msg})
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("export-no-group"))
+		return chat, false
+	}
+	return chat, CheckChatRight(ctx, b, msg, chat, right)
+}
+
+func exportHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := exportChat(ctx, b, msg, "")
+	if !ok {
+		return
+	}
+
+	export, err := utils.ExportChat(chat.ID)
+	if err != nil {
+		slog.Error("Couldn't export chat",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		slog.Error("Couldn't encode chat export",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	if _, err := b.SendDocument(ctx, &bot.SendDocumentParams{
+		ChatID:          msg.Chat.ID,
+		MessageThreadID: utils.ChatThread(ctx, msg.Chat.ID),
+		Document: &models.InputFileUpload{
+			Filename: fmt.Sprintf("config%d.json", chat.ID),
+			Data:     bytes.NewReader(data),
+		},
+		Caption:         i18n("export-caption", map[string]any{"chatName": utils.EscapeHTML(chat.Title)}),
+		ParseMode:       models.ParseModeHTML,
+		ReplyParameters: &models.ReplyParameters{MessageID: msg.ID},
+	}); err != nil {
+		slog.Error("Couldn't send chat export",
+			"ChatID", msg.Chat.ID,
+			"Error", err.Error())
+	}
+}
+
+func importHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.Message
+	i18n := localization.Get(update)
+
+	// Importing replaces the settings of the whole group.
+	chat, ok := exportChat(ctx, b, msg, RightChangeInfo)
+	if !ok {
+		return
+	}
+
+	if msg.ReplyToMessage == nil || msg.ReplyToMessage.Document == nil {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("import-usage"))
+		return
+	}
+	if msg.ReplyToMessage.Document.FileSize > maxImportSize {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("import-invalid"))
+		return
+	}
+
+	data, err := utils.DownloadDocument(ctx, b, msg.ReplyToMessage.Document)
+	if err != nil {
+		slog.Error("Couldn't download chat export",
+			"ChatID", msg.Chat.ID,
+			"Error", err.Error())
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("import-invalid"))
+		return
+	}
+
+	var export utils.ChatExport
+	if err := json.Unmarshal(data, &export); err != nil || export.Version < 1 || export.Sections == nil {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("import-invalid"))
+		return
+	}
+
+	sections, err := utils.ImportChat(chat.ID, export)
+	switch {
+	case errors.Is(err, utils.ErrUnsupportedExport):
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("import-unsupported", map[string]any{
+			"version": export.Version,
+		}))
+		return
+	case err != nil:
+		slog.Error("Couldn't import chat export",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("import-invalid"))
+		return
+	}
+
+	SendLog(ctx, b, chat, LogEntry{
+		Action:  "import",
+		Actor:   msg.From,
+		Details: utils.EscapeHTML(strings.Join(sections, ", ")),
+	})
+	utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("import-success", map[string]any{
+		"chatName": utils.EscapeHTML(chat.Title),
+		"sections": utils.EscapeHTML(strings.Join(sections, ", ")),
+	}))
+}
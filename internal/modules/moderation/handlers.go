@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
@@ -19,22 +21,25 @@ import (
 	"github.com/angelomds42/EleineBot/internal/utils"
 )
 
-func parseUserRestriction(msg *models.Message) (userID int64, until int, errMsg string) {
+// ParseUserTarget reads the user a command acts on, from the replied message,
+// an ID or a mention, returning the remaining arguments.
+func ParseUserTarget(msg *models.Message) (userID int64, args []string, errMsg string) {
 	parts := strings.Fields(msg.Text)
 
-	if msg.ReplyToMessage != nil && msg.ReplyToMessage.From != nil {
-		userID = msg.ReplyToMessage.From.ID
-		if len(parts) >= 2 {
-			dur, err := utils.ParseCustomDuration(parts[1])
-			if err == nil {
-				until = int(time.Now().Add(dur).Unix())
-			}
+	if reply := msg.ReplyToMessage; reply != nil && reply.SenderChat != nil {
+		// From is a placeholder bot on messages sent on behalf of a chat.
+		if reply.SenderChat.ID == msg.Chat.ID {
+			return 0, nil, "id-anonymous"
 		}
-		return userID, until, ""
+		return 0, nil, "id-channel"
+	}
+
+	if msg.ReplyToMessage != nil && msg.ReplyToMessage.From != nil {
+		return msg.ReplyToMessage.From.ID, parts[1:], ""
 	}
 
 	if len(parts) < 2 {
-		return 0, 0, "id-required"
+		return 0, nil, "id-required"
 	}
 
 	arg := parts[1]
@@ -43,89 +48,363 @@ func parseUserRestriction(msg *models.Message) (userID int64, until int, errMsg
 		userID = id
 	} else {
 		for _, ent := range msg.Entities {
+			off := ent.Offset
+			ln := ent.Length
+			if off+ln > len(msg.Text) || msg.Text[off:off+ln] != arg {
+				continue
+			}
+
 			if ent.Type == "text_mention" && ent.User != nil {
-				off := ent.Offset
-				ln := ent.Length
-				if off+ln <= len(msg.Text) && msg.Text[off:off+ln] == arg {
-					userID = ent.User.ID
-					break
+				userID = ent.User.ID
+				break
+			}
+
+			if ent.Type == "mention" {
+				id, err := getUserIDByUsername(arg)
+				if err != nil {
+					slog.Error("Couldn't get user ID from username",
+						"Username", arg,
+						"Error", err.Error())
 				}
+				userID = id
+				break
 			}
 		}
 	}
 
 	if userID == 0 {
-		return 0, 0, "id-invalid"
+		return 0, nil, "id-invalid"
 	}
 
-	if len(parts) >= 3 {
-		dur, err := utils.ParseCustomDuration(parts[2])
+	return userID, parts[2:], ""
+}
+
+// parseUserRestriction reads the target user, an optional duration and the
+// reason, which is everything after them. Replies to messages sent on
+// behalf of a chat target that chat.
+func parseUserRestriction(msg *models.Message) (userID int64, until int, reason string, errMsg string) {
+	var args []string
+	if reply := msg.ReplyToMessage; reply != nil && reply.SenderChat != nil {
+		userID, args = reply.SenderChat.ID, strings.Fields(msg.Text)[1:]
+	} else if userID, args, errMsg = ParseUserTarget(msg); errMsg != "" {
+		return 0, 0, "", errMsg
+	}
+
+	if len(args) >= 1 {
+		dur, err := utils.ParseCustomDuration(args[0])
 		if err == nil {
 			until = int(time.Now().Add(dur).Unix())
+			args = args[1:]
 		}
 	}
 
-	return userID, until, ""
+	return userID, until, strings.Join(args, " "), ""
+}
+
+type AdminRight string
+
+const (
+	RightPinMessages     AdminRight = "pin-messages"
+	RightPromoteMembers  AdminRight = "promote-members"
+	RightChangeInfo      AdminRight = "change-info"
+	RightDeleteMessages  AdminRight = "delete-messages"
+	RightRestrictMembers AdminRight = "restrict-members"
+	RightInviteUsers     AdminRight = "invite-users"
+	RightManageTopics    AdminRight = "manage-topics"
+)
+
+func hasAdminRight(admin *models.ChatMemberAdministrator, right AdminRight) bool {
+	switch right {
+	case RightPinMessages:
+		return admin.CanPinMessages
+	case RightPromoteMembers:
+		return admin.CanPromoteMembers
+	case RightChangeInfo:
+		return admin.CanChangeInfo
+	case RightDeleteMessages:
+		return admin.CanDeleteMessages
+	case RightRestrictMembers:
+		return admin.CanRestrictMembers
+	case RightInviteUsers:
+		return admin.CanInviteUsers
+	case RightManageTopics:
+		return admin.CanManageTopics
+	}
+	return true
+}
+
+func fullAdminRights() *models.ChatMemberAdministrator {
+	return &models.ChatMemberAdministrator{
+		CanManageChat:       true,
+		CanDeleteMessages:   true,
+		CanManageVideoChats: true,
+		CanRestrictMembers:  true,
+		CanPromoteMembers:   true,
+		CanChangeInfo:       true,
+		CanInviteUsers:      true,
+		CanPinMessages:      true,
+		CanManageTopics:     true,
+	}
 }
 
-func checkUserAdmin(ctx context.Context, b *bot.Bot, msg *models.Message) bool {
+func getAdminRights(ctx context.Context, b *bot.Bot, chatID, userID int64) *models.ChatMemberAdministrator {
+	member, err := b.GetChatMember(ctx, &bot.GetChatMemberParams{
+		ChatID: chatID,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil
+	}
+
+	switch member.Type {
+	case models.ChatMemberTypeOwner:
+		return fullAdminRights()
+	case models.ChatMemberTypeAdministrator:
+		return member.Administrator
+	}
+	return nil
+}
+
+func memberRights(ctx context.Context, b *bot.Bot, chatID, userID int64, right AdminRight) (isAdmin, hasRight bool) {
+	admin := getAdminRights(ctx, b, chatID, userID)
+	if admin == nil {
+		return false, false
+	}
+	return true, hasAdminRight(admin, right)
+}
+
+func CheckUserAdmin(ctx context.Context, b *bot.Bot, msg *models.Message) bool {
+	return CheckUserRight(ctx, b, msg, "")
+}
+
+func CheckUserRight(ctx context.Context, b *bot.Bot, msg *models.Message, right AdminRight) bool {
+	if utils.IsAnonymousAdmin(msg) {
+		askAnonymousAdmin(ctx, b, msg, right)
+		return false
+	}
+
+	isAdmin, hasRight := memberRights(ctx, b, msg.Chat.ID, msg.From.ID, right)
+	return replyMissingRight(ctx, b, msg, "user", right, isAdmin, hasRight)
+}
+
+// CheckChatRight is like CheckUserRight, but checks the rights in chat, which
+// is the connected group when msg was sent in private.
+func CheckChatRight(ctx context.Context, b *bot.Bot, msg *models.Message, chat models.Chat, right AdminRight) bool {
+	if chat.ID == msg.Chat.ID {
+		return CheckUserRight(ctx, b, msg, right)
+	}
+
+	isAdmin, hasRight := memberRights(ctx, b, chat.ID, msg.From.ID, right)
+	return replyMissingRight(ctx, b, msg, "user", right, isAdmin, hasRight)
+}
+
+// replyMissingRight tells why the user or the bot, as who, can't go ahead
+// unless they're an admin with the right.
+func replyMissingRight(ctx context.Context, b *bot.Bot, msg *models.Message, who string, right AdminRight, isAdmin, hasRight bool) bool {
 	i18n := localization.Get(&models.Update{Message: msg})
-	if !IsAdmin(ctx, b, msg.Chat.ID, msg.From.ID) {
-		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("user-not-admin"))
+	if !isAdmin {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n(who+"-not-admin"))
+		return false
+	}
+	if !hasRight {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID,
+			i18n(who+"-missing-right", map[string]any{"right": i18n("right-" + string(right))}))
 		return false
 	}
 	return true
 }
 
-func checkAdminCallback(ctx context.Context, b *bot.Bot, cb *models.CallbackQuery) bool {
+func CheckAdminCallback(ctx context.Context, b *bot.Bot, cb *models.CallbackQuery) bool {
+	return CheckRightCallback(ctx, b, cb, "")
+}
+
+func CheckRightCallback(ctx context.Context, b *bot.Bot, cb *models.CallbackQuery, right AdminRight) bool {
+	return CheckChatRightCallback(ctx, b, cb, cb.Message.Message.Chat.ID, right)
+}
+
+// CheckChatRightCallback is like CheckRightCallback, but checks the rights in
+// chatID, for buttons sent outside the chat they act on.
+func CheckChatRightCallback(ctx context.Context, b *bot.Bot, cb *models.CallbackQuery, chatID int64, right AdminRight) bool {
+	isAdmin, hasRight := memberRights(ctx, b, chatID, cb.From.ID, right)
+	return answerMissingRight(ctx, b, cb, "user", right, isAdmin, hasRight)
+}
+
+// CheckChatBotRightCallback is like CheckChatBotRight, for buttons.
+func CheckChatBotRightCallback(ctx context.Context, b *bot.Bot, cb *models.CallbackQuery, chatID int64, right AdminRight) bool {
+	botID, err := utils.GetBotID(ctx, b)
+	if err != nil {
+		return answerMissingRight(ctx, b, cb, "bot", right, false, false)
+	}
+
+	isAdmin, hasRight := memberRights(ctx, b, chatID, botID, right)
+	return answerMissingRight(ctx, b, cb, "bot", right, isAdmin, hasRight)
+}
+
+// answerMissingRight is like replyMissingRight, answering the button instead.
+func answerMissingRight(ctx context.Context, b *bot.Bot, cb *models.CallbackQuery, who string, right AdminRight, isAdmin, hasRight bool) bool {
 	i18n := localization.Get(&models.Update{CallbackQuery: cb})
-	msg := cb.Message.Message
-	if !IsAdmin(ctx, b, msg.Chat.ID, cb.From.ID) {
-		utils.SendCallbackReply(ctx, b, cb.ID, i18n("user-not-admin"))
+	if !isAdmin {
+		utils.SendCallbackReply(ctx, b, cb.ID, i18n(who+"-not-admin"))
+		return false
+	}
+	if !hasRight {
+		utils.SendCallbackReply(ctx, b, cb.ID,
+			i18n(who+"-missing-right", map[string]any{"right": i18n("right-" + string(right))}))
 		return false
 	}
 	return true
 }
 
-func checkBotAdmin(ctx context.Context, b *bot.Bot, msg *models.Message) bool {
-	i18n := localization.Get(&models.Update{Message: msg})
+func CheckBotAdmin(ctx context.Context, b *bot.Bot, msg *models.Message) bool {
+	return CheckBotRight(ctx, b, msg, "")
+}
+
+func CheckBotRight(ctx context.Context, b *bot.Bot, msg *models.Message, right AdminRight) bool {
+	return CheckChatBotRight(ctx, b, msg, msg.Chat, right)
+}
+
+// CheckChatBotRight is like CheckBotRight, but checks the rights in chat,
+// which is the connected group when msg was sent in private.
+func CheckChatBotRight(ctx context.Context, b *bot.Bot, msg *models.Message, chat models.Chat, right AdminRight) bool {
 	botID, err := utils.GetBotID(ctx, b)
-	if err != nil || (!IsAdmin(ctx, b, msg.Chat.ID, botID)) {
-		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("bot-not-admin", nil))
-		return false
+	if err != nil {
+		return replyMissingRight(ctx, b, msg, "bot", right, false, false)
 	}
-	return true
+
+	isAdmin, hasRight := memberRights(ctx, b, chat.ID, botID, right)
+	return replyMissingRight(ctx, b, msg, "bot", right, isAdmin, hasRight)
 }
 
 func checkPrivateChat(chat models.Chat) bool {
 	return chat.Type == models.ChatTypePrivate
 }
 
-func checkPrivateChatCallback(cb *models.CallbackQuery) bool {
-	return cb.Message.Message.Chat.Type == models.ChatTypePrivate
+func IsAdmin(ctx context.Context, b *bot.Bot, chatID int64, userID int64) bool {
+	isAdmin, _ := memberRights(ctx, b, chatID, userID, "")
+	return isAdmin
 }
 
-func IsAdmin(ctx context.Context, b *bot.Bot, chatID int64, userID int64) bool {
-	member, err := b.GetChatMember(ctx, &bot.GetChatMemberParams{
-		ChatID: chatID,
-		UserID: userID,
-	})
-	return err == nil && (member.Type == models.ChatMemberTypeAdministrator || member.Type == models.ChatMemberTypeOwner)
+// IsExempt reports whether automated moderation, such as locks or the
+// blocklist, should leave the user alone: admins and approved users.
+func IsExempt(ctx context.Context, b *bot.Bot, chatID int64, userID int64) bool {
+	return IsApproved(chatID, userID) || IsAdmin(ctx, b, chatID, userID)
+}
+
+// IsSenderExempt is like IsExempt for the sender of msg. Messages sent as a
+// channel come from a placeholder user, so the channel itself is checked,
+// which can only be approved.
+func IsSenderExempt(ctx context.Context, b *bot.Bot, msg *models.Message) bool {
+	if msg.SenderChat != nil {
+		return IsApproved(msg.Chat.ID, msg.SenderChat.ID)
+	}
+	return IsExempt(ctx, b, msg.Chat.ID, msg.From.ID)
 }
 
 func getUserName(msg *models.Message, userID int64) string {
+	if msg.ReplyToMessage != nil && msg.ReplyToMessage.SenderChat != nil {
+		return utils.EscapeHTML(msg.ReplyToMessage.SenderChat.Title)
+	}
 	if msg.ReplyToMessage != nil && msg.ReplyToMessage.From != nil {
 		return utils.EscapeHTML(msg.ReplyToMessage.From.FirstName)
 	}
 	return strconv.FormatInt(userID, 10)
 }
 
+// commandWithAliases formats command for the command lists, followed by
+// its aliases.
+func commandWithAliases(command string) string {
+	text := "<code>" + utils.EscapeHTML(command) + "</code>"
+	if aliases := utils.CommandAliases(command); len(aliases) > 0 {
+		text += " (" + utils.EscapeHTML(strings.Join(aliases, ", ")) + ")"
+	}
+	return text
+}
+
+func formatCommands(commands []string) string {
+	formatted := make([]string, len(commands))
+	for i, command := range commands {
+		formatted[i] = "<code>" + utils.EscapeHTML(command) + "</code>"
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// resolveCommandArgs returns the commands named in args, by name or alias,
+// all of them for "all", or those of the modules listed after "module".
+// An unknown module is returned as invalid, and so is an unknown command
+// when strict; otherwise it's kept as is, so stale entries can be enabled.
+func resolveCommandArgs(args []string, strict bool) (commands []string, invalid string) {
+	if strings.EqualFold(args[0], "all") {
+		return utils.AllDisableableCommands(), ""
+	}
+
+	modules := strings.EqualFold(args[0], "module")
+	if modules {
+		args = args[1:]
+	}
+
+	for _, arg := range args {
+		var resolved []string
+		if modules {
+			resolved = utils.ModuleCommands(arg)
+		} else if command := utils.ResolveDisableable(arg); command != "" {
+			resolved = []string{command}
+		}
+
+		if resolved == nil {
+			if strict || modules {
+				return nil, arg
+			}
+			resolved = []string{strings.ToLower(strings.TrimPrefix(arg, "/"))}
+		}
+		for _, command := range resolved {
+			if !slices.Contains(commands, command) {
+				commands = append(commands, command)
+			}
+		}
+	}
+	return commands, ""
+}
+
+// commandStateMark returns the mark shown next to a command in the lists,
+// given whether it's disabled and whether only for non-admins.
+func commandStateMark(disabled, adminsOnly bool) string {
+	switch {
+	case !disabled:
+		return "✅"
+	case adminsOnly:
+		return "🛡"
+	}
+	return "🚫"
+}
+
 func disableableHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	i18n := localization.Get(update)
 	text := i18n("disableables-commands")
 
-	for _, command := range utils.DisableableCommands {
-		text += "\n- <code>" + command + "</code>"
+	// In groups, each command is marked with its state there.
+	var states map[string]bool
+	if chat, _ := ConnectedChat(ctx, b, update.Message); !checkPrivateChat(chat) {
+		var err error
+		if states, err = getDisabledCommands(chat.ID, 0); err != nil {
+			slog.Error("Error getting disabled commands", "error", err)
+			return
+		}
+	}
+
+	for _, module := range utils.DisableableModules() {
+		text += "\n\n<b>" + utils.EscapeHTML(module) + "</b>"
+		for _, command := range utils.ModuleCommands(module) {
+			mark := "-"
+			if states != nil {
+				adminsOnly, disabled := states[command]
+				mark = commandStateMark(disabled, adminsOnly)
+			}
+			text += "\n" + mark + " " + commandWithAliases(command)
+		}
+	}
+	text += "\n\n" + i18n("disableables-hint")
+	if states != nil {
+		text += "\n" + i18n("command-states-legend")
 	}
 
 	utils.SendMessage(ctx, b, update.Message.Chat.ID, update.Message.ID, text,
@@ -138,103 +417,294 @@ func disableableHandler(ctx context.Context, b *bot.Bot, update *models.Update)
 		})))
 }
 
-func disableHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
-	i18n := localization.Get(update)
-	contains := func(array []string, str string) bool {
-		for _, item := range array {
-			if item == str {
-				return true
-			}
-		}
-		return false
+// disableHandler disables commands for everyone or, when adminsOnly,
+// restricts them to the admins of the chat.
+func disableHandler(adminsOnly bool) bot.HandlerFunc {
+	action, state := "disable", "disabled"
+	if adminsOnly {
+		action, state = "restrict", "restricted"
 	}
 
-	if !checkUserAdmin(ctx, b, update.Message) {
-		return
-	}
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		msg := update.Message
+		i18n := localization.Get(update)
 
-	if len(strings.Fields(update.Message.Text)) > 1 {
-		command := strings.Fields(update.Message.Text)[1]
-		if !contains(utils.DisableableCommands, command) {
-			utils.SendMessage(ctx, b, update.Message.Chat.ID, update.Message.ID,
-				i18n("command-not-deactivatable", map[string]interface{}{"command": command}))
+		chat, connected := ConnectedChat(ctx, b, msg)
+		if !connected && !CheckUserAdmin(ctx, b, msg) {
+			return
+		}
+
+		threadID, args, ok := topicScope(ctx, b, msg, connected, strings.Fields(msg.Text)[1:])
+		if !ok {
+			return
+		}
+		if len(args) == 0 {
+			utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n(action+"-commands-usage"))
 			return
 		}
 
-		if utils.CheckDisabledCommand(command, update.Message.Chat.ID) {
-			utils.SendMessage(ctx, b, update.Message.Chat.ID, update.Message.ID,
-				i18n("command-already-disabled", map[string]interface{}{"command": command}))
+		commands, invalid := resolveCommandArgs(args, true)
+		if invalid != "" {
+			errKey := "command-not-deactivatable"
+			if strings.EqualFold(args[0], "module") {
+				errKey = "module-not-found"
+			}
+			utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n(errKey, map[string]any{
+				"command": utils.EscapeHTML(invalid),
+				"module":  utils.EscapeHTML(invalid),
+			}))
+			return
+		}
+		if len(commands) == 0 {
+			utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n(action+"-commands-usage"))
 			return
 		}
 
-		if err := insertDisabledCommand(update.Message.Chat.ID, command); err != nil {
-			slog.Error("Error inserting command", "error", err)
+		changed, err := setCommandsState(chat.ID, threadID, commands, adminsOnly)
+		if err != nil {
+			slog.Error("Error updating commands", "error", err)
 			return
 		}
 
-		utils.SendMessage(ctx, b, update.Message.Chat.ID, update.Message.ID,
-			i18n("command-disabled", map[string]interface{}{"command": command}))
-		return
-	}
+		respKey := "commands-" + state
+		switch {
+		case changed == 0 && len(commands) == 1:
+			respKey = "command-already-" + state
+		case changed == 0:
+			respKey = "commands-already-" + state
+		case len(commands) == 1:
+			respKey = "command-" + state
+		}
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, topicNotice(i18n, threadID, i18n(respKey, map[string]any{
+			"command":  utils.EscapeHTML(commands[0]),
+			"commands": formatCommands(commands),
+		})))
 
-	utils.SendMessage(ctx, b, update.Message.Chat.ID, update.Message.ID, i18n("disable-commands-usage"))
+		if changed > 0 {
+			SendLog(ctx, b, chat, LogEntry{
+				Action:  action,
+				Actor:   msg.From,
+				Details: formatCommands(commands),
+				Message: msg,
+			})
+		}
+	}
 }
 
 func enableHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.Message
 	i18n := localization.Get(update)
 
-	if !checkUserAdmin(ctx, b, update.Message) {
+	chat, connected := ConnectedChat(ctx, b, msg)
+	if !connected && !CheckUserAdmin(ctx, b, msg) {
 		return
 	}
 
-	if len(strings.Fields(update.Message.Text)) > 1 {
-		command := strings.Fields(update.Message.Text)[1]
+	threadID, args, ok := topicScope(ctx, b, msg, connected, strings.Fields(msg.Text)[1:])
+	if !ok {
+		return
+	}
+	if len(args) == 0 {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("enable-commands-usage"))
+		return
+	}
 
-		if !utils.CheckDisabledCommand(command, update.Message.Chat.ID) {
-			utils.SendMessage(ctx, b, update.Message.Chat.ID, update.Message.ID,
-				i18n("command-already-enabled", map[string]interface{}{"command": command}))
+	if strings.EqualFold(args[0], "all") {
+		deleted, err := clearDisabledCommands(chat.ID, threadID)
+		if err != nil {
+			slog.Error("Error deleting commands", "error", err)
 			return
 		}
-
-		if err := deleteDisabledCommand(command); err != nil {
-			slog.Error("Error deleting command", "error", err)
+		if deleted == 0 {
+			utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("no-disabled-commands"))
 			return
 		}
 
-		utils.SendMessage(ctx, b, update.Message.Chat.ID, update.Message.ID,
-			i18n("command-enabled", map[string]interface{}{"command": command}))
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID,
+			topicNotice(i18n, threadID, i18n("enable-all-success", map[string]any{"count": deleted})))
+		SendLog(ctx, b, chat, LogEntry{
+			Action:  "enable",
+			Actor:   msg.From,
+			Details: "<code>all</code>",
+			Message: msg,
+		})
+		return
+	}
+
+	commands, invalid := resolveCommandArgs(args, false)
+	if invalid != "" {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID,
+			i18n("module-not-found", map[string]any{"module": utils.EscapeHTML(invalid)}))
 		return
 	}
+	if len(commands) == 0 {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("enable-commands-usage"))
+		return
+	}
+	names := slices.Clone(commands)
+	for _, command := range commands {
+		names = append(names, utils.CommandAliases(command)...)
+	}
 
-	utils.SendMessage(ctx, b, update.Message.Chat.ID, update.Message.ID, i18n("enable-commands-usage"))
+	deleted, err := deleteDisabledCommands(chat.ID, threadID, names)
+	if err != nil {
+		slog.Error("Error deleting commands", "error", err)
+		return
+	}
+
+	respKey := "commands-enabled"
+	switch {
+	case deleted == 0 && len(commands) == 1:
+		respKey = "command-already-enabled"
+	case deleted == 0:
+		respKey = "commands-already-enabled"
+	case len(commands) == 1:
+		respKey = "command-enabled"
+	}
+	utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, topicNotice(i18n, threadID, i18n(respKey, map[string]any{
+		"command":  utils.EscapeHTML(commands[0]),
+		"commands": formatCommands(commands),
+	})))
+
+	if deleted > 0 {
+		SendLog(ctx, b, chat, LogEntry{
+			Action:  "enable",
+			Actor:   msg.From,
+			Details: formatCommands(commands),
+			Message: msg,
+		})
+	}
 }
 
 func disabledHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	i18n := localization.Get(update)
 	text := i18n("disabled-commands")
 
-	commands, err := getDisabledCommands(update.Message.Chat.ID)
+	chat, connected := ConnectedChat(ctx, b, update.Message)
+	commands, err := getDisabledCommands(chat.ID, 0)
 	if err != nil {
 		slog.Error("Error getting disabled commands", "error", err)
 		return
 	}
 
-	if len(commands) == 0 {
+	// In a forum topic, the commands disabled only there are listed apart.
+	var topicCommands map[string]bool
+	if threadID := utils.MessageThread(update.Message); threadID != 0 && !connected {
+		if topicCommands, err = getDisabledCommands(chat.ID, threadID); err != nil {
+			slog.Error("Error getting disabled commands", "error", err)
+			return
+		}
+	}
+
+	if len(commands) == 0 && len(topicCommands) == 0 {
 		utils.SendMessage(ctx, b, update.Message.Chat.ID, update.Message.ID, i18n("no-disabled-commands"))
 		return
 	}
 
-	for _, command := range commands {
-		text += "\n- <code>" + command + "</code>"
+	if len(commands) > 0 {
+		text += formatCommandStates(commands)
+	}
+	if len(topicCommands) > 0 {
+		text += "\n\n" + i18n("topic-disabled-commands") + formatCommandStates(topicCommands)
+	}
+	text += "\n\n" + i18n("command-states-legend")
+	if utils.GetDisabledDelete(chat.ID) {
+		text += "\n\n" + i18n("disabled-delete-notice")
 	}
 
 	utils.SendMessage(ctx, b, update.Message.Chat.ID, update.Message.ID, text)
 }
 
+// formatCommandStates lists commands, sorted, each marked with its state.
+func formatCommandStates(commands map[string]bool) string {
+	names := make([]string, 0, len(commands))
+	for command := range commands {
+		names = append(names, command)
+	}
+	slices.Sort(names)
+
+	var text string
+	for _, command := range names {
+		text += "\n" + commandStateMark(true, commands[command]) + " " + commandWithAliases(command)
+	}
+	return text
+}
+
+// topicScope reads the topic keyword that makes /disable, /restrict and
+// /enable act on the current forum topic only, returning the topic, or 0 for
+// the whole chat, and the remaining arguments. It replies why not and
+// returns false when the keyword is used outside a topic.
+func topicScope(ctx context.Context, b *bot.Bot, msg *models.Message, connected bool, args []string) (int, []string, bool) {
+	if len(args) == 0 || !strings.EqualFold(args[0], "topic") {
+		return 0, args, true
+	}
+
+	threadID := utils.MessageThread(msg)
+	if threadID == 0 || connected {
+		i18n := localization.Get(&models.Update{Message: msg})
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("topic-only"))
+		return 0, nil, false
+	}
+	return threadID, args[1:], true
+}
+
+// topicNotice adds to text that it only applies to the current forum topic,
+// when threadID is one.
+func topicNotice(i18n func(string, ...map[string]any) string, threadID int, text string) string {
+	if threadID == 0 {
+		return text
+	}
+	return text + "\n" + i18n("topic-scope-notice")
+}
+
+// disabledDeleteHandler chooses whether messages using a disabled command
+// are deleted, instead of just ignored.
+func disabledDeleteHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.Message
+	i18n := localization.Get(update)
+
+	chat, connected := ConnectedChat(ctx, b, msg)
+	if !connected && !CheckUserAdmin(ctx, b, msg) {
+		return
+	}
+
+	fields := strings.Fields(msg.Text)
+	if len(fields) < 2 {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("disabledel-status", map[string]any{
+			"enabled": strconv.FormatBool(utils.GetDisabledDelete(chat.ID)),
+		}))
+		return
+	}
+
+	enabled, ok := utils.ParseSwitch(fields[1])
+	if !ok {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("disabledel-usage"))
+		return
+	}
+
+	if err := setDisabledDelete(chat.ID, enabled); err != nil {
+		slog.Error("Couldn't update disabled commands settings",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("disabledel-status", map[string]any{
+		"enabled": strconv.FormatBool(enabled),
+	}))
+	SendLog(ctx, b, chat, LogEntry{
+		Action:  "config",
+		Actor:   msg.From,
+		Details: i18n("disabledel-log", map[string]any{"enabled": strconv.FormatBool(enabled)}),
+		Message: msg,
+	})
+}
+
 func languageMenuCallback(ctx context.Context, b *bot.Bot, update *models.Update) {
 	i18n := localization.Get(update)
 
-	if !checkPrivateChatCallback(update.CallbackQuery) && !checkAdminCallback(ctx, b, update.CallbackQuery) {
+	chat := CallbackChat(ctx, b, update.CallbackQuery)
+	if !checkPrivateChat(chat) && !CheckChatRightCallback(ctx, b, update.CallbackQuery, chat.ID, "") {
 		return
 	}
 
@@ -269,24 +739,33 @@ func setLanguageCallback(ctx context.Context, b *bot.Bot, update *models.Update)
 	i18n := localization.Get(update)
 	lang := strings.ReplaceAll(update.CallbackQuery.Data, "setLang ", "")
 
-	if !checkPrivateChatCallback(update.CallbackQuery) && !checkAdminCallback(ctx, b, update.CallbackQuery) {
+	chat := CallbackChat(ctx, b, update.CallbackQuery)
+	if !checkPrivateChat(chat) && !CheckChatRightCallback(ctx, b, update.CallbackQuery, chat.ID, "") {
 		return
 	}
 
 	dbQuery := "UPDATE groups SET language = ? WHERE id = ?;"
-	if update.CallbackQuery.Message.Message.Chat.Type == models.ChatTypePrivate {
+	if checkPrivateChat(chat) {
 		dbQuery = "UPDATE users SET language = ? WHERE id = ?;"
 	}
 
-	if _, err := database.DB.Exec(dbQuery, lang, update.CallbackQuery.Message.Message.Chat.ID); err != nil {
+	if _, err := database.DB.Exec(dbQuery, lang, chat.ID); err != nil {
 		slog.Error("Couldn't update language",
-			"ChatID", update.CallbackQuery.Message.Message.ID,
+			"ChatID", chat.ID,
 			"Error", err.Error())
 	}
 
 	callbackData := "config"
-	if update.CallbackQuery.Message.Message.Chat.Type == models.ChatTypePrivate {
+	if checkPrivateChat(chat) {
 		callbackData = "start"
+	} else if loaded, ok := localization.LangBundles[lang]; ok {
+		languageFlag, _, _ := loaded.FormatMessage("language-flag")
+		languageName, _, _ := loaded.FormatMessage("language-name")
+		SendLog(ctx, b, chat, LogEntry{
+			Action:  "language",
+			Actor:   &update.CallbackQuery.From,
+			Details: languageFlag + languageName,
+		})
 	}
 
 	utils.EditMessage(ctx, b, update.CallbackQuery.Message.Message.Chat.ID,
@@ -309,6 +788,20 @@ func createConfigKeyboard(i18n func(string, ...map[string]any) string) *models.I
 					Text:         i18n("medias"),
 					CallbackData: "mediaConfig",
 				},
+				{
+					Text:         i18n("locks"),
+					CallbackData: "locksConfig",
+				},
+			},
+			{
+				{
+					Text:         i18n("nightmode"),
+					CallbackData: "nightModeConfig",
+				},
+				{
+					Text:         i18n("cleanup"),
+					CallbackData: "cleanupConfig",
+				},
 			},
 			{
 				{
@@ -320,19 +813,39 @@ func createConfigKeyboard(i18n func(string, ...map[string]any) string) *models.I
 	}
 }
 
+// configMessage returns the /config text, naming the group when it's managed
+// from a private chat.
+func configMessage(i18n func(string, ...map[string]any) string, chat models.Chat, connected bool) string {
+	if connected {
+		return i18n("config-message-connected", map[string]any{"chatName": utils.EscapeHTML(chat.Title)})
+	}
+	return i18n("config-message")
+}
+
 func configHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	i18n := localization.Get(update)
-	utils.SendMessage(ctx, b, update.Message.Chat.ID, update.Message.ID,
-		i18n("config-message"),
+	chat, connected := ConnectedChat(ctx, b, update.Message)
+	msg, err := utils.SendMessageWithResult(ctx, b, update.Message.Chat.ID, update.Message.ID,
+		configMessage(i18n, chat, connected),
 		utils.WithReplyMarkupSend(createConfigKeyboard(i18n)),
 	)
+	if err != nil || !connected {
+		return
+	}
+
+	if err := setConnectionConfigMessage(update.Message.From.ID, msg.ID); err != nil {
+		slog.Error("Couldn't save connection config message",
+			"UserID", update.Message.From.ID,
+			"Error", err.Error())
+	}
 }
 
 func configCallback(ctx context.Context, b *bot.Bot, update *models.Update) {
 	i18n := localization.Get(update)
-	utils.EditMessage(ctx, b, update.CallbackQuery.Message.Message.Chat.ID,
-		update.CallbackQuery.Message.Message.ID,
-		i18n("config-message"),
+	message := update.CallbackQuery.Message.Message
+	chat := CallbackChat(ctx, b, update.CallbackQuery)
+	utils.EditMessage(ctx, b, message.Chat.ID, message.ID,
+		configMessage(i18n, chat, chat.ID != message.Chat.ID),
 		utils.WithReplyMarkup(createConfigKeyboard(i18n)),
 	)
 }
@@ -344,15 +857,16 @@ func getMediaConfig(chatID int64) (bool, bool, error) {
 }
 
 func mediaConfigCallback(ctx context.Context, b *bot.Bot, update *models.Update) {
-	mediasCaption, mediasAuto, err := getMediaConfig(update.CallbackQuery.Message.Message.Chat.ID)
+	chat := CallbackChat(ctx, b, update.CallbackQuery)
+	mediasCaption, mediasAuto, err := getMediaConfig(chat.ID)
 	if err != nil {
 		slog.Error("Couldn't query media config",
-			"ChatID", update.CallbackQuery.Message.Message.Chat.ID,
+			"ChatID", chat.ID,
 			"Error", err.Error())
 		return
 	}
 
-	if !checkPrivateChatCallback(update.CallbackQuery) && !checkAdminCallback(ctx, b, update.CallbackQuery) {
+	if !checkPrivateChat(chat) && !CheckChatRightCallback(ctx, b, update.CallbackQuery, chat.ID, "") {
 		return
 	}
 
@@ -362,10 +876,10 @@ func mediaConfigCallback(ctx context.Context, b *bot.Bot, update *models.Update)
 		switch configType {
 		case "mediasCaption":
 			mediasCaption = !mediasCaption
-			_, err = database.DB.Exec(query, mediasCaption, update.CallbackQuery.Message.Message.Chat.ID)
+			_, err = database.DB.Exec(query, mediasCaption, chat.ID)
 		case "mediasAuto":
 			mediasAuto = !mediasAuto
-			_, err = database.DB.Exec(query, mediasAuto, update.CallbackQuery.Message.Message.Chat.ID)
+			_, err = database.DB.Exec(query, mediasAuto, chat.ID)
 		}
 		if err != nil {
 			slog.Error("Error updating media config", "error", err)
@@ -381,6 +895,19 @@ func mediaConfigCallback(ctx context.Context, b *bot.Bot, update *models.Update)
 		return "☑️"
 	}
 
+	switch configType {
+	case "mediasCaption", "mediasAuto":
+		label, value := i18n("caption-button"), mediasCaption
+		if configType == "mediasAuto" {
+			label, value = i18n("automatic-button"), mediasAuto
+		}
+		SendLog(ctx, b, chat, LogEntry{
+			Action:  "config",
+			Actor:   &update.CallbackQuery.From,
+			Details: utils.EscapeHTML(i18n("medias")+" › "+label) + ": " + state(value),
+		})
+	}
+
 	buttons := [][]models.InlineKeyboardButton{
 		{
 			{Text: i18n("caption-button"), CallbackData: "ieConfig mediasCaption"},
@@ -419,15 +946,24 @@ func newRestrictionHandler(name string, action restrictionFunc) bot.HandlerFunc
 			return
 		}
 
-		if !checkUserAdmin(ctx, b, msg) || !checkBotAdmin(ctx, b, msg) {
+		if !CheckUserAdmin(ctx, b, msg) || !CheckBotAdmin(ctx, b, msg) {
 			return
 		}
 
-		userID, until, errMsg := parseUserRestriction(msg)
+		userID, until, reason, errMsg := parseUserRestriction(msg)
 		if errMsg != "" {
 			utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n(name+"-id"))
 			return
 		}
+		// Chats that send messages in the group can only be banned, and
+		// only for good.
+		if isSenderChat(userID) {
+			if name == "mute" || name == "unmute" {
+				utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("id-channel"))
+				return
+			}
+			until = 0
+		}
 
 		if err := action(ctx, b, msg, userID, until); err != nil {
 			utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n(name+"-failed"))
@@ -446,6 +982,12 @@ func newRestrictionHandler(name string, action restrictionFunc) bot.HandlerFunc
 			respData["untilDate"] = time.Unix(int64(until), 0).Format("02/01/2006 15:04")
 		}
 		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n(respKey, respData))
+
+		entry := LogEntry{Action: name, Actor: msg.From, TargetID: userID, Reason: reason, Until: until, Message: msg}
+		if msg.ReplyToMessage != nil && msg.ReplyToMessage.From != nil && msg.ReplyToMessage.SenderChat == nil {
+			entry.Target = msg.ReplyToMessage.From
+		}
+		SendLog(ctx, b, msg.Chat, entry)
 	}
 }
 
@@ -459,7 +1001,21 @@ func unmuteAction(ctx context.Context, b *bot.Bot, msg *models.Message, userID i
 	return err
 }
 
+// isSenderChat reports whether id, a restriction target, is a chat that
+// sends messages in the group rather than a user.
+func isSenderChat(id int64) bool {
+	return id < 0
+}
+
 func banAction(ctx context.Context, b *bot.Bot, msg *models.Message, userID int64, until int) error {
+	if isSenderChat(userID) {
+		if userID == msg.Chat.ID {
+			return fmt.Errorf("can't ban the chat itself")
+		}
+		_, err := b.BanChatSenderChat(ctx, &bot.BanChatSenderChatParams{ChatID: msg.Chat.ID, SenderChatID: int(userID)})
+		return err
+	}
+
 	revoke := false
 	params := &bot.BanChatMemberParams{ChatID: msg.Chat.ID, UserID: userID, RevokeMessages: revoke}
 	if until > 0 {
@@ -470,6 +1026,10 @@ func banAction(ctx context.Context, b *bot.Bot, msg *models.Message, userID int6
 }
 
 func unbanAction(ctx context.Context, b *bot.Bot, msg *models.Message, userID int64, until int) error {
+	if isSenderChat(userID) {
+		_, err := b.UnbanChatSenderChat(ctx, &bot.UnbanChatSenderChatParams{ChatID: msg.Chat.ID, SenderChatID: int(userID)})
+		return err
+	}
 	_, err := b.UnbanChatMember(ctx, &bot.UnbanChatMemberParams{ChatID: msg.Chat.ID, UserID: userID})
 	return err
 }
@@ -482,6 +1042,463 @@ func deleteAction(ctx context.Context, b *bot.Bot, msg *models.Message, userID i
 	return err
 }
 
+const (
+	purgeBatchSize    = 100
+	purgeMaxMessages  = 1000
+	purgeReplyTimeout = 5 * time.Second
+)
+
+// parsePurgeRange returns the messages to purge, which never include the
+// command itself.
+func parsePurgeRange(msg *models.Message) (fromID, toID int, errMsg string) {
+	var count int
+	if parts := strings.Fields(msg.Text); len(parts) > 1 {
+		n, err := strconv.Atoi(parts[1])
+		if err != nil || n < 1 {
+			return 0, 0, "purge-usage"
+		}
+		count = min(n, purgeMaxMessages)
+	}
+
+	if msg.ReplyToMessage != nil {
+		fromID, toID = msg.ReplyToMessage.ID, msg.ID-1
+		if count > 0 {
+			toID = min(fromID+count-1, toID)
+		}
+		return max(fromID, toID-purgeMaxMessages+1), toID, ""
+	}
+
+	if count == 0 {
+		return 0, 0, "purge-usage"
+	}
+	return max(1, msg.ID-count), msg.ID - 1, ""
+}
+
+// purgeMessages deletes the messages from fromID to toID in batches of
+// deleteMessages. It skips the missing messages without telling which, so
+// deleted counts every ID of the batches that succeeded and is only an upper
+// bound. A batch fails as a whole, like when it holds messages older than
+// 48 hours, which is reported through failed.
+func purgeMessages(ctx context.Context, b *bot.Bot, chatID int64, fromID, toID int) (deleted int, failed bool) {
+	for start := fromID; start <= toID; start += purgeBatchSize {
+		end := min(start+purgeBatchSize-1, toID)
+		ids := make([]int, 0, end-start+1)
+		for id := start; id <= end; id++ {
+			ids = append(ids, id)
+		}
+		if _, err := b.DeleteMessages(ctx, &bot.DeleteMessagesParams{ChatID: chatID, MessageIDs: ids}); err != nil {
+			slog.Warn("Couldn't delete messages batch",
+				"ChatID", chatID,
+				"From", start,
+				"To", end,
+				"Error", err.Error())
+			failed = true
+			continue
+		}
+		deleted += len(ids)
+	}
+	return deleted, failed
+}
+
+func purgeHandler(silent bool) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		msg := update.Message
+		i18n := localization.Get(update)
+
+		if checkPrivateChat(msg.Chat) {
+			utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("group-only"))
+			return
+		}
+
+		if !CheckUserRight(ctx, b, msg, RightDeleteMessages) || !CheckBotRight(ctx, b, msg, RightDeleteMessages) {
+			return
+		}
+
+		fromID, toID, errMsg := parsePurgeRange(msg)
+		if errMsg != "" {
+			utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n(errMsg))
+			return
+		}
+
+		deleted, failed := purgeMessages(ctx, b, msg.Chat.ID, fromID, toID)
+		b.DeleteMessage(ctx, &bot.DeleteMessageParams{ChatID: msg.Chat.ID, MessageID: msg.ID})
+		if silent {
+			return
+		}
+
+		text := i18n("purge-success", map[string]any{"count": deleted})
+		if failed {
+			text += "\n" + i18n("purge-partial")
+		}
+
+		reply, err := utils.SendMessageWithResult(ctx, b, msg.Chat.ID, 0, text)
+		if err != nil {
+			return
+		}
+		time.AfterFunc(purgeReplyTimeout, func() {
+			b.DeleteMessage(context.Background(), &bot.DeleteMessageParams{ChatID: reply.Chat.ID, MessageID: reply.ID})
+		})
+	}
+}
+
+func checkPinRights(ctx context.Context, b *bot.Bot, msg *models.Message) bool {
+	if checkPrivateChat(msg.Chat) {
+		i18n := localization.Get(&models.Update{Message: msg})
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("group-only"))
+		return false
+	}
+	return CheckUserRight(ctx, b, msg, RightPinMessages) && CheckBotRight(ctx, b, msg, RightPinMessages)
+}
+
+func pinHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.Message
+	i18n := localization.Get(update)
+
+	if !checkPinRights(ctx, b, msg) {
+		return
+	}
+
+	if msg.ReplyToMessage == nil {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("pin-reply-required"))
+		return
+	}
+
+	loud := false
+	if parts := strings.Fields(msg.Text); len(parts) > 1 {
+		switch strings.ToLower(parts[1]) {
+		case "loud", "notify":
+			loud = true
+		case "silent", "quiet":
+		default:
+			utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("pin-usage"))
+			return
+		}
+	}
+
+	if _, err := b.PinChatMessage(ctx, &bot.PinChatMessageParams{
+		ChatID:              msg.Chat.ID,
+		MessageID:           msg.ReplyToMessage.ID,
+		DisableNotification: !loud,
+	}); err != nil {
+		slog.Error("Couldn't pin message",
+			"ChatID", msg.Chat.ID,
+			"MessageID", msg.ReplyToMessage.ID,
+			"Error", err.Error())
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("pin-failed"))
+		return
+	}
+
+	if !loud {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ReplyToMessage.ID, i18n("pin-success"))
+	}
+}
+
+func unpinHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.Message
+	i18n := localization.Get(update)
+
+	if !checkPinRights(ctx, b, msg) {
+		return
+	}
+
+	params := &bot.UnpinChatMessageParams{ChatID: msg.Chat.ID}
+	if msg.ReplyToMessage != nil {
+		params.MessageID = msg.ReplyToMessage.ID
+	}
+
+	if _, err := b.UnpinChatMessage(ctx, params); err != nil {
+		slog.Error("Couldn't unpin message",
+			"ChatID", msg.Chat.ID,
+			"Error", err.Error())
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("unpin-failed"))
+		return
+	}
+
+	utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("unpin-success"))
+}
+
+func unpinAllHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.Message
+	i18n := localization.Get(update)
+
+	if !checkPinRights(ctx, b, msg) {
+		return
+	}
+
+	utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("unpinall-confirm"),
+		utils.WithReplyMarkupSend(&models.InlineKeyboardMarkup{
+			InlineKeyboard: [][]models.InlineKeyboardButton{{
+				{Text: i18n("unpinall-yes-button"), CallbackData: "unpinall confirm"},
+				{Text: i18n("unpinall-no-button"), CallbackData: "unpinall cancel"},
+			}},
+		}))
+}
+
+func unpinAllCallback(ctx context.Context, b *bot.Bot, update *models.Update) {
+	i18n := localization.Get(update)
+	msg := update.CallbackQuery.Message.Message
+
+	if !CheckRightCallback(ctx, b, update.CallbackQuery, RightPinMessages) {
+		return
+	}
+
+	if strings.TrimPrefix(update.CallbackQuery.Data, "unpinall ") != "confirm" {
+		b.DeleteMessage(ctx, &bot.DeleteMessageParams{ChatID: msg.Chat.ID, MessageID: msg.ID})
+		return
+	}
+
+	if _, err := b.UnpinAllChatMessages(ctx, &bot.UnpinAllChatMessagesParams{ChatID: msg.Chat.ID}); err != nil {
+		slog.Error("Couldn't unpin all messages",
+			"ChatID", msg.Chat.ID,
+			"Error", err.Error())
+		utils.EditMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("unpin-failed"))
+		return
+	}
+
+	utils.EditMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("unpinall-success"))
+}
+
+func pinnedHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.Message
+	i18n := localization.Get(update)
+
+	if checkPrivateChat(msg.Chat) {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("group-only"))
+		return
+	}
+
+	chat, err := b.GetChat(ctx, &bot.GetChatParams{ChatID: msg.Chat.ID})
+	if err != nil {
+		slog.Error("Couldn't get chat",
+			"ChatID", msg.Chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	if chat.PinnedMessage == nil {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("no-pinned-message"))
+		return
+	}
+
+	utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("pinned-message"),
+		utils.WithReplyMarkupSend(&models.InlineKeyboardMarkup{
+			InlineKeyboard: [][]models.InlineKeyboardButton{{
+				{Text: i18n("pinned-message-button"), URL: utils.MessageLink(chat.ID, chat.Username, chat.PinnedMessage.ID)},
+			}},
+		}))
+}
+
+const maxAdminTitleLength = 16
+
+func checkPromoteRights(ctx context.Context, b *bot.Bot, msg *models.Message) bool {
+	if checkPrivateChat(msg.Chat) {
+		i18n := localization.Get(&models.Update{Message: msg})
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("group-only"))
+		return false
+	}
+	return CheckUserRight(ctx, b, msg, RightPromoteMembers) && CheckBotRight(ctx, b, msg, RightPromoteMembers)
+}
+
+// grantableRights returns the rights that both the caller and the bot hold,
+// so that nobody can hand out a permission they don't have themselves.
+func grantableRights(caller, botAdmin *models.ChatMemberAdministrator, full bool) *models.ChatMemberAdministrator {
+	return &models.ChatMemberAdministrator{
+		CanManageChat:       caller.CanManageChat && botAdmin.CanManageChat,
+		CanDeleteMessages:   caller.CanDeleteMessages && botAdmin.CanDeleteMessages,
+		CanManageVideoChats: caller.CanManageVideoChats && botAdmin.CanManageVideoChats,
+		CanRestrictMembers:  caller.CanRestrictMembers && botAdmin.CanRestrictMembers,
+		CanInviteUsers:      caller.CanInviteUsers && botAdmin.CanInviteUsers,
+		CanPinMessages:      caller.CanPinMessages && botAdmin.CanPinMessages,
+		CanManageTopics:     caller.CanManageTopics && botAdmin.CanManageTopics,
+		CanChangeInfo:       full && caller.CanChangeInfo && botAdmin.CanChangeInfo,
+		CanPromoteMembers:   full && caller.CanPromoteMembers && botAdmin.CanPromoteMembers,
+	}
+}
+
+// holdsAdminRights reports whether caller holds every right target has, so
+// that admins can't strip or overwrite the rights of those above them.
+func holdsAdminRights(caller, target *models.ChatMemberAdministrator) bool {
+	held := func(callerRight, targetRight bool) bool { return callerRight || !targetRight }
+	return held(caller.CanManageChat, target.CanManageChat) &&
+		held(caller.CanDeleteMessages, target.CanDeleteMessages) &&
+		held(caller.CanManageVideoChats, target.CanManageVideoChats) &&
+		held(caller.CanRestrictMembers, target.CanRestrictMembers) &&
+		held(caller.CanPromoteMembers, target.CanPromoteMembers) &&
+		held(caller.CanChangeInfo, target.CanChangeInfo) &&
+		held(caller.CanInviteUsers, target.CanInviteUsers) &&
+		held(caller.CanPinMessages, target.CanPinMessages) &&
+		held(caller.CanManageTopics, target.CanManageTopics)
+}
+
+// checkEditableAdmin checks that the caller may change the rights of
+// userID when they're already an admin.
+func checkEditableAdmin(ctx context.Context, b *bot.Bot, msg *models.Message, caller *models.ChatMemberAdministrator, userID int64) bool {
+	target := getAdminRights(ctx, b, msg.Chat.ID, userID)
+	if target == nil || holdsAdminRights(caller, target) {
+		return true
+	}
+	i18n := localization.Get(&models.Update{Message: msg})
+	utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("admin-outranked"))
+	return false
+}
+
+func promoteHandler(full bool) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		msg := update.Message
+		i18n := localization.Get(update)
+
+		if !checkPromoteRights(ctx, b, msg) {
+			return
+		}
+
+		userID, args, errMsg := ParseUserTarget(msg)
+		if errMsg != "" {
+			utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n(errMsg))
+			return
+		}
+
+		botID, err := utils.GetBotID(ctx, b)
+		if err != nil {
+			slog.Error("Couldn't get bot ID", "Error", err.Error())
+			return
+		}
+
+		caller := getAdminRights(ctx, b, msg.Chat.ID, msg.From.ID)
+		botAdmin := getAdminRights(ctx, b, msg.Chat.ID, botID)
+		if caller == nil || botAdmin == nil || !checkEditableAdmin(ctx, b, msg, caller, userID) {
+			return
+		}
+
+		rights := grantableRights(caller, botAdmin, full)
+		if _, err := b.PromoteChatMember(ctx, &bot.PromoteChatMemberParams{
+			ChatID:              msg.Chat.ID,
+			UserID:              userID,
+			CanManageChat:       rights.CanManageChat,
+			CanDeleteMessages:   rights.CanDeleteMessages,
+			CanManageVideoChats: rights.CanManageVideoChats,
+			CanRestrictMembers:  rights.CanRestrictMembers,
+			CanPromoteMembers:   rights.CanPromoteMembers,
+			CanChangeInfo:       rights.CanChangeInfo,
+			CanInviteUsers:      rights.CanInviteUsers,
+			CanPinMessages:      rights.CanPinMessages,
+			CanManageTopics:     rights.CanManageTopics,
+		}); err != nil {
+			slog.Error("Couldn't promote member",
+				"ChatID", msg.Chat.ID,
+				"UserID", userID,
+				"Error", err.Error())
+			utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("promote-failed"))
+			return
+		}
+
+		utils.InvalidateChatAdmins(msg.Chat.ID)
+
+		respKey := "promote-success"
+		if full {
+			respKey = "fullpromote-success"
+		}
+		text := i18n(respKey, map[string]any{"userFirstName": getUserName(msg, userID)})
+
+		if title := strings.Join(args, " "); title != "" {
+			if err := setAdminTitle(ctx, b, msg.Chat.ID, userID, title); err != nil {
+				text += "\n" + i18n("title-failed")
+			}
+		}
+
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, text)
+		SendLog(ctx, b, msg.Chat, LogEntry{Action: strings.TrimSuffix(respKey, "-success"), Actor: msg.From, TargetID: userID, Message: msg})
+	}
+}
+
+func demoteHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.Message
+	i18n := localization.Get(update)
+
+	if !checkPromoteRights(ctx, b, msg) {
+		return
+	}
+
+	userID, _, errMsg := ParseUserTarget(msg)
+	if errMsg != "" {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n(errMsg))
+		return
+	}
+
+	caller := getAdminRights(ctx, b, msg.Chat.ID, msg.From.ID)
+	if caller == nil || !checkEditableAdmin(ctx, b, msg, caller, userID) {
+		return
+	}
+
+	if _, err := b.PromoteChatMember(ctx, &bot.PromoteChatMemberParams{
+		ChatID: msg.Chat.ID,
+		UserID: userID,
+	}); err != nil {
+		slog.Error("Couldn't demote member",
+			"ChatID", msg.Chat.ID,
+			"UserID", userID,
+			"Error", err.Error())
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("demote-failed"))
+		return
+	}
+	utils.InvalidateChatAdmins(msg.Chat.ID)
+
+	utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID,
+		i18n("demote-success", map[string]any{"userFirstName": getUserName(msg, userID)}))
+	SendLog(ctx, b, msg.Chat, LogEntry{Action: "demote", Actor: msg.From, TargetID: userID, Message: msg})
+}
+
+func setAdminTitle(ctx context.Context, b *bot.Bot, chatID, userID int64, title string) error {
+	_, err := b.SetChatAdministratorCustomTitle(ctx, &bot.SetChatAdministratorCustomTitleParams{
+		ChatID:      chatID,
+		UserID:      userID,
+		CustomTitle: title,
+	})
+	if err != nil {
+		slog.Error("Couldn't set admin title",
+			"ChatID", chatID,
+			"UserID", userID,
+			"Error", err.Error())
+	}
+	return err
+}
+
+func titleHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.Message
+	i18n := localization.Get(update)
+
+	if !checkPromoteRights(ctx, b, msg) {
+		return
+	}
+
+	userID, args, errMsg := ParseUserTarget(msg)
+	if errMsg != "" {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n(errMsg))
+		return
+	}
+
+	title := strings.Join(args, " ")
+	if title == "" {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("title-usage"))
+		return
+	}
+
+	if utf8.RuneCountInString(title) > maxAdminTitleLength {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID,
+			i18n("title-too-long", map[string]any{"max": maxAdminTitleLength}))
+		return
+	}
+
+	if err := setAdminTitle(ctx, b, msg.Chat.ID, userID, title); err != nil {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("title-failed"))
+		return
+	}
+
+	utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("title-success", map[string]any{
+		"userFirstName": getUserName(msg, userID),
+		"title":         utils.EscapeHTML(title),
+	}))
+}
+
 func muteAction(ctx context.Context, b *bot.Bot, msg *models.Message, userID int64, until int) error {
 	permissions := &models.ChatPermissions{
 		CanSendMessages:       false,
@@ -512,11 +1529,14 @@ func Load(b *bot.Bot) {
 	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "setLang", bot.MatchTypeContains, setLanguageCallback)
 	b.RegisterHandler(bot.HandlerTypeMessageText, "config", bot.MatchTypeCommand, configHandler)
 	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "config", bot.MatchTypeExact, configCallback)
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "anonAdmin", bot.MatchTypePrefix, anonymousAdminCallback)
 	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "mediaConfig", bot.MatchTypeContains, mediaConfigCallback)
 	b.RegisterHandler(bot.HandlerTypeMessageText, "disableable", bot.MatchTypeCommand, disableableHandler)
-	b.RegisterHandler(bot.HandlerTypeMessageText, "disable", bot.MatchTypeCommand, disableHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "disable", bot.MatchTypeCommand, disableHandler(false))
+	b.RegisterHandler(bot.HandlerTypeMessageText, "restrict", bot.MatchTypeCommand, disableHandler(true))
 	b.RegisterHandler(bot.HandlerTypeMessageText, "enable", bot.MatchTypeCommand, enableHandler)
 	b.RegisterHandler(bot.HandlerTypeMessageText, "disabled", bot.MatchTypeCommand, disabledHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "disabledel", bot.MatchTypeCommand, disabledDeleteHandler)
 	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "disableable", bot.MatchTypeCommand, disableableHandler)
 	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "ieConfig", bot.MatchTypeExact, explainConfigCallback)
 	b.RegisterHandler(bot.HandlerTypeMessageText, "mute", bot.MatchTypeCommand, newRestrictionHandler("mute", muteAction))
@@ -524,7 +1544,40 @@ func Load(b *bot.Bot) {
 	b.RegisterHandler(bot.HandlerTypeMessageText, "ban", bot.MatchTypeCommand, newRestrictionHandler("ban", banAction))
 	b.RegisterHandler(bot.HandlerTypeMessageText, "unban", bot.MatchTypeCommand, newRestrictionHandler("unban", unbanAction))
 	b.RegisterHandler(bot.HandlerTypeMessageText, "del", bot.MatchTypeCommand, newRestrictionHandler("delete", deleteAction))
+	b.RegisterHandler(bot.HandlerTypeMessageText, "purge", bot.MatchTypeCommand, purgeHandler(false))
+	b.RegisterHandler(bot.HandlerTypeMessageText, "spurge", bot.MatchTypeCommand, purgeHandler(true))
+	b.RegisterHandler(bot.HandlerTypeMessageText, "pin", bot.MatchTypeCommand, pinHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "unpin", bot.MatchTypeCommand, unpinHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "unpinall", bot.MatchTypeCommand, unpinAllHandler)
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "unpinall", bot.MatchTypePrefix, unpinAllCallback)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "pinned", bot.MatchTypeCommand, pinnedHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "promote", bot.MatchTypeCommand, promoteHandler(false))
+	b.RegisterHandler(bot.HandlerTypeMessageText, "fullpromote", bot.MatchTypeCommand, promoteHandler(true))
+	b.RegisterHandler(bot.HandlerTypeMessageText, "demote", bot.MatchTypeCommand, demoteHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "title", bot.MatchTypeCommand, titleHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "setlog", bot.MatchTypeCommand, setLogHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "unsetlog", bot.MatchTypeCommand, unsetLogHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "logchannel", bot.MatchTypeCommand, logChannelHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "approve", bot.MatchTypeCommand, approveHandler(true))
+	b.RegisterHandler(bot.HandlerTypeMessageText, "unapprove", bot.MatchTypeCommand, approveHandler(false))
+	b.RegisterHandler(bot.HandlerTypeMessageText, "approved", bot.MatchTypeCommand, approvedHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "unapproveall", bot.MatchTypeCommand, unapproveAllHandler)
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "unapproveall", bot.MatchTypePrefix, unapproveAllCallback)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "connect", bot.MatchTypeCommand, connectHandler)
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "connect", bot.MatchTypePrefix, connectCallback)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "disconnect", bot.MatchTypeCommand, disconnectHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "export", bot.MatchTypeCommand, exportHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "import", bot.MatchTypeCommand, importHandler)
+
+	utils.RegisterStartPayload("connect", connectStartHandler)
+	utils.RegisterChatSection("chat", utils.ChatSection{Export: exportChatSection, Import: importChatSection})
+	utils.RegisterChatSection("disabled", utils.ChatSection{Export: exportDisabledSection, Import: importDisabledSection})
+	utils.RegisterChatSection("approvals", utils.ChatSection{
+		Export:   exportApprovalsSection,
+		Import:   importApprovalsSection,
+		Imported: invalidateApprovals,
+	})
 
-	utils.DisableableCommands = append(utils.DisableableCommands, "ban", "unban", "mute", "unmute", "del")
+	utils.RegisterDisableable("moderation", "ban", "unban", "mute", "unmute", "del", "purge", "spurge")
 	utils.SaveHelp("moderation")
 }
@@ -0,0 +1,237 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+// LogEntry is a moderation event posted to the log channel of a chat.
+type LogEntry struct {
+	// Action selects the "log-action-<Action>" header, e.g. "ban" or "join".
+	Action string
+	// Actor is who performed the action, nil for automatic events.
+	Actor *models.User
+	// Target is the affected user. TargetID is used when only the ID is known.
+	Target   *models.User
+	TargetID int64
+	Reason   string
+	// Until is the unix time a temporary restriction expires.
+	Until int
+	// Details is an HTML description of what changed, e.g. a config value.
+	Details string
+	// Message is linked in the entry so admins can jump to the context.
+	Message *models.Message
+}
+
+// LogChannel returns the log channel linked to chatID, or 0 if there's none.
+func LogChannel(chatID int64) int64 {
+	channelID, err := getLogChannel(chatID)
+	if err != nil {
+		slog.Error("Couldn't get log channel",
+			"ChatID", chatID,
+			"Error", err.Error())
+	}
+	return channelID
+}
+
+// SendLog posts entry to the log channel linked to chat, if there is one.
+// The entry is written in the language of the chat.
+func SendLog(ctx context.Context, b *bot.Bot, chat models.Chat, entry LogEntry) {
+	if chat.Type == models.ChatTypePrivate {
+		return
+	}
+
+	channelID, err := getLogChannel(chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get log channel",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+	if channelID == 0 {
+		return
+	}
+
+	i18n := localization.Get(&models.Update{Message: &models.Message{Chat: chat}})
+	lines := []string{
+		i18n("log-action-" + entry.Action),
+		i18n("log-chat", map[string]any{"chatName": utils.EscapeHTML(chat.Title), "chatID": strconv.FormatInt(chat.ID, 10)}),
+	}
+	if entry.Actor != nil {
+		lines = append(lines, i18n("log-admin", map[string]any{
+			"admin": utils.MentionUser(entry.Actor),
+			"id":    strconv.FormatInt(entry.Actor.ID, 10),
+		}))
+	}
+	switch {
+	case entry.Target != nil:
+		lines = append(lines, i18n("log-user", map[string]any{
+			"user": utils.MentionUser(entry.Target),
+			"id":   strconv.FormatInt(entry.Target.ID, 10),
+		}))
+	case entry.TargetID != 0:
+		user := fmt.Sprintf("<a href='tg://user?id=%d'>%d</a>", entry.TargetID, entry.TargetID)
+		if isSenderChat(entry.TargetID) {
+			user = fmt.Sprintf("<code>%d</code>", entry.TargetID)
+		}
+		lines = append(lines, i18n("log-user", map[string]any{
+			"user": user,
+			"id":   strconv.FormatInt(entry.TargetID, 10),
+		}))
+	}
+	if entry.Details != "" {
+		lines = append(lines, i18n("log-details", map[string]any{"details": entry.Details}))
+	}
+	if entry.Reason != "" {
+		lines = append(lines, i18n("log-reason", map[string]any{"reason": utils.EscapeHTML(entry.Reason)}))
+	}
+	if entry.Until > 0 {
+		lines = append(lines, i18n("log-until", map[string]any{
+			"untilDate": time.Unix(int64(entry.Until), 0).Format("02/01/2006 15:04"),
+		}))
+	}
+	if entry.Message != nil && entry.Message.Chat.ID == chat.ID {
+		lines = append(lines, i18n("log-link", map[string]any{
+			"link": utils.MessageLink(chat.ID, chat.Username, entry.Message.ID),
+		}))
+	}
+
+	utils.SendMessage(ctx, b, channelID, 0, strings.Join(lines, "\n"))
+}
+
+// resolveLogChannel finds the channel given as argument to /setlog, either as
+// an ID, an @username or by replying to a message forwarded from it.
+func resolveLogChannel(ctx context.Context, b *bot.Bot, msg *models.Message) (*models.ChatFullInfo, error) {
+	var chatID any
+	if parts := strings.Fields(msg.Text); len(parts) > 1 {
+		if id, err := strconv.ParseInt(parts[1], 10, 64); err == nil {
+			chatID = id
+		} else {
+			chatID = "@" + strings.TrimPrefix(parts[1], "@")
+		}
+	} else if reply := msg.ReplyToMessage; reply != nil && reply.ForwardOrigin != nil &&
+		reply.ForwardOrigin.MessageOriginChannel != nil {
+		chatID = reply.ForwardOrigin.MessageOriginChannel.Chat.ID
+	} else {
+		return nil, nil
+	}
+
+	return b.GetChat(ctx, &bot.GetChatParams{ChatID: chatID})
+}
+
+func setLogHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := SettingsChat(ctx, b, msg, RightChangeInfo)
+	if !ok {
+		return
+	}
+
+	channel, err := resolveLogChannel(ctx, b, msg)
+	if channel == nil {
+		if err != nil {
+			slog.Debug("Couldn't resolve log channel",
+				"ChatID", chat.ID,
+				"Error", err.Error())
+		}
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("setlog-usage"))
+		return
+	}
+	if channel.Type != models.ChatTypeChannel {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("setlog-not-channel"))
+		return
+	}
+
+	// The bot must be able to post in the channel, and only admins of the
+	// channel may send the group logs there.
+	member, err := b.GetChatMember(ctx, &bot.GetChatMemberParams{ChatID: channel.ID, UserID: b.ID()})
+	if err != nil || member.Type != models.ChatMemberTypeAdministrator || !member.Administrator.CanPostMessages {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("setlog-bot-cant-post"))
+		return
+	}
+	if !IsAdmin(ctx, b, channel.ID, msg.From.ID) {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("setlog-user-not-admin"))
+		return
+	}
+
+	if err := setLogChannel(chat.ID, channel.ID); err != nil {
+		slog.Error("Couldn't set log channel",
+			"ChatID", chat.ID,
+			"ChannelID", channel.ID,
+			"Error", err.Error())
+		return
+	}
+
+	utils.SendMessage(ctx, b, channel.ID, 0, i18n("log-channel-linked", map[string]any{
+		"chatName": utils.EscapeHTML(chat.Title),
+	}))
+	utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("setlog-success", map[string]any{
+		"channelName": utils.EscapeHTML(channel.Title),
+	}))
+}
+
+func unsetLogHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := SettingsChat(ctx, b, msg, RightChangeInfo)
+	if !ok {
+		return
+	}
+
+	removed, err := deleteLogChannel(chat.ID)
+	if err != nil {
+		slog.Error("Couldn't delete log channel",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	respKey := "log-channel-none"
+	if removed {
+		respKey = "unsetlog-success"
+	}
+	utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n(respKey))
+}
+
+func logChannelHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := ConnectedGroup(ctx, b, msg)
+	if !ok {
+		return
+	}
+
+	channelID, err := getLogChannel(chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get log channel",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+	if channelID == 0 {
+		utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("log-channel-none"))
+		return
+	}
+
+	channelName := strconv.FormatInt(channelID, 10)
+	if channel, err := b.GetChat(ctx, &bot.GetChatParams{ChatID: channelID}); err == nil {
+		channelName = channel.Title
+	}
+	utils.SendMessage(ctx, b, msg.Chat.ID, msg.ID, i18n("log-channel-current", map[string]any{
+		"channelName": utils.EscapeHTML(channelName),
+		"channelID":   strconv.FormatInt(channelID, 10),
+	}))
+}
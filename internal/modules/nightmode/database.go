@@ -0,0 +1,81 @@
+package nightmode
+
+import (
+	"database/sql"
+
+	"github.com/angelomds42/EleineBot/internal/database"
+)
+
+type nightSettings struct {
+	ChatID   int64
+	Enabled  bool
+	Start    int
+	End      int
+	Timezone string
+	// Active is set while the chat is locked, with the permissions it had
+	// before saved in Permissions.
+	Active      bool
+	Permissions string
+}
+
+func defaultSettings(chatID int64) nightSettings {
+	return nightSettings{ChatID: chatID, Start: 23 * 60, End: 7 * 60, Timezone: "UTC"}
+}
+
+func scanSettings(row interface{ Scan(...any) error }) (nightSettings, error) {
+	var s nightSettings
+	var permissions sql.NullString
+	err := row.Scan(&s.ChatID, &s.Enabled, &s.Start, &s.End, &s.Timezone, &s.Active, &permissions)
+	s.Permissions = permissions.String
+	return s, err
+}
+
+func getNightSettings(chatID int64) (nightSettings, error) {
+	settings, err := scanSettings(database.DB.QueryRow(`
+		SELECT chat_id, enabled, start_minute, end_minute, timezone, active, permissions
+		FROM nightMode WHERE chat_id = ?;
+	`, chatID))
+	if err == sql.ErrNoRows {
+		return defaultSettings(chatID), nil
+	}
+	return settings, err
+}
+
+// getScheduledChats returns the chats with night mode enabled, plus those
+// still locked after it was disabled, which need their permissions back.
+func getScheduledChats() ([]nightSettings, error) {
+	rows, err := database.DB.Query(`
+		SELECT chat_id, enabled, start_minute, end_minute, timezone, active, permissions
+		FROM nightMode WHERE enabled = 1 OR active = 1;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chats []nightSettings
+	for rows.Next() {
+		settings, err := scanSettings(rows)
+		if err != nil {
+			return nil, err
+		}
+		chats = append(chats, settings)
+	}
+	return chats, rows.Err()
+}
+
+func setNightOption(chatID int64, option string, value any) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO nightMode (chat_id, `+option+`) VALUES (?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET `+option+` = excluded.`+option+`;
+	`, chatID, value)
+	return err
+}
+
+func setNightActive(chatID int64, active bool, permissions string) error {
+	_, err := database.DB.Exec(
+		"UPDATE nightMode SET active = ?, permissions = ? WHERE chat_id = ?;",
+		active, permissions, chatID,
+	)
+	return err
+}
@@ -0,0 +1,54 @@
+package nightmode
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+type nightModeSection struct {
+	Enabled  bool   `json:"enabled"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone"`
+}
+
+// exportNightMode returns the night mode schedule of chatID. Whether the
+// chat is locked right now isn't exported; the scheduler works it out.
+func exportNightMode(chatID int64) (any, error) {
+	settings, err := getNightSettings(chatID)
+	return nightModeSection{
+		Enabled:  settings.Enabled,
+		Start:    formatMinute(settings.Start),
+		End:      formatMinute(settings.End),
+		Timezone: settings.Timezone,
+	}, err
+}
+
+func importNightMode(tx *sql.Tx, chatID int64, data json.RawMessage) error {
+	var section nightModeSection
+	if err := json.Unmarshal(data, &section); err != nil {
+		return err
+	}
+
+	settings := defaultSettings(chatID)
+	settings.Enabled = section.Enabled
+	start, okStart := parseMinute(section.Start)
+	end, okEnd := parseMinute(section.End)
+	if okStart && okEnd && start != end {
+		settings.Start, settings.End = start, end
+	}
+	if location, err := time.LoadLocation(section.Timezone); err == nil && section.Timezone != "Local" {
+		settings.Timezone = location.String()
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO nightMode (chat_id, enabled, start_minute, end_minute, timezone) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET
+			enabled = excluded.enabled,
+			start_minute = excluded.start_minute,
+			end_minute = excluded.end_minute,
+			timezone = excluded.timezone;
+	`, chatID, settings.Enabled, settings.Start, settings.End, settings.Timezone)
+	return err
+}
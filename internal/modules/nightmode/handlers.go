@@ -0,0 +1,396 @@
+package nightmode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/modules/moderation"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+const (
+	scheduleInterval = 30 * time.Second
+	minutesPerDay    = 24 * 60
+	// configStep is how much the /config buttons move the start and end times.
+	configStep = 30
+)
+
+func formatMinute(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
+
+// parseMinute reads a time of day as HH:MM or HH into minutes since midnight.
+func parseMinute(s string) (int, bool) {
+	hours, minutes, found := strings.Cut(s, ":")
+	h, err := strconv.Atoi(hours)
+	if err != nil || h < 0 || h > 23 {
+		return 0, false
+	}
+	m := 0
+	if found {
+		if m, err = strconv.Atoi(minutes); err != nil || m < 0 || m > 59 {
+			return 0, false
+		}
+	}
+	return h*60 + m, true
+}
+
+// isNight reports whether now falls between start and end, which may wrap
+// around midnight.
+func (s nightSettings) isNight(now time.Time) bool {
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	now = now.In(location)
+	minute := now.Hour()*60 + now.Minute()
+
+	switch {
+	case s.Start == s.End:
+		return false
+	case s.Start < s.End:
+		return minute >= s.Start && minute < s.End
+	default:
+		return minute >= s.Start || minute < s.End
+	}
+}
+
+func chatI18n(chatID int64) func(string, ...map[string]any) string {
+	return localization.Get(&models.Update{Message: &models.Message{
+		Chat: models.Chat{ID: chatID, Type: models.ChatTypeSupergroup},
+	}})
+}
+
+// startNight saves the current permissions of the chat and locks it.
+func startNight(ctx context.Context, b *bot.Bot, settings nightSettings) error {
+	chat, err := b.GetChat(ctx, &bot.GetChatParams{ChatID: settings.ChatID})
+	if err != nil {
+		return err
+	}
+
+	var permissions []byte
+	if chat.Permissions != nil {
+		if permissions, err = json.Marshal(chat.Permissions); err != nil {
+			return err
+		}
+	}
+
+	if _, err := b.SetChatPermissions(ctx, &bot.SetChatPermissionsParams{
+		ChatID:      settings.ChatID,
+		Permissions: models.ChatPermissions{},
+	}); err != nil {
+		return err
+	}
+
+	if err := setNightActive(settings.ChatID, true, string(permissions)); err != nil {
+		return err
+	}
+
+	i18n := chatI18n(settings.ChatID)
+	utils.SendMessage(ctx, b, settings.ChatID, 0, i18n("nightmode-started", map[string]any{
+		"end": formatMinute(settings.End),
+	}))
+	return nil
+}
+
+// endNight restores the permissions the chat had before night mode.
+func endNight(ctx context.Context, b *bot.Bot, settings nightSettings) error {
+	// Chats locked before their permissions could be read get the defaults
+	// Telegram gives to new groups.
+	permissions := models.ChatPermissions{
+		CanSendMessages:       true,
+		CanSendAudios:         true,
+		CanSendDocuments:      true,
+		CanSendPhotos:         true,
+		CanSendVideos:         true,
+		CanSendVideoNotes:     true,
+		CanSendVoiceNotes:     true,
+		CanSendPolls:          true,
+		CanSendOtherMessages:  true,
+		CanAddWebPagePreviews: true,
+	}
+	if settings.Permissions != "" {
+		if err := json.Unmarshal([]byte(settings.Permissions), &permissions); err != nil {
+			slog.Error("Couldn't decode saved permissions",
+				"ChatID", settings.ChatID,
+				"Error", err.Error())
+		}
+	}
+
+	if _, err := b.SetChatPermissions(ctx, &bot.SetChatPermissionsParams{
+		ChatID:                        settings.ChatID,
+		Permissions:                   permissions,
+		UseIndependentChatPermissions: true,
+	}); err != nil {
+		return err
+	}
+
+	if err := setNightActive(settings.ChatID, false, ""); err != nil {
+		return err
+	}
+
+	i18n := chatI18n(settings.ChatID)
+	utils.SendMessage(ctx, b, settings.ChatID, 0, i18n("nightmode-ended", map[string]any{
+		"start": formatMinute(settings.Start),
+	}))
+	return nil
+}
+
+// updateNight locks or unlocks the chat as its night starts or ends.
+func updateNight(ctx context.Context, b *bot.Bot, settings nightSettings) {
+	night := settings.Enabled && settings.isNight(time.Now())
+	var err error
+	switch {
+	case night && !settings.Active:
+		err = startNight(ctx, b, settings)
+	case !night && settings.Active:
+		err = endNight(ctx, b, settings)
+	default:
+		return
+	}
+	if err != nil {
+		slog.Warn("Couldn't update night mode",
+			"ChatID", settings.ChatID,
+			"Night", night,
+			"Error", err.Error())
+	}
+}
+
+func statusText(i18n func(string, ...map[string]any) string, settings nightSettings) string {
+	return i18n("nightmode-status", map[string]any{
+		"enabled":  strconv.FormatBool(settings.Enabled),
+		"start":    formatMinute(settings.Start),
+		"end":      formatMinute(settings.End),
+		"timezone": settings.Timezone,
+	})
+}
+
+func nightModeHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightChangeInfo)
+	if !ok {
+		return
+	}
+
+	settings, err := getNightSettings(chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get night mode settings",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	if fields := strings.Fields(message.Text); len(fields) > 1 {
+		enabled, ok := utils.ParseSwitch(fields[1])
+		if !ok {
+			utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("nightmode-usage"))
+			return
+		}
+		if enabled && !moderation.CheckChatBotRight(ctx, b, message, chat, moderation.RightRestrictMembers) {
+			return
+		}
+		settings.Enabled = enabled
+
+		if err := setNightOption(chat.ID, "enabled", settings.Enabled); err != nil {
+			slog.Error("Couldn't update night mode",
+				"ChatID", chat.ID,
+				"Error", err.Error())
+			return
+		}
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, statusText(i18n, settings))
+}
+
+func nightTimeHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightChangeInfo)
+	if !ok {
+		return
+	}
+
+	fields := strings.Fields(message.Text)
+	if len(fields) != 3 {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("nighttime-usage"))
+		return
+	}
+
+	start, okStart := parseMinute(fields[1])
+	end, okEnd := parseMinute(fields[2])
+	if !okStart || !okEnd || start == end {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("nighttime-usage"))
+		return
+	}
+
+	err := setNightOption(chat.ID, "start_minute", start)
+	if err == nil {
+		err = setNightOption(chat.ID, "end_minute", end)
+	}
+	if err != nil {
+		slog.Error("Couldn't set night mode time",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("nighttime-set", map[string]any{
+		"start": formatMinute(start),
+		"end":   formatMinute(end),
+	}))
+}
+
+func nightTimezoneHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightChangeInfo)
+	if !ok {
+		return
+	}
+
+	fields := strings.Fields(message.Text)
+	if len(fields) != 2 {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("nighttz-usage"))
+		return
+	}
+
+	location, err := time.LoadLocation(fields[1])
+	if err != nil || fields[1] == "Local" {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("nighttz-invalid", map[string]any{
+			"timezone": utils.EscapeHTML(fields[1]),
+		}))
+		return
+	}
+
+	if err := setNightOption(chat.ID, "timezone", location.String()); err != nil {
+		slog.Error("Couldn't set night mode timezone",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("nighttz-set", map[string]any{
+		"timezone": location.String(),
+		"time":     time.Now().In(location).Format("15:04"),
+	}))
+}
+
+// nightModeConfigCallback shows the night mode page of /config, where admins
+// toggle it and move its start and end times.
+func nightModeConfigCallback(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.CallbackQuery.Message.Message
+	i18n := localization.Get(update)
+
+	chat := moderation.CallbackChat(ctx, b, update.CallbackQuery)
+	if !moderation.CheckChatRightCallback(ctx, b, update.CallbackQuery, chat.ID, moderation.RightChangeInfo) {
+		return
+	}
+
+	settings, err := getNightSettings(chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get night mode settings",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	shift := func(minute, delta int) int {
+		return ((minute+delta)%minutesPerDay + minutesPerDay) % minutesPerDay
+	}
+
+	option, value := "", any(nil)
+	switch strings.TrimPrefix(update.CallbackQuery.Data, "nightModeConfig") {
+	case " toggle":
+		if !settings.Enabled && !moderation.CheckChatBotRightCallback(ctx, b, update.CallbackQuery, chat.ID, moderation.RightRestrictMembers) {
+			return
+		}
+		settings.Enabled = !settings.Enabled
+		option, value = "enabled", settings.Enabled
+	case " start-":
+		settings.Start = shift(settings.Start, -configStep)
+		option, value = "start_minute", settings.Start
+	case " start+":
+		settings.Start = shift(settings.Start, configStep)
+		option, value = "start_minute", settings.Start
+	case " end-":
+		settings.End = shift(settings.End, -configStep)
+		option, value = "end_minute", settings.End
+	case " end+":
+		settings.End = shift(settings.End, configStep)
+		option, value = "end_minute", settings.End
+	case " info":
+		utils.SendCallbackReply(ctx, b, update.CallbackQuery.ID, i18n("nightmode-time-alert"))
+		return
+	case " timezone":
+		utils.SendCallbackReply(ctx, b, update.CallbackQuery.ID, i18n("nightmode-timezone-alert"))
+		return
+	}
+
+	if option != "" {
+		if err := setNightOption(chat.ID, option, value); err != nil {
+			slog.Error("Couldn't update night mode",
+				"ChatID", chat.ID,
+				"Option", option,
+				"Error", err.Error())
+			return
+		}
+	}
+
+	state := "☑️"
+	if settings.Enabled {
+		state = "✅"
+	}
+	buttons := [][]models.InlineKeyboardButton{
+		{
+			{Text: state + " " + i18n("nightmode-button"), CallbackData: "nightModeConfig toggle"},
+		},
+		{
+			{Text: "➖", CallbackData: "nightModeConfig start-"},
+			{Text: i18n("nightmode-start-button", map[string]any{"time": formatMinute(settings.Start)}), CallbackData: "nightModeConfig info"},
+			{Text: "➕", CallbackData: "nightModeConfig start+"},
+		},
+		{
+			{Text: "➖", CallbackData: "nightModeConfig end-"},
+			{Text: i18n("nightmode-end-button", map[string]any{"time": formatMinute(settings.End)}), CallbackData: "nightModeConfig info"},
+			{Text: "➕", CallbackData: "nightModeConfig end+"},
+		},
+		{
+			{Text: "🌐 " + settings.Timezone, CallbackData: "nightModeConfig timezone"},
+		},
+		{
+			{Text: i18n("back-button"), CallbackData: "config"},
+		},
+	}
+
+	utils.EditMessage(ctx, b, message.Chat.ID, message.ID,
+		i18n("config-nightmode"),
+		utils.WithReplyMarkup(&models.InlineKeyboardMarkup{InlineKeyboard: buttons}))
+}
+
+func Load(b *bot.Bot) {
+	b.RegisterHandler(bot.HandlerTypeMessageText, "nightmode", bot.MatchTypeCommand, nightModeHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "nighttime", bot.MatchTypeCommand, nightTimeHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "nighttz", bot.MatchTypeCommand, nightTimezoneHandler)
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "nightModeConfig", bot.MatchTypePrefix, nightModeConfigCallback)
+
+	utils.Schedule(b, "nightmode", scheduleInterval, func(time.Time) ([]nightSettings, error) {
+		return getScheduledChats()
+	}, updateNight)
+
+	utils.RegisterChatSection("nightmode", utils.ChatSection{Export: exportNightMode, Import: importNightMode})
+	utils.SaveHelp("nightmode")
+}
@@ -0,0 +1,63 @@
+package notes
+
+import (
+	"database/sql"
+
+	"github.com/angelomds42/EleineBot/internal/database"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+// getNote returns nil when the chat has no note with the given name.
+func getNote(chatID int64, name string) (*utils.Content, error) {
+	var content utils.Content
+	err := database.DB.QueryRow(
+		"SELECT COALESCE(text, ''), COALESCE(media_type, ''), COALESCE(file_id, '') FROM notes WHERE chat_id = ? AND name = ?;",
+		chatID, name,
+	).Scan(&content.Text, &content.MediaType, &content.FileID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &content, nil
+}
+
+func getNoteNames(chatID int64) ([]string, error) {
+	rows, err := database.DB.Query("SELECT name FROM notes WHERE chat_id = ? ORDER BY name;", chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+func saveNote(chatID int64, name string, content utils.Content) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO notes (chat_id, name, text, media_type, file_id)
+		VALUES (?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''))
+		ON CONFLICT(chat_id, name) DO UPDATE SET
+			text = excluded.text,
+			media_type = excluded.media_type,
+			file_id = excluded.file_id;
+	`, chatID, name, content.Text, content.MediaType, content.FileID)
+	return err
+}
+
+func deleteNote(chatID int64, name string) (bool, error) {
+	result, err := database.DB.Exec("DELETE FROM notes WHERE chat_id = ? AND name = ?;", chatID, name)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
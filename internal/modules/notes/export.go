@@ -0,0 +1,58 @@
+package notes
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/angelomds42/EleineBot/internal/database"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+type exportedNote struct {
+	Name    string        `json:"name"`
+	Content utils.Content `json:"content"`
+}
+
+func exportNotes(chatID int64) (any, error) {
+	rows, err := database.DB.Query(`
+		SELECT name, COALESCE(text, ''), COALESCE(media_type, ''), COALESCE(file_id, '')
+		FROM notes WHERE chat_id = ? ORDER BY name;
+	`, chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notes := []exportedNote{}
+	for rows.Next() {
+		var note exportedNote
+		if err := rows.Scan(&note.Name, &note.Content.Text, &note.Content.MediaType, &note.Content.FileID); err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
+
+func importNotes(tx *sql.Tx, chatID int64, data json.RawMessage) error {
+	var notes []exportedNote
+	if err := json.Unmarshal(data, &notes); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM notes WHERE chat_id = ?;", chatID); err != nil {
+		return err
+	}
+	for _, note := range notes {
+		if note.Name == "" || (note.Content.Text == "" && note.Content.FileID == "") {
+			continue
+		}
+		if _, err := tx.Exec(`
+			INSERT OR REPLACE INTO notes (chat_id, name, text, media_type, file_id)
+			VALUES (?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''));
+		`, chatID, note.Name, note.Content.Text, note.Content.MediaType, note.Content.FileID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
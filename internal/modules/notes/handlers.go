@@ -0,0 +1,231 @@
+package notes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/modules/moderation"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+const privateTag = "{private}"
+
+var (
+	noteNameRegex    = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
+	noteHashtagRegex = regexp.MustCompile(`^#([\p{L}\p{N}_-]+)`)
+)
+
+// sendNote sends the note called name, returning false when it does not exist.
+func sendNote(ctx context.Context, b *bot.Bot, update *models.Update, name string, replyTo int) bool {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	note, err := getNote(message.Chat.ID, name)
+	if err != nil {
+		slog.Error("Couldn't get note",
+			"ChatID", message.Chat.ID,
+			"Name", name,
+			"Error", err.Error())
+		return true
+	}
+	if note == nil {
+		return false
+	}
+
+	text, rows := utils.ParseButtons(note.Text)
+	private := strings.Contains(text, privateTag)
+	text = strings.TrimSpace(strings.ReplaceAll(text, privateTag, ""))
+	note.Text = utils.FormatPlaceholders(text, utils.UserPlaceholders(message.From, message.Chat))
+
+	var markup *models.InlineKeyboardMarkup
+	if len(rows) > 0 {
+		markup = &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+	}
+
+	if !private || message.Chat.Type == models.ChatTypePrivate {
+		if _, err := utils.SendContent(ctx, b, message.Chat.ID, replyTo, *note, markup); err != nil {
+			slog.Error("Couldn't send note",
+				"ChatID", message.Chat.ID,
+				"Name", name,
+				"Error", err.Error())
+		}
+		return true
+	}
+
+	if _, err := utils.SendContent(ctx, b, message.From.ID, 0, *note, markup); err == nil {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("note-sent-private"))
+		return true
+	}
+
+	botUser, err := b.GetMe(ctx)
+	if err != nil {
+		slog.Error("GetMe failed", "error", err)
+		return true
+	}
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("note-private-start"),
+		utils.WithReplyMarkupSend(&models.InlineKeyboardMarkup{
+			InlineKeyboard: [][]models.InlineKeyboardButton{{
+				{Text: i18n("start-button"), URL: fmt.Sprintf("https://t.me/%s?start=start", botUser.Username)},
+			}},
+		}))
+	return true
+}
+
+func getHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	fields := strings.Fields(message.Text)
+	if len(fields) < 2 {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("get-usage"))
+		return
+	}
+
+	replyTo := message.ID
+	if message.ReplyToMessage != nil {
+		replyTo = message.ReplyToMessage.ID
+	}
+
+	name := strings.ToLower(strings.TrimPrefix(fields[1], "#"))
+	if !sendNote(ctx, b, update, name, replyTo) {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("note-not-found", map[string]any{
+			"name": utils.EscapeHTML(name),
+		}))
+	}
+}
+
+func hashtagHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+
+	replyTo := message.ID
+	if message.ReplyToMessage != nil {
+		replyTo = message.ReplyToMessage.ID
+	}
+
+	name := strings.ToLower(noteHashtagRegex.FindStringSubmatch(message.Text)[1])
+	sendNote(ctx, b, update, name, replyTo)
+}
+
+func notesHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	names, err := getNoteNames(message.Chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get notes",
+			"ChatID", message.Chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	if len(names) == 0 {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("notes-empty"))
+		return
+	}
+
+	text := i18n("notes-list")
+	for _, name := range names {
+		text += "\n - <code>#" + utils.EscapeHTML(name) + "</code>"
+	}
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, text)
+}
+
+func checkNotesRights(ctx context.Context, b *bot.Bot, message *models.Message) bool {
+	if message.Chat.Type == models.ChatTypePrivate {
+		i18n := localization.Get(&models.Update{Message: message})
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("group-only"))
+		return false
+	}
+	return moderation.CheckUserRight(ctx, b, message, moderation.RightChangeInfo)
+}
+
+func saveHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	if !checkNotesRights(ctx, b, message) {
+		return
+	}
+
+	args := utils.SplitArgs(message.Text)
+	if len(args) < 2 {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("save-usage"))
+		return
+	}
+
+	name := strings.ToLower(strings.TrimPrefix(args[1], "#"))
+	if !noteNameRegex.MatchString(name) {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("note-invalid-name"))
+		return
+	}
+
+	content, ok := utils.ExtractContent(message, 1)
+	if !ok {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("save-usage"))
+		return
+	}
+
+	if err := saveNote(message.Chat.ID, name, content); err != nil {
+		slog.Error("Couldn't save note",
+			"ChatID", message.Chat.ID,
+			"Name", name,
+			"Error", err.Error())
+		return
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("note-saved", map[string]any{"name": name}))
+}
+
+func clearHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	if !checkNotesRights(ctx, b, message) {
+		return
+	}
+
+	fields := strings.Fields(message.Text)
+	if len(fields) < 2 {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("clear-usage"))
+		return
+	}
+
+	name := strings.ToLower(strings.TrimPrefix(fields[1], "#"))
+	removed, err := deleteNote(message.Chat.ID, name)
+	if err != nil {
+		slog.Error("Couldn't delete note",
+			"ChatID", message.Chat.ID,
+			"Name", name,
+			"Error", err.Error())
+		return
+	}
+
+	respKey := "note-not-found"
+	if removed {
+		respKey = "note-deleted"
+	}
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n(respKey, map[string]any{
+		"name": utils.EscapeHTML(name),
+	}))
+}
+
+func Load(b *bot.Bot) {
+	b.RegisterHandler(bot.HandlerTypeMessageText, "get", bot.MatchTypeCommand, getHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "notes", bot.MatchTypeCommand, notesHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "saved", bot.MatchTypeCommand, notesHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "save", bot.MatchTypeCommand, saveHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "clear", bot.MatchTypeCommand, clearHandler)
+	b.RegisterHandlerRegexp(bot.HandlerTypeMessageText, noteHashtagRegex, hashtagHandler)
+
+	utils.RegisterChatSection("notes", utils.ChatSection{Export: exportNotes, Import: importNotes})
+	utils.SaveHelp("notes")
+	utils.RegisterDisableable("notes", "get", "notes")
+	utils.RegisterAliases("notes", "saved")
+}
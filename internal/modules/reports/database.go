@@ -0,0 +1,41 @@
+package reports
+
+import (
+	"database/sql"
+
+	"github.com/angelomds42/EleineBot/internal/database"
+)
+
+func getReportsEnabled(chatID int64) (bool, error) {
+	var enabled bool
+	err := database.DB.QueryRow("SELECT enabled FROM reportSettings WHERE chat_id = ?;", chatID).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		return true, nil
+	}
+	return enabled, err
+}
+
+func setReportsEnabled(chatID int64, enabled bool) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO reportSettings (chat_id, enabled) VALUES (?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET enabled = excluded.enabled;
+	`, chatID, enabled)
+	return err
+}
+
+func isSubscribed(userID int64) (bool, error) {
+	var exists bool
+	err := database.DB.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM reportSubscribers WHERE user_id = ?);", userID,
+	).Scan(&exists)
+	return exists, err
+}
+
+func setSubscribed(userID int64, subscribed bool) error {
+	query := "DELETE FROM reportSubscribers WHERE user_id = ?;"
+	if subscribed {
+		query = "INSERT OR IGNORE INTO reportSubscribers (user_id) VALUES (?);"
+	}
+	_, err := database.DB.Exec(query, userID)
+	return err
+}
@@ -0,0 +1,24 @@
+package reports
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+type reportsSection struct {
+	Enabled bool `json:"enabled"`
+}
+
+func exportReports(chatID int64) (any, error) {
+	enabled, err := getReportsEnabled(chatID)
+	return reportsSection{Enabled: enabled}, err
+}
+
+func importReports(tx *sql.Tx, chatID int64, data json.RawMessage) error {
+	section := reportsSection{Enabled: true}
+	if err := json.Unmarshal(data, &section); err != nil {
+		return err
+	}
+	_, err := tx.Exec("INSERT OR REPLACE INTO reportSettings (chat_id, enabled) VALUES (?, ?);", chatID, section.Enabled)
+	return err
+}
@@ -0,0 +1,357 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/modules/moderation"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+const (
+	reporterCooldown = 2 * time.Minute
+	messageCooldown  = 10 * time.Minute
+)
+
+var adminMentionRegex = regexp.MustCompile(`(?i)(?:^|\s)@admins?\b`)
+
+// recentReports remembers reporters and reported messages to stop users from
+// flooding the admins with notifications.
+var (
+	recentReports      = make(map[string]time.Time)
+	recentReportsMutex sync.Mutex
+)
+
+type cooldown struct {
+	key      string
+	duration time.Duration
+}
+
+// checkCooldowns returns the index of the first of cooldowns whose key is
+// still in use, or -1 when they're all free, marking them all as used then.
+// Nothing is marked otherwise, so a rejected report costs no cooldown.
+func checkCooldowns(cooldowns ...cooldown) int {
+	recentReportsMutex.Lock()
+	defer recentReportsMutex.Unlock()
+
+	now := time.Now()
+	for k, expiry := range recentReports {
+		if now.After(expiry) {
+			delete(recentReports, k)
+		}
+	}
+
+	for i, c := range cooldowns {
+		if _, ok := recentReports[c.key]; ok {
+			return i
+		}
+	}
+	for _, c := range cooldowns {
+		recentReports[c.key] = now.Add(c.duration)
+	}
+	return -1
+}
+
+func reportKeyboard(
+	i18n func(string, ...map[string]any) string,
+	chatID, userID int64,
+	messageID int,
+) *models.InlineKeyboardMarkup {
+	button := func(action string) models.InlineKeyboardButton {
+		return models.InlineKeyboardButton{
+			Text:         i18n("report-" + action + "-button"),
+			CallbackData: fmt.Sprintf("report %s %d %d %d", action, chatID, userID, messageID),
+		}
+	}
+	return &models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{
+		{button("ban"), button("mute")},
+		{button("delete"), button("ignore")},
+	}}
+}
+
+func reportHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	if message.Chat.Type == models.ChatTypePrivate {
+		if strings.HasPrefix(message.Text, "/") {
+			utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("group-only"))
+		}
+		return
+	}
+
+	enabled, err := getReportsEnabled(message.Chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get report settings",
+			"ChatID", message.Chat.ID,
+			"Error", err.Error())
+		return
+	}
+	if !enabled {
+		return
+	}
+
+	reported := message.ReplyToMessage
+	if reported == nil || reported.From == nil {
+		// Mentioning the admins without replying is just talking to them.
+		if strings.HasPrefix(message.Text, "/") {
+			utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("report-usage"))
+		}
+		return
+	}
+
+	if reported.From.ID == message.From.ID || reported.From.ID == b.ID() || utils.IsAnonymousAdmin(reported) ||
+		moderation.IsAdmin(ctx, b, message.Chat.ID, reported.From.ID) {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("report-not-allowed"))
+		return
+	}
+
+	// Admins can act directly, there is no one else to notify.
+	if utils.IsAnonymousAdmin(message) || moderation.IsAdmin(ctx, b, message.Chat.ID, message.From.ID) {
+		return
+	}
+
+	switch checkCooldowns(
+		cooldown{fmt.Sprintf("%d:message:%d", message.Chat.ID, reported.ID), messageCooldown},
+		cooldown{fmt.Sprintf("%d:%d", message.Chat.ID, message.From.ID), reporterCooldown},
+	) {
+	case 0:
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("report-already"))
+		return
+	case 1:
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("report-cooldown"))
+		return
+	}
+
+	admins, err := b.GetChatAdministrators(ctx, &bot.GetChatAdministratorsParams{ChatID: message.Chat.ID})
+	if err != nil {
+		slog.Error("Couldn't get chat administrators",
+			"ChatID", message.Chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	var reason string
+	if fields := strings.Fields(message.Text); len(fields) > 1 && strings.HasPrefix(fields[0], "/") {
+		reason = utils.EscapeHTML(strings.Join(fields[1:], " "))
+	}
+
+	// Admins are tagged with invisible mentions so they get notified without
+	// flooding the chat with their names.
+	var mentions strings.Builder
+	var adminIDs []int64
+	for _, admin := range admins {
+		var user *models.User
+		switch admin.Type {
+		case models.ChatMemberTypeOwner:
+			user = admin.Owner.User
+		case models.ChatMemberTypeAdministrator:
+			user = &admin.Administrator.User
+		}
+		if user == nil || user.IsBot {
+			continue
+		}
+		adminIDs = append(adminIDs, user.ID)
+		fmt.Fprintf(&mentions, "<a href='tg://user?id=%d'>​</a>", user.ID)
+	}
+
+	text := i18n("report-sent", map[string]any{
+		"reporter": utils.MentionUser(message.From),
+		"user":     utils.MentionUser(reported.From),
+	})
+	if reason != "" {
+		text += "\n" + i18n("report-reason", map[string]any{"reason": reason})
+	}
+	text += mentions.String()
+	utils.SendMessage(ctx, b, message.Chat.ID, reported.ID, text,
+		utils.WithReplyMarkupSend(reportKeyboard(i18n, message.Chat.ID, reported.From.ID, reported.ID)))
+
+	notifySubscribers(ctx, b, message, adminIDs, reason)
+}
+
+// notifySubscribers sends the report in private to the admins who opted in.
+func notifySubscribers(ctx context.Context, b *bot.Bot, message *models.Message, adminIDs []int64, reason string) {
+	reported := message.ReplyToMessage
+	for _, adminID := range adminIDs {
+		subscribed, err := isSubscribed(adminID)
+		if err != nil {
+			slog.Error("Couldn't check report subscription",
+				"UserID", adminID,
+				"Error", err.Error())
+			continue
+		}
+		if !subscribed {
+			continue
+		}
+
+		i18n := localization.Get(&models.Update{Message: &models.Message{
+			Chat: models.Chat{ID: adminID, Type: models.ChatTypePrivate},
+		}})
+		text := i18n("report-private", map[string]any{
+			"reporter": utils.MentionUser(message.From),
+			"user":     utils.MentionUser(reported.From),
+			"chatName": utils.EscapeHTML(message.Chat.Title),
+			"link":     utils.MessageLink(message.Chat.ID, message.Chat.Username, reported.ID),
+		})
+		if reason != "" {
+			text += "\n" + i18n("report-reason", map[string]any{"reason": reason})
+		}
+		if _, err := b.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID:             adminID,
+			Text:               text,
+			ParseMode:          models.ParseModeHTML,
+			LinkPreviewOptions: &models.LinkPreviewOptions{IsDisabled: bot.True()},
+			ReplyMarkup:        reportKeyboard(i18n, message.Chat.ID, reported.From.ID, reported.ID),
+		}); err != nil {
+			slog.Debug("Couldn't send report in private",
+				"UserID", adminID,
+				"Error", err.Error())
+		}
+	}
+}
+
+func reportCallback(ctx context.Context, b *bot.Bot, update *models.Update) {
+	i18n := localization.Get(update)
+	message := update.CallbackQuery.Message.Message
+
+	parts := strings.Fields(update.CallbackQuery.Data)
+	if len(parts) != 5 {
+		return
+	}
+	action := parts[1]
+	chatID, _ := strconv.ParseInt(parts[2], 10, 64)
+	userID, _ := strconv.ParseInt(parts[3], 10, 64)
+	messageID, _ := strconv.Atoi(parts[4])
+
+	right := moderation.RightRestrictMembers
+	switch action {
+	case "delete":
+		right = moderation.RightDeleteMessages
+	case "ignore":
+		right = ""
+	}
+	if !moderation.CheckChatRightCallback(ctx, b, update.CallbackQuery, chatID, right) {
+		return
+	}
+
+	var err error
+	switch action {
+	case "ban":
+		_, err = b.BanChatMember(ctx, &bot.BanChatMemberParams{ChatID: chatID, UserID: userID})
+	case "mute":
+		_, err = b.RestrictChatMember(ctx, &bot.RestrictChatMemberParams{
+			ChatID:      chatID,
+			UserID:      userID,
+			Permissions: &models.ChatPermissions{},
+		})
+	case "delete":
+		_, err = b.DeleteMessage(ctx, &bot.DeleteMessageParams{ChatID: chatID, MessageID: messageID})
+	case "ignore":
+	default:
+		return
+	}
+	if err != nil {
+		slog.Error("Couldn't apply report action",
+			"ChatID", chatID,
+			"UserID", userID,
+			"Action", action,
+			"Error", err.Error())
+		utils.SendCallbackReply(ctx, b, update.CallbackQuery.ID, i18n("report-action-failed"))
+		return
+	}
+
+	utils.EditMessage(ctx, b, message.Chat.ID, message.ID, i18n("report-handled", map[string]any{
+		"action": action,
+		"admin":  utils.MentionUser(&update.CallbackQuery.From),
+		"userID": strconv.FormatInt(userID, 10),
+	}))
+
+	if action != "ignore" {
+		chat := message.Chat
+		if chat.ID != chatID {
+			chat = moderation.GroupChat(ctx, b, chatID)
+		}
+		moderation.SendLog(ctx, b, chat, moderation.LogEntry{
+			Action:   action,
+			Actor:    &update.CallbackQuery.From,
+			TargetID: userID,
+			Message:  &models.Message{ID: messageID, Chat: chat},
+		})
+	}
+}
+
+func reportsHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	fields := strings.Fields(message.Text)
+	var enabled, toggle bool
+	if len(fields) > 1 {
+		if enabled, toggle = utils.ParseSwitch(fields[1]); !toggle {
+			utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("reports-usage"))
+			return
+		}
+	}
+
+	// In private the command manages the user's own subscription, so unlike
+	// the other settings commands it doesn't act on a connected group.
+	if message.Chat.Type == models.ChatTypePrivate {
+		var err error
+		if toggle {
+			err = setSubscribed(message.From.ID, enabled)
+		} else {
+			enabled, err = isSubscribed(message.From.ID)
+		}
+		if err != nil {
+			slog.Error("Couldn't update report subscription",
+				"UserID", message.From.ID,
+				"Error", err.Error())
+			return
+		}
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("reports-private-status", map[string]any{
+			"enabled": strconv.FormatBool(enabled),
+		}))
+		return
+	}
+
+	if !moderation.CheckUserRight(ctx, b, message, moderation.RightChangeInfo) {
+		return
+	}
+
+	var err error
+	if toggle {
+		err = setReportsEnabled(message.Chat.ID, enabled)
+	} else {
+		enabled, err = getReportsEnabled(message.Chat.ID)
+	}
+	if err != nil {
+		slog.Error("Couldn't update report settings",
+			"ChatID", message.Chat.ID,
+			"Error", err.Error())
+		return
+	}
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("reports-group-status", map[string]any{
+		"enabled": strconv.FormatBool(enabled),
+	}))
+}
+
+func Load(b *bot.Bot) {
+	b.RegisterHandler(bot.HandlerTypeMessageText, "report", bot.MatchTypeCommand, reportHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "reports", bot.MatchTypeCommand, reportsHandler)
+	b.RegisterHandlerRegexp(bot.HandlerTypeMessageText, adminMentionRegex, reportHandler)
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "report ", bot.MatchTypePrefix, reportCallback)
+
+	utils.RegisterChatSection("reports", utils.ChatSection{Export: exportReports, Import: importReports})
+	utils.SaveHelp("reports")
+	utils.RegisterDisableable("reports", "report")
+}
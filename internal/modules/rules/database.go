@@ -0,0 +1,30 @@
+package rules
+
+import (
+	"database/sql"
+
+	"github.com/angelomds42/EleineBot/internal/database"
+)
+
+// getRules returns an empty string when the chat has no rules.
+func getRules(chatID int64) (string, error) {
+	var text string
+	err := database.DB.QueryRow("SELECT text FROM rules WHERE chat_id = ?;", chatID).Scan(&text)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return text, err
+}
+
+func setRules(chatID int64, text string) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO rules (chat_id, text) VALUES (?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET text = excluded.text;
+	`, chatID, text)
+	return err
+}
+
+func deleteRules(chatID int64) error {
+	_, err := database.DB.Exec("DELETE FROM rules WHERE chat_id = ?;", chatID)
+	return err
+}
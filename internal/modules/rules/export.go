@@ -0,0 +1,24 @@
+package rules
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+func exportRules(chatID int64) (any, error) {
+	return getRules(chatID)
+}
+
+func importRules(tx *sql.Tx, chatID int64, data json.RawMessage) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+
+	if text == "" {
+		_, err := tx.Exec("DELETE FROM rules WHERE chat_id = ?;", chatID)
+		return err
+	}
+	_, err := tx.Exec("INSERT OR REPLACE INTO rules (chat_id, text) VALUES (?, ?);", chatID, text)
+	return err
+}
@@ -0,0 +1,153 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/modules/moderation"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+func rulesHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	if message.Chat.Type == models.ChatTypePrivate {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("group-only"))
+		return
+	}
+
+	text, err := getRules(message.Chat.ID)
+	if err != nil {
+		slog.Error("Couldn't get rules",
+			"ChatID", message.Chat.ID,
+			"Error", err.Error())
+		return
+	}
+	if text == "" {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("rules-empty"))
+		return
+	}
+
+	botUser, err := b.GetMe(ctx)
+	if err != nil {
+		slog.Error("GetMe failed", "error", err)
+		return
+	}
+
+	replyTo := message.ID
+	if message.ReplyToMessage != nil {
+		replyTo = message.ReplyToMessage.ID
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, replyTo, i18n("rules-group"),
+		utils.WithReplyMarkupSend(&models.InlineKeyboardMarkup{
+			InlineKeyboard: [][]models.InlineKeyboardButton{{
+				{Text: i18n("rules-button"), URL: fmt.Sprintf("https://t.me/%s?start=rules_%d", botUser.Username, message.Chat.ID)},
+			}},
+		}))
+}
+
+// rulesStartHandler sends the rules of the group in args to the private chat.
+func rulesStartHandler(ctx context.Context, b *bot.Bot, update *models.Update, args string) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chatID, err := strconv.ParseInt(args, 10, 64)
+	if err != nil {
+		return
+	}
+
+	text, err := getRules(chatID)
+	if err != nil {
+		slog.Error("Couldn't get rules",
+			"ChatID", chatID,
+			"Error", err.Error())
+		return
+	}
+	if text == "" {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("rules-empty"))
+		return
+	}
+
+	chatName := args
+	if chat, err := b.GetChat(ctx, &bot.GetChatParams{ChatID: chatID}); err == nil {
+		chatName = chat.Title
+	}
+
+	text, rows := utils.ParseButtons(text)
+	content := utils.Content{Text: i18n("rules-private", map[string]any{
+		"chatName": utils.EscapeHTML(chatName),
+	}) + "\n\n" + text}
+
+	var markup *models.InlineKeyboardMarkup
+	if len(rows) > 0 {
+		markup = &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+	}
+	if _, err := utils.SendContent(ctx, b, message.Chat.ID, message.ID, content, markup); err != nil {
+		slog.Error("Couldn't send rules",
+			"ChatID", chatID,
+			"Error", err.Error())
+	}
+}
+
+func setRulesHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightChangeInfo)
+	if !ok {
+		return
+	}
+
+	content, ok := utils.ExtractContent(message, 0)
+	if !ok || content.Text == "" {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("setrules-usage"))
+		return
+	}
+
+	if err := setRules(chat.ID, content.Text); err != nil {
+		slog.Error("Couldn't set rules",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("rules-saved"))
+}
+
+func clearRulesHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	chat, ok := moderation.SettingsChat(ctx, b, message, moderation.RightChangeInfo)
+	if !ok {
+		return
+	}
+
+	if err := deleteRules(chat.ID); err != nil {
+		slog.Error("Couldn't clear rules",
+			"ChatID", chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("rules-cleared"))
+}
+
+func Load(b *bot.Bot) {
+	b.RegisterHandler(bot.HandlerTypeMessageText, "rules", bot.MatchTypeCommand, rulesHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "setrules", bot.MatchTypeCommand, setRulesHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "clearrules", bot.MatchTypeCommand, clearRulesHandler)
+
+	utils.RegisterStartPayload("rules", rulesStartHandler)
+	utils.RegisterChatSection("rules", utils.ChatSection{Export: exportRules, Import: importRules})
+	utils.SaveHelp("rules")
+	utils.RegisterDisableable("rules", "rules")
+}
@@ -96,7 +96,8 @@ func getStickerHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 		}
 
 		_, err = b.SendDocument(ctx, &bot.SendDocumentParams{
-			ChatID: update.Message.Chat.ID,
+			ChatID:          update.Message.Chat.ID,
+			MessageThreadID: utils.ChatThread(ctx, update.Message.Chat.ID),
 			Document: &models.InputFileUpload{
 				Filename: filepath.Base(b.FileDownloadLink(file)),
 				Data:     bytes.NewBuffer(data),
@@ -128,6 +129,7 @@ func checkStickerSetCount(ctx context.Context, b *bot.Bot, name string) bool {
 func sendProgressMessage(ctx context.Context, b *bot.Bot, update *models.Update, i18n func(string, ...map[string]any) string) *models.Message {
 	prog, err := b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:          update.Message.Chat.ID,
+		MessageThreadID: utils.ChatThread(ctx, update.Message.Chat.ID),
 		Text:            i18n("kanging"),
 		ParseMode:       models.ParseModeHTML,
 		ReplyParameters: &models.ReplyParameters{MessageID: update.Message.ID},
@@ -353,5 +355,5 @@ func Load(b *bot.Bot) {
 	b.RegisterHandler(bot.HandlerTypeMessageText, "kang", bot.MatchTypeCommand, kangStickerHandler)
 
 	utils.SaveHelp("stickers")
-	utils.DisableableCommands = append(utils.DisableableCommands, "getsticker", "kang")
+	utils.RegisterDisableable("stickers", "getsticker", "kang")
 }
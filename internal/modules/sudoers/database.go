@@ -0,0 +1,174 @@
+package sudoers
+
+import (
+	"database/sql"
+
+	"github.com/angelomds42/EleineBot/internal/database"
+)
+
+type gban struct {
+	UserID   int64  `json:"user_id"`
+	Reason   string `json:"reason"`
+	BannedBy int64  `json:"banned_by"`
+	Date     int64  `json:"date"`
+}
+
+func isSudoer(userID int64) (bool, error) {
+	var exists bool
+	err := database.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM sudoers WHERE user_id = ?);", userID).Scan(&exists)
+	return exists, err
+}
+
+func setSudoer(userID int64, sudo bool) (bool, error) {
+	query := "DELETE FROM sudoers WHERE user_id = ?;"
+	if sudo {
+		query = "INSERT OR IGNORE INTO sudoers (user_id) VALUES (?);"
+	}
+	result, err := database.DB.Exec(query, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func getSudoers() ([]int64, error) {
+	rows, err := database.DB.Query("SELECT user_id FROM sudoers;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []int64
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		users = append(users, userID)
+	}
+	return users, rows.Err()
+}
+
+func getGbans() ([]gban, error) {
+	rows, err := database.DB.Query("SELECT user_id, reason, banned_by, date FROM gbans ORDER BY date;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	gbans := []gban{}
+	for rows.Next() {
+		var g gban
+		var reason sql.NullString
+		var bannedBy sql.NullInt64
+		if err := rows.Scan(&g.UserID, &reason, &bannedBy, &g.Date); err != nil {
+			return nil, err
+		}
+		g.Reason, g.BannedBy = reason.String, bannedBy.Int64
+		gbans = append(gbans, g)
+	}
+	return gbans, rows.Err()
+}
+
+func countGbans() (int, error) {
+	var count int
+	err := database.DB.QueryRow("SELECT COUNT(*) FROM gbans;").Scan(&count)
+	return count, err
+}
+
+func saveGbans(gbans ...gban) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, g := range gbans {
+		if _, err := tx.Exec(`
+			INSERT INTO gbans (user_id, reason, banned_by, date) VALUES (?, ?, ?, ?)
+			ON CONFLICT(user_id) DO UPDATE SET
+				reason = excluded.reason,
+				banned_by = excluded.banned_by,
+				date = excluded.date;
+		`, g.UserID, g.Reason, g.BannedBy, g.Date); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func deleteGban(userID int64) (bool, error) {
+	result, err := database.DB.Exec("DELETE FROM gbans WHERE user_id = ?;", userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func getGbanEnabled(chatID int64) (bool, error) {
+	var enabled bool
+	err := database.DB.QueryRow("SELECT enabled FROM gbanSettings WHERE chat_id = ?;", chatID).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return enabled, err
+}
+
+func setGbanEnabled(chatID int64, enabled bool) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO gbanSettings (chat_id, enabled) VALUES (?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET enabled = excluded.enabled;
+	`, chatID, enabled)
+	return err
+}
+
+func getGbanChats() ([]int64, error) {
+	rows, err := database.DB.Query("SELECT chat_id FROM gbanSettings WHERE enabled = 1;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chats []int64
+	for rows.Next() {
+		var chatID int64
+		if err := rows.Scan(&chatID); err != nil {
+			return nil, err
+		}
+		chats = append(chats, chatID)
+	}
+	return chats, rows.Err()
+}
+
+// saveGbanBan records that userID was banned from chatID because of their
+// gban.
+func saveGbanBan(chatID, userID int64) error {
+	_, err := database.DB.Exec("INSERT OR IGNORE INTO gbanBans (chat_id, user_id) VALUES (?, ?);", chatID, userID)
+	return err
+}
+
+// getGbanBans returns the chats userID was banned from because of their gban.
+func getGbanBans(userID int64) ([]int64, error) {
+	rows, err := database.DB.Query("SELECT chat_id FROM gbanBans WHERE user_id = ?;", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chats []int64
+	for rows.Next() {
+		var chatID int64
+		if err := rows.Scan(&chatID); err != nil {
+			return nil, err
+		}
+		chats = append(chats, chatID)
+	}
+	return chats, rows.Err()
+}
+
+func deleteGbanBans(userID int64) error {
+	_, err := database.DB.Exec("DELETE FROM gbanBans WHERE user_id = ?;", userID)
+	return err
+}
@@ -0,0 +1,26 @@
+package sudoers
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+type gbanSection struct {
+	Enabled bool `json:"enabled"`
+}
+
+// exportGbanSettings returns whether chatID enforces the global bans. The
+// ban list itself is exported by /gbanexport.
+func exportGbanSettings(chatID int64) (any, error) {
+	enabled, err := getGbanEnabled(chatID)
+	return gbanSection{Enabled: enabled}, err
+}
+
+func importGbanSettings(tx *sql.Tx, chatID int64, data json.RawMessage) error {
+	var section gbanSection
+	if err := json.Unmarshal(data, &section); err != nil {
+		return err
+	}
+	_, err := tx.Exec("INSERT OR REPLACE INTO gbanSettings (chat_id, enabled) VALUES (?, ?);", chatID, section.Enabled)
+	return err
+}
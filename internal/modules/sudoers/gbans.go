@@ -0,0 +1,563 @@
+package sudoers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/config"
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/modules/moderation"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+const gbanExportVersion = 1
+
+// gbanExport is the JSON document produced by /gbanexport and read by /gbanimport.
+type gbanExport struct {
+	Version int    `json:"version"`
+	Gbans   []gban `json:"gbans"`
+}
+
+// The gban list and the opted-in chats are checked on every group message,
+// so both lookups are cached until a command changes them. The gban list is
+// loaded whole, so the cache holds nothing but the gbanned users.
+var (
+	gbanCache          map[int64]*gban
+	gbanCacheMutex     sync.RWMutex
+	gbanChatCache      = make(map[int64]bool)
+	gbanChatCacheMutex sync.RWMutex
+)
+
+func getCachedGban(userID int64) (*gban, error) {
+	gbanCacheMutex.RLock()
+	cache := gbanCache
+	gbanCacheMutex.RUnlock()
+	if cache != nil {
+		return cache[userID], nil
+	}
+
+	gbans, err := getGbans()
+	if err != nil {
+		return nil, err
+	}
+
+	cache = make(map[int64]*gban, len(gbans))
+	for i := range gbans {
+		cache[gbans[i].UserID] = &gbans[i]
+	}
+
+	gbanCacheMutex.Lock()
+	gbanCache = cache
+	gbanCacheMutex.Unlock()
+	return cache[userID], nil
+}
+
+func invalidateGbans() {
+	gbanCacheMutex.Lock()
+	gbanCache = nil
+	gbanCacheMutex.Unlock()
+}
+
+func getCachedGbanEnabled(chatID int64) (bool, error) {
+	gbanChatCacheMutex.RLock()
+	enabled, ok := gbanChatCache[chatID]
+	gbanChatCacheMutex.RUnlock()
+	if ok {
+		return enabled, nil
+	}
+
+	enabled, err := getGbanEnabled(chatID)
+	if err != nil {
+		return false, err
+	}
+
+	gbanChatCacheMutex.Lock()
+	gbanChatCache[chatID] = enabled
+	gbanChatCacheMutex.Unlock()
+	return enabled, nil
+}
+
+func invalidateGbanChat(chatID int64) {
+	gbanChatCacheMutex.Lock()
+	delete(gbanChatCache, chatID)
+	gbanChatCacheMutex.Unlock()
+}
+
+// IsGbanned reports whether userID is in the global ban list.
+func IsGbanned(userID int64) bool {
+	g, err := getCachedGban(userID)
+	if err != nil {
+		slog.Error("Couldn't get gban",
+			"UserID", userID,
+			"Error", err.Error())
+		return false
+	}
+	return g != nil
+}
+
+// CheckGbanMiddleware bans globally banned users from the groups that opted
+// in, as soon as they join or send their first message.
+func CheckGbanMiddleware(next bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		message := update.Message
+		if message == nil || message.Chat.Type == models.ChatTypePrivate || message.Chat.Type == models.ChatTypeChannel {
+			next(ctx, b, update)
+			return
+		}
+
+		enabled, err := getCachedGbanEnabled(message.Chat.ID)
+		if err != nil {
+			slog.Error("Couldn't get gban settings",
+				"ChatID", message.Chat.ID,
+				"Error", err.Error())
+		}
+		if !enabled {
+			next(ctx, b, update)
+			return
+		}
+
+		users := message.NewChatMembers
+		if len(users) == 0 && message.From != nil && message.SenderChat == nil {
+			users = []models.User{*message.From}
+		}
+
+		banned := false
+		for _, user := range users {
+			g, err := getCachedGban(user.ID)
+			if err != nil {
+				slog.Error("Couldn't check gban",
+					"UserID", user.ID,
+					"Error", err.Error())
+				continue
+			}
+			if g != nil && enforceGban(ctx, b, message, &user, g) {
+				banned = true
+			}
+		}
+
+		if banned {
+			b.DeleteMessage(ctx, &bot.DeleteMessageParams{ChatID: message.Chat.ID, MessageID: message.ID})
+			return
+		}
+		next(ctx, b, update)
+	}
+}
+
+func enforceGban(ctx context.Context, b *bot.Bot, message *models.Message, user *models.User, g *gban) bool {
+	if _, err := b.BanChatMember(ctx, &bot.BanChatMemberParams{ChatID: message.Chat.ID, UserID: user.ID}); err != nil {
+		slog.Debug("Couldn't enforce gban",
+			"ChatID", message.Chat.ID,
+			"UserID", user.ID,
+			"Error", err.Error())
+		return false
+	}
+	if err := saveGbanBan(message.Chat.ID, user.ID); err != nil {
+		slog.Error("Couldn't save gban ban",
+			"ChatID", message.Chat.ID,
+			"UserID", user.ID,
+			"Error", err.Error())
+	}
+
+	i18n := localization.Get(&models.Update{Message: message})
+	text := i18n("gban-enforced", map[string]any{
+		"userID":        strconv.FormatInt(user.ID, 10),
+		"userFirstName": utils.EscapeHTML(user.FirstName),
+	})
+	if g.Reason != "" {
+		text += "\n" + i18n("gban-reason", map[string]any{"reason": utils.EscapeHTML(g.Reason)})
+	}
+	utils.SendMessage(ctx, b, message.Chat.ID, 0, text)
+
+	moderation.SendLog(ctx, b, message.Chat, moderation.LogEntry{
+		Action: "gban",
+		Target: user,
+		Reason: g.Reason,
+	})
+	return true
+}
+
+// isSudo reports whether the sender is the owner or one of the sudo users.
+func isSudo(update *models.Update) bool {
+	if isOwner(update) {
+		return true
+	}
+
+	var userID int64
+	switch {
+	case update.Message != nil && update.Message.From != nil:
+		userID = update.Message.From.ID
+	case update.CallbackQuery != nil:
+		userID = update.CallbackQuery.From.ID
+	default:
+		return false
+	}
+
+	sudo, err := isSudoer(userID)
+	if err != nil {
+		slog.Error("Couldn't check sudoer",
+			"UserID", userID,
+			"Error", err.Error())
+		return false
+	}
+	return sudo
+}
+
+// applyToGbanChats runs action on every group that opted in to the gban list,
+// returning how many of them succeeded.
+func applyToGbanChats(ctx context.Context, b *bot.Bot, action func(chatID int64) error) int {
+	chats, err := getGbanChats()
+	if err != nil {
+		slog.Error("Couldn't get gban chats", "Error", err.Error())
+		return 0
+	}
+
+	var count int
+	for _, chatID := range chats {
+		if err := action(chatID); err != nil {
+			slog.Debug("Couldn't apply gban change",
+				"ChatID", chatID,
+				"Error", err.Error())
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// gbanChatMember bans userID from chatID because of their gban, recording
+// it so that an ungban only lifts the bans the gban caused. Users the chat
+// had already banned are left as they are.
+func gbanChatMember(ctx context.Context, b *bot.Bot, chatID, userID int64) error {
+	member, err := b.GetChatMember(ctx, &bot.GetChatMemberParams{ChatID: chatID, UserID: userID})
+	if err == nil && member.Type == models.ChatMemberTypeBanned {
+		return nil
+	}
+
+	if _, err := b.BanChatMember(ctx, &bot.BanChatMemberParams{ChatID: chatID, UserID: userID}); err != nil {
+		return err
+	}
+	return saveGbanBan(chatID, userID)
+}
+
+func gbanHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	if !isSudo(update) {
+		return
+	}
+	i18n := localization.Get(update)
+
+	userID, args, errMsg := moderation.ParseUserTarget(message)
+	if errMsg != "" {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n(errMsg))
+		return
+	}
+
+	protected, err := isSudoer(userID)
+	if err != nil {
+		slog.Error("Couldn't check sudoer",
+			"UserID", userID,
+			"Error", err.Error())
+		return
+	}
+	if protected || userID == config.OwnerID || userID == b.ID() {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("gban-protected"))
+		return
+	}
+
+	g := gban{
+		UserID:   userID,
+		Reason:   strings.Join(args, " "),
+		BannedBy: message.From.ID,
+		Date:     time.Now().Unix(),
+	}
+	if err := saveGbans(g); err != nil {
+		slog.Error("Couldn't save gban",
+			"UserID", userID,
+			"Error", err.Error())
+		return
+	}
+	invalidateGbans()
+
+	count := applyToGbanChats(ctx, b, func(chatID int64) error {
+		return gbanChatMember(ctx, b, chatID, userID)
+	})
+
+	text := i18n("gban-success", map[string]any{
+		"userID": strconv.FormatInt(userID, 10),
+		"count":  count,
+	})
+	if g.Reason != "" {
+		text += "\n" + i18n("gban-reason", map[string]any{"reason": utils.EscapeHTML(g.Reason)})
+	}
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, text)
+}
+
+func ungbanHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	if !isSudo(update) {
+		return
+	}
+	i18n := localization.Get(update)
+
+	userID, _, errMsg := moderation.ParseUserTarget(message)
+	if errMsg != "" {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n(errMsg))
+		return
+	}
+
+	removed, err := deleteGban(userID)
+	if err != nil {
+		slog.Error("Couldn't delete gban",
+			"UserID", userID,
+			"Error", err.Error())
+		return
+	}
+	if !removed {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("gban-not-found"))
+		return
+	}
+	invalidateGbans()
+
+	chats, err := getGbanBans(userID)
+	if err != nil {
+		slog.Error("Couldn't get gban bans",
+			"UserID", userID,
+			"Error", err.Error())
+		return
+	}
+
+	// Only the bans the gban caused are lifted, not the ones of the chats.
+	var count int
+	for _, chatID := range chats {
+		if _, err := b.UnbanChatMember(ctx, &bot.UnbanChatMemberParams{ChatID: chatID, UserID: userID, OnlyIfBanned: true}); err != nil {
+			slog.Debug("Couldn't lift gban ban",
+				"ChatID", chatID,
+				"UserID", userID,
+				"Error", err.Error())
+			continue
+		}
+		count++
+	}
+	if err := deleteGbanBans(userID); err != nil {
+		slog.Error("Couldn't delete gban bans",
+			"UserID", userID,
+			"Error", err.Error())
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("ungban-success", map[string]any{
+		"userID": strconv.FormatInt(userID, 10),
+		"count":  count,
+	}))
+}
+
+func gbanStatHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	if message.Chat.Type == models.ChatTypePrivate {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("group-only"))
+		return
+	}
+
+	var err error
+	var enabled bool
+	if fields := strings.Fields(message.Text); len(fields) > 1 {
+		var ok bool
+		if enabled, ok = utils.ParseSwitch(fields[1]); !ok {
+			utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("gbanstat-usage"))
+			return
+		}
+
+		if !moderation.CheckUserRight(ctx, b, message, moderation.RightRestrictMembers) {
+			return
+		}
+		err = setGbanEnabled(message.Chat.ID, enabled)
+		invalidateGbanChat(message.Chat.ID)
+	} else {
+		enabled, err = getGbanEnabled(message.Chat.ID)
+	}
+	if err != nil {
+		slog.Error("Couldn't update gban settings",
+			"ChatID", message.Chat.ID,
+			"Error", err.Error())
+		return
+	}
+
+	count, err := countGbans()
+	if err != nil {
+		slog.Error("Couldn't count gbans", "Error", err.Error())
+		return
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("gbanstat-status", map[string]any{
+		"enabled": strconv.FormatBool(enabled),
+		"count":   count,
+	}))
+}
+
+func gbanExportHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	if !isSudo(update) {
+		return
+	}
+	i18n := localization.Get(update)
+
+	gbans, err := getGbans()
+	if err != nil {
+		slog.Error("Couldn't get gbans", "Error", err.Error())
+		return
+	}
+
+	data, err := json.MarshalIndent(gbanExport{Version: gbanExportVersion, Gbans: gbans}, "", "  ")
+	if err != nil {
+		slog.Error("Couldn't encode gbans", "Error", err.Error())
+		return
+	}
+
+	if _, err := b.SendDocument(ctx, &bot.SendDocumentParams{
+		ChatID:          message.Chat.ID,
+		MessageThreadID: utils.ChatThread(ctx, message.Chat.ID),
+		Document: &models.InputFileUpload{
+			Filename: "gbans.json",
+			Data:     bytes.NewReader(data),
+		},
+		Caption:         i18n("gbanexport-caption", map[string]any{"count": len(gbans)}),
+		ParseMode:       models.ParseModeHTML,
+		ReplyParameters: &models.ReplyParameters{MessageID: message.ID},
+	}); err != nil {
+		slog.Error("Couldn't send gban export",
+			"ChatID", message.Chat.ID,
+			"Error", err.Error())
+	}
+}
+
+func gbanImportHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	if !isSudo(update) {
+		return
+	}
+	i18n := localization.Get(update)
+
+	if message.ReplyToMessage == nil || message.ReplyToMessage.Document == nil {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("gbanimport-usage"))
+		return
+	}
+
+	data, err := utils.DownloadDocument(ctx, b, message.ReplyToMessage.Document)
+	if err != nil {
+		slog.Error("Couldn't download gban list",
+			"ChatID", message.Chat.ID,
+			"Error", err.Error())
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("gbanimport-invalid"))
+		return
+	}
+
+	var export gbanExport
+	if err := json.Unmarshal(data, &export); err != nil || export.Version != gbanExportVersion {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("gbanimport-invalid"))
+		return
+	}
+
+	sudoers, err := getSudoers()
+	if err != nil {
+		slog.Error("Couldn't get sudoers", "Error", err.Error())
+		return
+	}
+
+	gbans := make([]gban, 0, len(export.Gbans))
+	for _, g := range export.Gbans {
+		// Same as /gban, the list can't ban the ones managing it.
+		if g.UserID == 0 || g.UserID == config.OwnerID || g.UserID == b.ID() || slices.Contains(sudoers, g.UserID) {
+			continue
+		}
+		if g.Date == 0 {
+			g.Date = time.Now().Unix()
+		}
+		if g.BannedBy == 0 {
+			g.BannedBy = message.From.ID
+		}
+		gbans = append(gbans, g)
+	}
+
+	if err := saveGbans(gbans...); err != nil {
+		slog.Error("Couldn't import gbans", "Error", err.Error())
+		return
+	}
+	invalidateGbans()
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("gbanimport-success", map[string]any{
+		"count": len(gbans),
+	}))
+}
+
+func sudoHandler(sudo bool) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		message := update.Message
+		if !isOwner(update) {
+			return
+		}
+		i18n := localization.Get(update)
+
+		userID, _, errMsg := moderation.ParseUserTarget(message)
+		if errMsg != "" {
+			utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n(errMsg))
+			return
+		}
+
+		changed, err := setSudoer(userID, sudo)
+		if err != nil {
+			slog.Error("Couldn't update sudoer",
+				"UserID", userID,
+				"Error", err.Error())
+			return
+		}
+
+		respKey := "sudo-removed"
+		switch {
+		case sudo && changed:
+			respKey = "sudo-added"
+		case sudo:
+			respKey = "sudo-already"
+		case !changed:
+			respKey = "sudo-not-found"
+		}
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n(respKey, map[string]any{
+			"userID": strconv.FormatInt(userID, 10),
+		}))
+	}
+}
+
+func sudoersHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	if !isOwner(update) {
+		return
+	}
+	i18n := localization.Get(update)
+
+	users, err := getSudoers()
+	if err != nil {
+		slog.Error("Couldn't get sudoers", "Error", err.Error())
+		return
+	}
+	if len(users) == 0 {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("sudoers-empty"))
+		return
+	}
+
+	text := i18n("sudoers-list")
+	for _, userID := range users {
+		text += fmt.Sprintf("\n- <a href='tg://user?id=%d'>%d</a>", userID, userID)
+	}
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, text)
+}
@@ -153,4 +153,18 @@ func getQueryForType(announceType, lang string) string {
 func Load(b *bot.Bot) {
 	b.RegisterHandler(bot.HandlerTypeMessageText, "announce", bot.MatchTypeCommand, announceHandler)
 	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "announce", bot.MatchTypePrefix, announceHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "gban", bot.MatchTypeCommand, gbanHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "ungban", bot.MatchTypeCommand, ungbanHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "gbanstat", bot.MatchTypeCommand, gbanStatHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "gbanexport", bot.MatchTypeCommand, gbanExportHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "gbanimport", bot.MatchTypeCommand, gbanImportHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "addsudo", bot.MatchTypeCommand, sudoHandler(true))
+	b.RegisterHandler(bot.HandlerTypeMessageText, "rmsudo", bot.MatchTypeCommand, sudoHandler(false))
+	b.RegisterHandler(bot.HandlerTypeMessageText, "sudoers", bot.MatchTypeCommand, sudoersHandler)
+
+	utils.RegisterChatSection("gbans", utils.ChatSection{
+		Export:   exportGbanSettings,
+		Import:   importGbanSettings,
+		Imported: invalidateGbanChat,
+	})
 }
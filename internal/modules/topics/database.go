@@ -0,0 +1,30 @@
+package topics
+
+import (
+	"database/sql"
+
+	"github.com/angelomds42/EleineBot/internal/database"
+)
+
+// getTopicMediasAuto returns whether medias are downloaded automatically in
+// a forum topic, invalid when the topic follows the group setting.
+func getTopicMediasAuto(chatID int64, threadID int) (sql.NullBool, error) {
+	var mediasAuto sql.NullBool
+	err := database.DB.QueryRow(
+		"SELECT mediasAuto FROM topicSettings WHERE chat_id = ? AND thread_id = ?;", chatID, threadID,
+	).Scan(&mediasAuto)
+	if err == sql.ErrNoRows {
+		return mediasAuto, nil
+	}
+	return mediasAuto, err
+}
+
+// setTopicMediasAuto overrides the automatic downloads of the group in a
+// forum topic, or stops overriding them when mediasAuto is invalid.
+func setTopicMediasAuto(chatID int64, threadID int, mediasAuto sql.NullBool) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO topicSettings (chat_id, thread_id, mediasAuto) VALUES (?, ?, ?)
+		ON CONFLICT(chat_id, thread_id) DO UPDATE SET mediasAuto = excluded.mediasAuto;
+	`, chatID, threadID, mediasAuto)
+	return err
+}
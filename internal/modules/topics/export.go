@@ -0,0 +1,56 @@
+package topics
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/angelomds42/EleineBot/internal/database"
+)
+
+// topicSection holds the settings a forum topic overrides.
+type topicSection struct {
+	ThreadID   int  `json:"thread_id"`
+	MediasAuto bool `json:"medias_auto"`
+}
+
+func exportTopics(chatID int64) (any, error) {
+	rows, err := database.DB.Query(
+		"SELECT thread_id, mediasAuto FROM topicSettings WHERE chat_id = ? AND mediasAuto IS NOT NULL ORDER BY thread_id;", chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	topics := []topicSection{}
+	for rows.Next() {
+		var topic topicSection
+		if err := rows.Scan(&topic.ThreadID, &topic.MediasAuto); err != nil {
+			return nil, err
+		}
+		topics = append(topics, topic)
+	}
+	return topics, rows.Err()
+}
+
+func importTopics(tx *sql.Tx, chatID int64, data json.RawMessage) error {
+	var topics []topicSection
+	if err := json.Unmarshal(data, &topics); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM topicSettings WHERE chat_id = ?;", chatID); err != nil {
+		return err
+	}
+	for _, topic := range topics {
+		if topic.ThreadID <= 0 {
+			continue
+		}
+		if _, err := tx.Exec(
+			"INSERT INTO topicSettings (chat_id, thread_id, mediasAuto) VALUES (?, ?, ?);",
+			chatID, topic.ThreadID, topic.MediasAuto,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
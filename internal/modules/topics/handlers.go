@@ -0,0 +1,248 @@
+package topics
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/localization"
+	"github.com/angelomds42/EleineBot/internal/modules/moderation"
+	"github.com/angelomds42/EleineBot/internal/utils"
+)
+
+// maxTopicName is the longest name Telegram accepts for a topic.
+const maxTopicName = 128
+
+// checkTopicsRights checks that message was sent in a forum where both its
+// sender and the bot can manage topics.
+func checkTopicsRights(ctx context.Context, b *bot.Bot, message *models.Message) bool {
+	if !message.Chat.IsForum {
+		i18n := localization.Get(&models.Update{Message: message})
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("topics-forum-only"))
+		return false
+	}
+	return moderation.CheckUserRight(ctx, b, message, moderation.RightManageTopics) &&
+		moderation.CheckBotRight(ctx, b, message, moderation.RightManageTopics)
+}
+
+// topicName returns the name given after the command, or "" when it's
+// missing or too long.
+func topicName(message *models.Message) string {
+	_, name, _ := strings.Cut(message.Text, " ")
+	name = strings.TrimSpace(name)
+	if utf8.RuneCountInString(name) > maxTopicName {
+		return ""
+	}
+	return name
+}
+
+func newTopicHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	if !checkTopicsRights(ctx, b, message) {
+		return
+	}
+
+	name := topicName(message)
+	if name == "" {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("newtopic-usage"))
+		return
+	}
+
+	topic, err := b.CreateForumTopic(ctx, &bot.CreateForumTopicParams{ChatID: message.Chat.ID, Name: name})
+	if err != nil {
+		slog.Error("Couldn't create topic",
+			"ChatID", message.Chat.ID,
+			"Error", err.Error())
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("topics-failed"))
+		return
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("newtopic-success", map[string]any{
+		"name": utils.EscapeHTML(topic.Name),
+	}))
+	utils.SendMessage(utils.WithThread(ctx, message.Chat.ID, topic.MessageThreadID), b,
+		message.Chat.ID, 0, i18n("newtopic-welcome", map[string]any{
+			"name": utils.EscapeHTML(topic.Name),
+		}))
+	moderation.SendLog(ctx, b, message.Chat, moderation.LogEntry{
+		Action:  "newtopic",
+		Actor:   message.From,
+		Details: utils.EscapeHTML(topic.Name),
+		Message: message,
+	})
+}
+
+// topicStateHandler closes or reopens the topic the command is sent in,
+// the General one included.
+func topicStateHandler(closed bool) bot.HandlerFunc {
+	action := "reopentopic"
+	if closed {
+		action = "closetopic"
+	}
+
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		message := update.Message
+		i18n := localization.Get(update)
+
+		if !checkTopicsRights(ctx, b, message) {
+			return
+		}
+
+		threadID := utils.MessageThread(message)
+		var err error
+		switch {
+		case closed && threadID == 0:
+			_, err = b.CloseGeneralForumTopic(ctx, &bot.CloseGeneralForumTopicParams{ChatID: message.Chat.ID})
+		case closed:
+			_, err = b.CloseForumTopic(ctx, &bot.CloseForumTopicParams{ChatID: message.Chat.ID, MessageThreadID: threadID})
+		case threadID == 0:
+			_, err = b.ReopenGeneralForumTopic(ctx, &bot.ReopenGeneralForumTopicParams{ChatID: message.Chat.ID})
+		default:
+			_, err = b.ReopenForumTopic(ctx, &bot.ReopenForumTopicParams{ChatID: message.Chat.ID, MessageThreadID: threadID})
+		}
+		if err != nil {
+			slog.Error("Couldn't change topic state",
+				"ChatID", message.Chat.ID,
+				"ThreadID", threadID,
+				"Closed", closed,
+				"Error", err.Error())
+			utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("topics-failed"))
+			return
+		}
+
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n(action+"-success"))
+		moderation.SendLog(ctx, b, message.Chat, moderation.LogEntry{
+			Action:  action,
+			Actor:   message.From,
+			Message: message,
+		})
+	}
+}
+
+func renameTopicHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	if !checkTopicsRights(ctx, b, message) {
+		return
+	}
+
+	name := topicName(message)
+	if name == "" {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("renametopic-usage"))
+		return
+	}
+
+	threadID := utils.MessageThread(message)
+	var err error
+	if threadID == 0 {
+		_, err = b.EditGeneralForumTopic(ctx, &bot.EditGeneralForumTopicParams{ChatID: message.Chat.ID, Name: name})
+	} else {
+		_, err = b.EditForumTopic(ctx, &bot.EditForumTopicParams{ChatID: message.Chat.ID, MessageThreadID: threadID, Name: name})
+	}
+	if err != nil {
+		slog.Error("Couldn't rename topic",
+			"ChatID", message.Chat.ID,
+			"ThreadID", threadID,
+			"Error", err.Error())
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("topics-failed"))
+		return
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("renametopic-success", map[string]any{
+		"name": utils.EscapeHTML(name),
+	}))
+	moderation.SendLog(ctx, b, message.Chat, moderation.LogEntry{
+		Action:  "renametopic",
+		Actor:   message.From,
+		Details: utils.EscapeHTML(name),
+		Message: message,
+	})
+}
+
+// topicMediasHandler chooses whether links sent in the current topic are
+// downloaded automatically, regardless of the group setting.
+func topicMediasHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	i18n := localization.Get(update)
+
+	threadID := utils.MessageThread(message)
+	if threadID == 0 {
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("topic-only"))
+		return
+	}
+	if !moderation.CheckUserAdmin(ctx, b, message) {
+		return
+	}
+
+	fields := strings.Fields(message.Text)
+	if len(fields) < 2 {
+		mediasAuto, err := getTopicMediasAuto(message.Chat.ID, threadID)
+		if err != nil {
+			slog.Error("Couldn't get topic settings",
+				"ChatID", message.Chat.ID,
+				"ThreadID", threadID,
+				"Error", err.Error())
+			return
+		}
+		utils.SendMessage(ctx, b, message.Chat.ID, message.ID, topicMediasStatus(i18n, mediasAuto))
+		return
+	}
+
+	var mediasAuto sql.NullBool
+	if strings.ToLower(fields[1]) != "default" {
+		enabled, ok := utils.ParseSwitch(fields[1])
+		if !ok {
+			utils.SendMessage(ctx, b, message.Chat.ID, message.ID, i18n("topicmedias-usage"))
+			return
+		}
+		mediasAuto = sql.NullBool{Bool: enabled, Valid: true}
+	}
+
+	if err := setTopicMediasAuto(message.Chat.ID, threadID, mediasAuto); err != nil {
+		slog.Error("Couldn't update topic settings",
+			"ChatID", message.Chat.ID,
+			"ThreadID", threadID,
+			"Error", err.Error())
+		return
+	}
+
+	utils.SendMessage(ctx, b, message.Chat.ID, message.ID, topicMediasStatus(i18n, mediasAuto))
+	moderation.SendLog(ctx, b, message.Chat, moderation.LogEntry{
+		Action:  "config",
+		Actor:   message.From,
+		Details: i18n("topicmedias-log", map[string]any{"state": topicMediasState(mediasAuto)}),
+		Message: message,
+	})
+}
+
+// topicMediasState is the selector the locales use for mediasAuto.
+func topicMediasState(mediasAuto sql.NullBool) string {
+	if !mediasAuto.Valid {
+		return "default"
+	}
+	return strconv.FormatBool(mediasAuto.Bool)
+}
+
+func topicMediasStatus(i18n func(string, ...map[string]any) string, mediasAuto sql.NullBool) string {
+	return i18n("topicmedias-status", map[string]any{"state": topicMediasState(mediasAuto)})
+}
+
+func Load(b *bot.Bot) {
+	b.RegisterHandler(bot.HandlerTypeMessageText, "newtopic", bot.MatchTypeCommand, newTopicHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "closetopic", bot.MatchTypeCommand, topicStateHandler(true))
+	b.RegisterHandler(bot.HandlerTypeMessageText, "reopentopic", bot.MatchTypeCommand, topicStateHandler(false))
+	b.RegisterHandler(bot.HandlerTypeMessageText, "renametopic", bot.MatchTypeCommand, renameTopicHandler)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "topicmedias", bot.MatchTypeCommand, topicMediasHandler)
+
+	utils.RegisterChatSection("topics", utils.ChatSection{Export: exportTopics, Import: importTopics})
+	utils.SaveHelp("topics")
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// adminsCacheTTL bounds how long a promotion or demotion made outside the
+// bot takes to be noticed.
+const adminsCacheTTL = 10 * time.Minute
+
+type cachedAdmins struct {
+	ids     map[int64]bool
+	expires time.Time
+}
+
+// adminsCache holds the admins of each chat, since checks made for every
+// message can't afford a request each.
+var (
+	adminsCache      = make(map[int64]cachedAdmins)
+	adminsCacheMutex sync.RWMutex
+)
+
+// IsChatAdmin reports whether userID is an admin of chatID, from a cache
+// refreshed every few minutes.
+func IsChatAdmin(ctx context.Context, b *bot.Bot, chatID, userID int64) bool {
+	adminsCacheMutex.RLock()
+	admins, ok := adminsCache[chatID]
+	adminsCacheMutex.RUnlock()
+	if ok && time.Now().Before(admins.expires) {
+		return admins.ids[userID]
+	}
+
+	members, err := b.GetChatAdministrators(ctx, &bot.GetChatAdministratorsParams{ChatID: chatID})
+	if err != nil {
+		slog.Error("Couldn't get chat administrators",
+			"ChatID", chatID,
+			"Error", err.Error())
+		return false
+	}
+
+	admins = cachedAdmins{ids: make(map[int64]bool, len(members)), expires: time.Now().Add(adminsCacheTTL)}
+	for _, member := range members {
+		switch member.Type {
+		case models.ChatMemberTypeOwner:
+			admins.ids[member.Owner.User.ID] = true
+		case models.ChatMemberTypeAdministrator:
+			admins.ids[member.Administrator.User.ID] = true
+		}
+	}
+
+	adminsCacheMutex.Lock()
+	adminsCache[chatID] = admins
+	adminsCacheMutex.Unlock()
+	return admins.ids[userID]
+}
+
+// InvalidateChatAdmins drops the cached admins of chatID, after the bot
+// promotes or demotes someone there.
+func InvalidateChatAdmins(chatID int64) {
+	adminsCacheMutex.Lock()
+	delete(adminsCache, chatID)
+	adminsCacheMutex.Unlock()
+}
+
+// IsAnonymousAdmin reports whether message was sent by an admin who stays
+// anonymous, on behalf of the group itself.
+func IsAnonymousAdmin(message *models.Message) bool {
+	return message.SenderChat != nil && message.SenderChat.ID == message.Chat.ID
+}
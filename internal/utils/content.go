@@ -0,0 +1,238 @@
+package utils
+
+import (
+	"context"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// Content is a message saved by the bot to be sent again later, such as a
+// welcome message. Text is HTML and may contain button markup.
+type Content struct {
+	Text      string `json:"text,omitempty"`
+	MediaType string `json:"media_type,omitempty"`
+	FileID    string `json:"file_id,omitempty"`
+}
+
+var buttonRegex = regexp.MustCompile(`\[([^\[\]]+)\]\(buttonurl:(?://)?([^\s()]+?)(:same)?\)`)
+
+// ExtractContent builds the content of a saving command such as /setwelcome,
+// taken from the replied message or from the text following the command and
+// its first skip arguments.
+func ExtractContent(message *models.Message, skip int) (Content, bool) {
+	var content Content
+	text, entities := commandArgs(message.Text, message.Entities, skip+1)
+
+	if reply := message.ReplyToMessage; reply != nil {
+		content.MediaType, content.FileID = messageMedia(reply)
+		switch {
+		case reply.Text != "":
+			text, entities = reply.Text, reply.Entities
+		case reply.Caption != "" && text == "":
+			text, entities = reply.Caption, reply.CaptionEntities
+		}
+	}
+
+	content.Text = strings.TrimSpace(FormatText(text, entities))
+	return content, content.Text != "" || content.FileID != ""
+}
+
+// commandArgs strips the first n arguments from text, as split by SplitArgs,
+// shifting the entities so they keep pointing to the same characters.
+func commandArgs(text string, entities []models.MessageEntity, n int) (string, []models.MessageEntity) {
+	var (
+		quoted bool
+		hasArg bool
+		start  = len(text)
+	)
+	for i, r := range text {
+		if n == 0 && !unicode.IsSpace(r) {
+			start = i
+			break
+		}
+		switch {
+		case r == '"':
+			quoted = !quoted
+			hasArg = true
+		case !quoted && unicode.IsSpace(r):
+			if hasArg {
+				n--
+				hasArg = false
+			}
+		default:
+			hasArg = true
+		}
+	}
+
+	offset := len(utf16.Encode([]rune(text[:start])))
+	var shifted []models.MessageEntity
+	for _, entity := range entities {
+		if entity.Offset < offset {
+			continue
+		}
+		entity.Offset -= offset
+		shifted = append(shifted, entity)
+	}
+	return text[start:], shifted
+}
+
+func messageMedia(message *models.Message) (string, string) {
+	switch {
+	case len(message.Photo) > 0:
+		return "photo", message.Photo[len(message.Photo)-1].FileID
+	case message.Animation != nil:
+		return "animation", message.Animation.FileID
+	case message.Sticker != nil:
+		return "sticker", message.Sticker.FileID
+	case message.Video != nil:
+		return "video", message.Video.FileID
+	case message.Document != nil:
+		return "document", message.Document.FileID
+	case message.Audio != nil:
+		return "audio", message.Audio.FileID
+	case message.Voice != nil:
+		return "voice", message.Voice.FileID
+	}
+	return "", ""
+}
+
+// ParseButtons removes the [text](buttonurl://url) markup from text and
+// returns it as keyboard rows. Buttons ending with :same share the previous row.
+func ParseButtons(text string) (string, [][]models.InlineKeyboardButton) {
+	var rows [][]models.InlineKeyboardButton
+	for _, match := range buttonRegex.FindAllStringSubmatch(text, -1) {
+		url := html.UnescapeString(match[2])
+		if !strings.Contains(url, "://") {
+			url = "https://" + url
+		}
+		button := models.InlineKeyboardButton{
+			Text: html.UnescapeString(match[1]),
+			URL:  url,
+		}
+		if match[3] != "" && len(rows) > 0 {
+			rows[len(rows)-1] = append(rows[len(rows)-1], button)
+			continue
+		}
+		rows = append(rows, []models.InlineKeyboardButton{button})
+	}
+	return strings.TrimSpace(buttonRegex.ReplaceAllString(text, "")), rows
+}
+
+// UserPlaceholders returns the values of the placeholders describing user
+// and chat, already escaped to be used in HTML.
+func UserPlaceholders(user *models.User, chat models.Chat) map[string]string {
+	mention := MentionUser(user)
+	username := mention
+	if user.Username != "" {
+		username = "@" + user.Username
+	}
+	return map[string]string{
+		"first":    EscapeHTML(user.FirstName),
+		"last":     EscapeHTML(user.LastName),
+		"fullname": EscapeHTML(strings.TrimSpace(user.FirstName + " " + user.LastName)),
+		"username": username,
+		"mention":  mention,
+		"id":       strconv.FormatInt(user.ID, 10),
+		"chatname": EscapeHTML(chat.Title),
+	}
+}
+
+// FormatPlaceholders replaces every {key} of text with its value.
+func FormatPlaceholders(text string, values map[string]string) string {
+	pairs := make([]string, 0, len(values)*2)
+	for key, value := range values {
+		pairs = append(pairs, "{"+key+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// SendContent sends content to chatID using the method matching its media.
+func SendContent(
+	ctx context.Context,
+	b *bot.Bot,
+	chatID int64,
+	replyTo int,
+	content Content,
+	markup *models.InlineKeyboardMarkup,
+) (*models.Message, error) {
+	msg, err := sendContent(ctx, b, chatID, replyTo, content, markup)
+	if err == nil && replyTo != 0 {
+		NotifyReply(ctx, b, msg)
+	}
+	return msg, err
+}
+
+func sendContent(
+	ctx context.Context,
+	b *bot.Bot,
+	chatID int64,
+	replyTo int,
+	content Content,
+	markup *models.InlineKeyboardMarkup,
+) (*models.Message, error) {
+	var replyParameters *models.ReplyParameters
+	if replyTo != 0 {
+		replyParameters = &models.ReplyParameters{MessageID: replyTo}
+	}
+	var replyMarkup models.ReplyMarkup
+	if markup != nil {
+		replyMarkup = markup
+	}
+	file := &models.InputFileString{Data: content.FileID}
+	threadID := ChatThread(ctx, chatID)
+
+	switch content.MediaType {
+	case "photo":
+		return b.SendPhoto(ctx, &bot.SendPhotoParams{
+			ChatID: chatID, MessageThreadID: threadID, Photo: file, Caption: content.Text, ParseMode: models.ParseModeHTML,
+			ReplyParameters: replyParameters, ReplyMarkup: replyMarkup,
+		})
+	case "animation":
+		return b.SendAnimation(ctx, &bot.SendAnimationParams{
+			ChatID: chatID, MessageThreadID: threadID, Animation: file, Caption: content.Text, ParseMode: models.ParseModeHTML,
+			ReplyParameters: replyParameters, ReplyMarkup: replyMarkup,
+		})
+	case "sticker":
+		return b.SendSticker(ctx, &bot.SendStickerParams{
+			ChatID: chatID, MessageThreadID: threadID, Sticker: file,
+			ReplyParameters: replyParameters, ReplyMarkup: replyMarkup,
+		})
+	case "video":
+		return b.SendVideo(ctx, &bot.SendVideoParams{
+			ChatID: chatID, MessageThreadID: threadID, Video: file, Caption: content.Text, ParseMode: models.ParseModeHTML,
+			ReplyParameters: replyParameters, ReplyMarkup: replyMarkup,
+		})
+	case "document":
+		return b.SendDocument(ctx, &bot.SendDocumentParams{
+			ChatID: chatID, MessageThreadID: threadID, Document: file, Caption: content.Text, ParseMode: models.ParseModeHTML,
+			ReplyParameters: replyParameters, ReplyMarkup: replyMarkup,
+		})
+	case "audio":
+		return b.SendAudio(ctx, &bot.SendAudioParams{
+			ChatID: chatID, MessageThreadID: threadID, Audio: file, Caption: content.Text, ParseMode: models.ParseModeHTML,
+			ReplyParameters: replyParameters, ReplyMarkup: replyMarkup,
+		})
+	case "voice":
+		return b.SendVoice(ctx, &bot.SendVoiceParams{
+			ChatID: chatID, MessageThreadID: threadID, Voice: file, Caption: content.Text, ParseMode: models.ParseModeHTML,
+			ReplyParameters: replyParameters, ReplyMarkup: replyMarkup,
+		})
+	}
+
+	return b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:             chatID,
+		MessageThreadID:    threadID,
+		Text:               content.Text,
+		ParseMode:          models.ParseModeHTML,
+		LinkPreviewOptions: &models.LinkPreviewOptions{IsDisabled: bot.True()},
+		ReplyParameters:    replyParameters,
+		ReplyMarkup:        replyMarkup,
+	})
+}
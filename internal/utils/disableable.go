@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"database/sql"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/angelomds42/EleineBot/internal/database"
+)
+
+var (
+	// disableableModules maps each module to the commands admins can disable.
+	disableableModules = make(map[string][]string)
+	// commandAliases maps each alias to the command it shares its state with.
+	commandAliases = make(map[string]string)
+)
+
+// RegisterDisableable lets admins disable commands of module, one by one or
+// together with the rest of the module.
+func RegisterDisableable(module string, commands ...string) {
+	disableableModules[module] = append(disableableModules[module], commands...)
+}
+
+// RegisterAliases makes aliases be disabled and enabled along with command.
+func RegisterAliases(command string, aliases ...string) {
+	for _, alias := range aliases {
+		commandAliases[alias] = command
+	}
+}
+
+// DisableableModules returns the modules with disableable commands, sorted.
+func DisableableModules() []string {
+	modules := make([]string, 0, len(disableableModules))
+	for module := range disableableModules {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	return modules
+}
+
+// ModuleCommands returns the disableable commands of module, or nil if it
+// has none.
+func ModuleCommands(module string) []string {
+	return slices.Clone(disableableModules[strings.ToLower(module)])
+}
+
+// AllDisableableCommands returns every disableable command, without aliases.
+func AllDisableableCommands() []string {
+	var commands []string
+	for _, module := range DisableableModules() {
+		commands = append(commands, disableableModules[module]...)
+	}
+	return commands
+}
+
+// CommandAliases returns the aliases of command, sorted.
+func CommandAliases(command string) []string {
+	var aliases []string
+	for alias, target := range commandAliases {
+		if target == command {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+func canonicalCommand(name string) string {
+	if command, ok := commandAliases[name]; ok {
+		return command
+	}
+	return name
+}
+
+// ResolveDisableable returns the disableable command name refers to, by its
+// name or one of its aliases, or "" if there's none.
+func ResolveDisableable(name string) string {
+	command := canonicalCommand(strings.ToLower(strings.TrimPrefix(name, "/")))
+	for _, commands := range disableableModules {
+		if slices.Contains(commands, command) {
+			return command
+		}
+	}
+	return ""
+}
+
+// commandFromText returns the command a message starts with, without the
+// slash or the bot username, or "" if it doesn't start with one.
+func commandFromText(text string) string {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return ""
+	}
+	command, _, _ := strings.Cut(strings.TrimPrefix(fields[0], "/"), "@")
+	return strings.ToLower(command)
+}
+
+// GetDisabledDelete reports whether messages using a disabled command are
+// deleted in chatID.
+func GetDisabledDelete(chatID int64) bool {
+	var enabled bool
+	err := database.DB.QueryRow("SELECT delete_commands FROM disabledSettings WHERE chat_id = ?;", chatID).Scan(&enabled)
+	if err != nil && err != sql.ErrNoRows {
+		slog.Error("Couldn't get disabled commands settings",
+			"ChatID", chatID,
+			"Error", err.Error())
+	}
+	return enabled
+}
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+
+	"github.com/angelomds42/EleineBot/internal/database"
+)
+
+// ChatExportVersion is the version of the documents written by /export.
+// Older versions must keep importing: sections only gain fields, and a
+// field missing from an old document leaves its default.
+const ChatExportVersion = 1
+
+var ErrUnsupportedExport = errors.New("unsupported export version")
+
+// ChatExport is the document written by /export and read by /import.
+type ChatExport struct {
+	Version  int                        `json:"version"`
+	ChatID   int64                      `json:"chat_id"`
+	Date     int64                      `json:"date"`
+	Sections map[string]json.RawMessage `json:"sections"`
+}
+
+// ChatSection moves the settings a module keeps for a chat in and out of
+// /export documents.
+type ChatSection struct {
+	// Export returns the settings of the chat, to be encoded as JSON.
+	Export func(chatID int64) (any, error)
+	// Import replaces the settings of the chat with the exported ones.
+	Import func(tx *sql.Tx, chatID int64, data json.RawMessage) error
+	// Imported, if set, is called once the import is committed, to drop
+	// what the module caches about the chat.
+	Imported func(chatID int64)
+}
+
+var chatSections = make(map[string]ChatSection)
+
+// RegisterChatSection adds a section named name to the /export documents.
+func RegisterChatSection(name string, section ChatSection) {
+	chatSections[name] = section
+}
+
+// ExportChat collects the settings every module keeps for chatID.
+func ExportChat(chatID int64) (ChatExport, error) {
+	export := ChatExport{
+		Version:  ChatExportVersion,
+		ChatID:   chatID,
+		Date:     time.Now().Unix(),
+		Sections: make(map[string]json.RawMessage, len(chatSections)),
+	}
+	for name, section := range chatSections {
+		settings, err := section.Export(chatID)
+		if err != nil {
+			return export, fmt.Errorf("%s: %w", name, err)
+		}
+		data, err := json.Marshal(settings)
+		if err != nil {
+			return export, fmt.Errorf("%s: %w", name, err)
+		}
+		export.Sections[name] = data
+	}
+	return export, nil
+}
+
+// ImportChat applies the sections of export to chatID, all or none of them,
+// and returns the names of those it applied, sorted. Sections unknown to
+// this version of the bot are skipped.
+func ImportChat(chatID int64, export ChatExport) ([]string, error) {
+	if export.Version < 1 || export.Version > ChatExportVersion {
+		return nil, ErrUnsupportedExport
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var imported []string
+	for name, data := range export.Sections {
+		section, ok := chatSections[name]
+		if !ok {
+			continue
+		}
+		if err := section.Import(tx, chatID, data); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		imported = append(imported, name)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	for _, name := range imported {
+		if section := chatSections[name]; section.Imported != nil {
+			section.Imported(chatID)
+		}
+	}
+	sort.Strings(imported)
+	return imported, nil
+}
+
+// DownloadDocument returns the contents of a document sent to the bot.
+func DownloadDocument(ctx context.Context, b *bot.Bot, document *models.Document) ([]byte, error) {
+	file, err := b.GetFile(ctx, &bot.GetFileParams{FileID: document.FileID})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.Get(b.FileDownloadLink(file))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
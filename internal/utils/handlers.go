@@ -2,6 +2,7 @@ package utils
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"log/slog"
 	"strconv"
@@ -14,32 +15,90 @@ import (
 	"github.com/angelomds42/EleineBot/internal/database"
 )
 
-var DisableableCommands []string
+// StartPayloadHandler handles a /start deep link, receiving what follows
+// the payload name, e.g. "-100123" for start=rules_-100123.
+type StartPayloadHandler func(ctx context.Context, b *bot.Bot, update *models.Update, args string)
 
-func CheckDisabledCommand(command string, chatID int64) bool {
-	var exists bool
-	query := "SELECT EXISTS(SELECT 1 FROM commandsDisabled WHERE command = ? AND chat_id = ? LIMIT 1);"
-	err := database.DB.QueryRow(query, command, chatID).Scan(&exists)
-	if err != nil {
+var startPayloadHandlers = make(map[string]StartPayloadHandler)
+
+// RegisterStartPayload makes /start handle the payload name, alone or
+// followed by an underscore and its arguments.
+func RegisterStartPayload(name string, handler StartPayloadHandler) {
+	startPayloadHandlers[name] = handler
+}
+
+func GetStartPayload(payload string) (StartPayloadHandler, string, bool) {
+	name, args, _ := strings.Cut(payload, "_")
+	handler, ok := startPayloadHandlers[name]
+	return handler, args, ok
+}
+
+// CommandState is whether a command can be used in a chat.
+type CommandState int
+
+const (
+	CommandEnabled CommandState = iota
+	CommandAdminsOnly
+	CommandDisabled
+)
+
+// GetCommandState returns the state of command, or of the command it's an
+// alias of, in the forum topic threadID of chatID, where it's disabled when
+// either the topic or the whole chat disables it.
+func GetCommandState(command string, chatID int64, threadID int) CommandState {
+	var adminsOnly bool
+	query := `
+		SELECT admins_only FROM commandsDisabled WHERE command IN (?1, ?2) AND chat_id = ?3
+		UNION ALL
+		SELECT admins_only FROM topicCommandsDisabled WHERE command IN (?1, ?2) AND chat_id = ?3 AND thread_id = ?4
+		ORDER BY admins_only LIMIT 1;
+	`
+	err := database.DB.QueryRow(query, command, canonicalCommand(command), chatID, threadID).Scan(&adminsOnly)
+	switch {
+	case err == sql.ErrNoRows:
+		return CommandEnabled
+	case err != nil:
 		fmt.Printf("Error checking command: %v\n", err)
-		return false
+		return CommandEnabled
+	case adminsOnly:
+		return CommandAdminsOnly
 	}
-	return exists
+	return CommandDisabled
+}
+
+// CheckDisabledCommand reports whether command is disabled for everyone in
+// the forum topic threadID of chatID.
+func CheckDisabledCommand(command string, chatID int64, threadID int) bool {
+	return GetCommandState(command, chatID, threadID) == CommandDisabled
 }
 
 func CheckDisabledMiddleware(next bot.HandlerFunc) bot.HandlerFunc {
 	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
-		if update.Message == nil || len(strings.Fields(update.Message.Text)) < 1 || update.Message.Chat.Type == models.ChatTypePrivate {
+		if update.Message == nil || update.Message.Chat.Type == models.ChatTypePrivate {
 			next(ctx, b, update)
 			return
 		}
 
-		if len(strings.Fields(update.Message.Text)) < 1 {
+		message := update.Message
+		command := commandFromText(message.Text)
+		if command == "" {
+			next(ctx, b, update)
 			return
 		}
 
-		command := strings.Replace(strings.Fields(update.Message.Text)[0], "/", "", 1)
-		if CheckDisabledCommand(command, update.Message.Chat.ID) {
+		state := GetCommandState(command, message.Chat.ID, MessageThread(message))
+		if state == CommandAdminsOnly && (IsAnonymousAdmin(message) ||
+			message.From != nil && IsChatAdmin(ctx, b, message.Chat.ID, message.From.ID)) {
+			state = CommandEnabled
+		}
+
+		if state != CommandEnabled {
+			if GetDisabledDelete(message.Chat.ID) {
+				b.DeleteMessage(ctx, &bot.DeleteMessageParams{
+					ChatID:    message.Chat.ID,
+					MessageID: message.ID,
+				})
+			}
 			return
 		}
 
@@ -72,6 +131,44 @@ func ParseCustomDuration(s string) (time.Duration, error) {
 	}
 }
 
+// ReplyHandler is called with a reply the bot has just sent.
+type ReplyHandler func(ctx context.Context, b *bot.Bot, reply *models.Message)
+
+var replyHandlers []ReplyHandler
+
+// OnReply makes the send helpers call handler with every reply they send.
+func OnReply(handler ReplyHandler) {
+	replyHandlers = append(replyHandlers, handler)
+}
+
+// NotifyReply calls the OnReply handlers with reply, for the replies sent
+// without the helpers.
+func NotifyReply(ctx context.Context, b *bot.Bot, reply *models.Message) {
+	if reply == nil {
+		return
+	}
+	for _, handler := range replyHandlers {
+		handler(ctx, b, reply)
+	}
+}
+
+type handledKey struct{}
+
+// TrackHandled returns a context in which DefaultHandler records that no
+// handler matched the update, and a function telling whether one did.
+func TrackHandled(ctx context.Context) (context.Context, func() bool) {
+	handled := true
+	return context.WithValue(ctx, handledKey{}, &handled), func() bool { return handled }
+}
+
+// DefaultHandler handles the updates no other handler matches, such as the
+// commands of other bots.
+func DefaultHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if handled, ok := ctx.Value(handledKey{}).(*bool); ok {
+		*handled = false
+	}
+}
+
 func SendMessage(
 	ctx context.Context,
 	b *bot.Bot,
@@ -85,15 +182,23 @@ func SendMessage(
 		Text:               text,
 		ParseMode:          models.ParseModeHTML,
 		LinkPreviewOptions: &models.LinkPreviewOptions{IsDisabled: bot.True()},
+		MessageThreadID:    ChatThread(ctx, chatID),
 	}
 	if replyTo != 0 {
-		params.ReplyParameters = &models.ReplyParameters{MessageID: replyTo}
+		// The command may be gone by the time it's answered, deleted by
+		// /cleancommands or while waiting for an anonymous admin.
+		params.ReplyParameters = &models.ReplyParameters{MessageID: replyTo, AllowSendingWithoutReply: true}
 	}
 	for _, opt := range opts {
 		opt(params)
 	}
-	if _, err := b.SendMessage(ctx, params); err != nil {
+	msg, err := b.SendMessage(ctx, params)
+	if err != nil {
 		slog.Error("utils: SendMessage failed", "chatID", chatID, "error", err)
+		return
+	}
+	if params.ReplyParameters != nil {
+		NotifyReply(ctx, b, msg)
 	}
 }
 
@@ -110,9 +215,10 @@ func SendMessageWithResult(
 		Text:               text,
 		ParseMode:          models.ParseModeHTML,
 		LinkPreviewOptions: &models.LinkPreviewOptions{IsDisabled: bot.True()},
+		MessageThreadID:    ChatThread(ctx, chatID),
 	}
 	if replyTo != 0 {
-		params.ReplyParameters = &models.ReplyParameters{MessageID: replyTo}
+		params.ReplyParameters = &models.ReplyParameters{MessageID: replyTo, AllowSendingWithoutReply: true}
 	}
 	for _, opt := range opts {
 		opt(params)
@@ -120,6 +226,10 @@ func SendMessageWithResult(
 	msg, err := b.SendMessage(ctx, params)
 	if err != nil {
 		slog.Error("utils: SendMessageWithResult failed", "chatID", chatID, "error", err)
+		return msg, err
+	}
+	if params.ReplyParameters != nil {
+		NotifyReply(ctx, b, msg)
 	}
 	return msg, err
 }
@@ -173,7 +283,12 @@ func SendAudio(
 	audio models.InputFile,
 	opts ...func(*bot.SendAudioParams),
 ) (*models.Message, error) {
-	params := &bot.SendAudioParams{ChatID: chatID, Audio: audio, ParseMode: models.ParseModeHTML}
+	params := &bot.SendAudioParams{
+		ChatID:          chatID,
+		MessageThreadID: ChatThread(ctx, chatID),
+		Audio:           audio,
+		ParseMode:       models.ParseModeHTML,
+	}
 	if replyTo != 0 {
 		params.ReplyParameters = &models.ReplyParameters{MessageID: replyTo}
 	}
@@ -183,6 +298,10 @@ func SendAudio(
 	msg, err := b.SendAudio(ctx, params)
 	if err != nil {
 		slog.Error("utils: SendAudio failed", "chatID", chatID, "error", err)
+		return msg, err
+	}
+	if params.ReplyParameters != nil {
+		NotifyReply(ctx, b, msg)
 	}
 	return msg, err
 }
@@ -196,7 +315,14 @@ func SendVideo(
 	width, height int,
 	opts ...func(*bot.SendVideoParams),
 ) (*models.Message, error) {
-	params := &bot.SendVideoParams{ChatID: chatID, Video: video, Width: width, Height: height, ParseMode: models.ParseModeHTML}
+	params := &bot.SendVideoParams{
+		ChatID:          chatID,
+		MessageThreadID: ChatThread(ctx, chatID),
+		Video:           video,
+		Width:           width,
+		Height:          height,
+		ParseMode:       models.ParseModeHTML,
+	}
 	if replyTo != 0 {
 		params.ReplyParameters = &models.ReplyParameters{MessageID: replyTo}
 	}
@@ -206,6 +332,10 @@ func SendVideo(
 	msg, err := b.SendVideo(ctx, params)
 	if err != nil {
 		slog.Error("utils: SendVideo failed", "chatID", chatID, "error", err)
+		return msg, err
+	}
+	if params.ReplyParameters != nil {
+		NotifyReply(ctx, b, msg)
 	}
 	return msg, err
 }
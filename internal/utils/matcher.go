@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	boundaryStart = `(?:^|[^\p{L}\p{N}_])`
+	boundaryEnd   = `(?:$|[^\p{L}\p{N}_])`
+)
+
+// WordExpression matches expression only when it isn't part of a longer word.
+func WordExpression(expression string) string {
+	return boundaryStart + expression + boundaryEnd
+}
+
+// PrefixExpression matches expression only at the start of the text, as a
+// whole word.
+func PrefixExpression(expression string) string {
+	return `^\s*` + expression + boundaryEnd
+}
+
+// Matcher joins the triggers of a chat into a single case-insensitive
+// regular expression, with one named group per item so the matching one can
+// be found.
+type Matcher[T any] struct {
+	re    *regexp.Regexp
+	items []T
+}
+
+// CompileMatcher builds a Matcher for items, using expression to get the
+// regular expression of each.
+func CompileMatcher[T any](items []T, expression func(T) string) (*Matcher[T], error) {
+	if len(items) == 0 {
+		return &Matcher[T]{}, nil
+	}
+
+	expressions := make([]string, 0, len(items))
+	for i, item := range items {
+		expressions = append(expressions, fmt.Sprintf("(?P<match%d>%s)", i, expression(item)))
+	}
+
+	re, err := regexp.Compile("(?i)" + strings.Join(expressions, "|"))
+	if err != nil {
+		return nil, err
+	}
+	return &Matcher[T]{re: re, items: items}, nil
+}
+
+// Match returns the first item whose trigger is found in text, or nil.
+func (m *Matcher[T]) Match(text string) *T {
+	if m.re == nil || text == "" {
+		return nil
+	}
+
+	indexes := m.re.FindStringSubmatchIndex(text)
+	if indexes == nil {
+		return nil
+	}
+
+	for i, name := range m.re.SubexpNames() {
+		if !strings.HasPrefix(name, "match") || indexes[2*i] < 0 {
+			continue
+		}
+		var itemIndex int
+		if _, err := fmt.Sscanf(name, "match%d", &itemIndex); err == nil && itemIndex < len(m.items) {
+			return &m.items[itemIndex]
+		}
+	}
+	return nil
+}
+
+// MatcherCache keeps the compiled Matcher of each chat until it's
+// invalidated.
+type MatcherCache[T any] struct {
+	load       func(chatID int64) ([]T, error)
+	expression func(T) string
+
+	cache map[int64]*Matcher[T]
+	mutex sync.RWMutex
+}
+
+// NewMatcherCache returns a MatcherCache that loads the items of a chat with
+// load.
+func NewMatcherCache[T any](load func(chatID int64) ([]T, error), expression func(T) string) *MatcherCache[T] {
+	return &MatcherCache[T]{
+		load:       load,
+		expression: expression,
+		cache:      make(map[int64]*Matcher[T]),
+	}
+}
+
+func (c *MatcherCache[T]) Get(chatID int64) (*Matcher[T], error) {
+	c.mutex.RLock()
+	matcher, ok := c.cache[chatID]
+	c.mutex.RUnlock()
+	if ok {
+		return matcher, nil
+	}
+
+	items, err := c.load(chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	matcher, err = CompileMatcher(items, c.expression)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	c.cache[chatID] = matcher
+	c.mutex.Unlock()
+	return matcher, nil
+}
+
+func (c *MatcherCache[T]) Invalidate(chatID int64) {
+	c.mutex.Lock()
+	delete(c.cache, chatID)
+	c.mutex.Unlock()
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/go-telegram/bot"
+)
+
+// Every calls task every interval for as long as the bot runs.
+func Every(interval time.Duration, task func(now time.Time)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for now := range ticker.C {
+			task(now)
+		}
+	}()
+}
+
+// Schedule checks every interval for the items due, as returned by due, and
+// calls act with each of them. Modules keep their deadlines in the database
+// and read them back in due, so they are honored across restarts.
+func Schedule[T any](
+	b *bot.Bot,
+	name string,
+	interval time.Duration,
+	due func(now time.Time) ([]T, error),
+	act func(ctx context.Context, b *bot.Bot, item T),
+) {
+	Every(interval, func(now time.Time) {
+		items, err := due(now)
+		if err != nil {
+			slog.Error("Couldn't get scheduled items",
+				"Task", name,
+				"Error", err.Error())
+			return
+		}
+
+		ctx := context.Background()
+		for _, item := range items {
+			act(ctx, b, item)
+		}
+	})
+}
@@ -3,34 +3,95 @@ package utils
 import (
 	"fmt"
 	"math/rand"
+	"regexp"
+	"strconv"
 	"strings"
+	"unicode"
 	"unicode/utf16"
 
 	"github.com/go-telegram/bot/models"
 )
 
+// MediaLinkRegex matches the links of the sites medias are downloaded from,
+// with or without their scheme.
+var MediaLinkRegex = regexp.MustCompile(`(?:http(?:s)?://)?(?:m|vm|vt|www|mobile)?(?:.)?(?:(?:instagram|twitter|x|tiktok|reddit|bsky|threads|xiaohongshu|xhslink)\.(?:com|net|app)|youtube\.com/shorts)/(?:\S*)`)
+
+// MentionUser links to user by their first name, in HTML.
+func MentionUser(user *models.User) string {
+	return fmt.Sprintf("<a href='tg://user?id=%d'>%s</a>", user.ID, EscapeHTML(user.FirstName))
+}
+
+// FormatText converts a Telegram text and its entities into HTML, escaping
+// the text itself so it can be sent back with ParseModeHTML.
 func FormatText(text string, entities []models.MessageEntity) string {
 	textRunes := utf16.Encode([]rune(text))
+	opening := make(map[int][]string)
+	closing := make(map[int][]string)
 
-	for i := len(entities) - 1; i >= 0; i-- {
-		entity := entities[i]
-		switch entity.Type {
-		case "bold":
-			textRunes = append(textRunes[:entity.Offset+entity.Length], append(utf16.Encode([]rune("</b>")), textRunes[entity.Offset+entity.Length:]...)...)
-			textRunes = append(textRunes[:entity.Offset], append(utf16.Encode([]rune("<b>")), textRunes[entity.Offset:]...)...)
-		case "italic":
-			textRunes = append(textRunes[:entity.Offset+entity.Length], append(utf16.Encode([]rune("</i>")), textRunes[entity.Offset+entity.Length:]...)...)
-			textRunes = append(textRunes[:entity.Offset], append(utf16.Encode([]rune("<i>")), textRunes[entity.Offset:]...)...)
-		case "code":
-			textRunes = append(textRunes[:entity.Offset+entity.Length], append(utf16.Encode([]rune("</code>")), textRunes[entity.Offset+entity.Length:]...)...)
-			textRunes = append(textRunes[:entity.Offset], append(utf16.Encode([]rune("<code>")), textRunes[entity.Offset:]...)...)
-		case "text_link":
-			textRunes = append(textRunes[:entity.Offset+entity.Length], append(utf16.Encode([]rune("</a>")), textRunes[entity.Offset+entity.Length:]...)...)
-			textRunes = append(textRunes[:entity.Offset], append(utf16.Encode([]rune(fmt.Sprintf("<a href='%s'>", entity.URL))), textRunes[entity.Offset:]...)...)
+	for _, entity := range entities {
+		openTag, closeTag := entityTags(entity)
+		if openTag == "" {
+			continue
 		}
+		end := entity.Offset + entity.Length
+		opening[entity.Offset] = append(opening[entity.Offset], openTag)
+		closing[end] = append([]string{closeTag}, closing[end]...)
 	}
 
-	return string(utf16.Decode(textRunes))
+	var result strings.Builder
+	for i := 0; i <= len(textRunes); i++ {
+		for _, tag := range closing[i] {
+			result.WriteString(tag)
+		}
+		for _, tag := range opening[i] {
+			result.WriteString(tag)
+		}
+		if i == len(textRunes) {
+			break
+		}
+
+		r := rune(textRunes[i])
+		if utf16.IsSurrogate(r) && i+1 < len(textRunes) {
+			r = utf16.DecodeRune(r, rune(textRunes[i+1]))
+			i++
+		}
+		result.WriteString(EscapeHTML(string(r)))
+	}
+
+	return result.String()
+}
+
+func entityTags(entity models.MessageEntity) (string, string) {
+	switch entity.Type {
+	case models.MessageEntityTypeBold:
+		return "<b>", "</b>"
+	case models.MessageEntityTypeItalic:
+		return "<i>", "</i>"
+	case models.MessageEntityTypeUnderline:
+		return "<u>", "</u>"
+	case models.MessageEntityTypeStrikethrough:
+		return "<s>", "</s>"
+	case models.MessageEntityTypeSpoiler:
+		return "<tg-spoiler>", "</tg-spoiler>"
+	case models.MessageEntityTypeCode:
+		return "<code>", "</code>"
+	case models.MessageEntityTypePre:
+		if entity.Language != "" {
+			return fmt.Sprintf("<pre><code class='language-%s'>", EscapeHTML(entity.Language)), "</code></pre>"
+		}
+		return "<pre>", "</pre>"
+	case models.MessageEntityTypeBlockquote:
+		return "<blockquote>", "</blockquote>"
+	case models.MessageEntityTypeExpandableBlockquote:
+		return "<blockquote expandable>", "</blockquote>"
+	case models.MessageEntityTypeTextLink:
+		return fmt.Sprintf("<a href='%s'>", strings.ReplaceAll(EscapeHTML(entity.URL), "'", "&#39;")), "</a>"
+	case models.MessageEntityTypeTextMention:
+		if entity.User != nil {
+			return fmt.Sprintf("<a href='tg://user?id=%d'>", entity.User.ID), "</a>"
+		}
+	}
+	return "", ""
 }
 
 func RandomString(n int) string {
@@ -63,3 +124,54 @@ func SanitizeString(input string) string {
 
 	return result
 }
+
+func MessageLink(chatID int64, username string, messageID int) string {
+	if username != "" {
+		return fmt.Sprintf("https://t.me/%s/%d", username, messageID)
+	}
+	return fmt.Sprintf("https://t.me/c/%s/%d", strings.TrimPrefix(strconv.FormatInt(chatID, 10), "-100"), messageID)
+}
+
+// ParseSwitch reads an on/off argument, reporting ok as false when it's
+// neither.
+func ParseSwitch(arg string) (enabled, ok bool) {
+	switch strings.ToLower(arg) {
+	case "on", "yes", "true":
+		return true, true
+	case "off", "no", "false":
+		return false, true
+	}
+	return false, false
+}
+
+// SplitArgs splits text into whitespace-separated arguments, keeping
+// "double quoted" sections together as a single argument.
+func SplitArgs(text string) []string {
+	var (
+		args    []string
+		current strings.Builder
+		quoted  bool
+		hasArg  bool
+	)
+
+	for _, r := range text {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			hasArg = true
+		case !quoted && unicode.IsSpace(r):
+			if hasArg {
+				args = append(args, current.String())
+				current.Reset()
+				hasArg = false
+			}
+		default:
+			current.WriteRune(r)
+			hasArg = true
+		}
+	}
+	if hasArg {
+		args = append(args, current.String())
+	}
+	return args
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+type threadKey struct{}
+
+type thread struct {
+	chatID int64
+	id     int
+}
+
+// MessageThread returns the forum topic message was sent in, or 0 outside
+// topics and in the General one.
+func MessageThread(message *models.Message) int {
+	if message == nil || !message.IsTopicMessage {
+		return 0
+	}
+	return message.MessageThreadID
+}
+
+// WithThread makes the send helpers post to threadID when sending to chatID
+// with the returned context.
+func WithThread(ctx context.Context, chatID int64, threadID int) context.Context {
+	if threadID == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, threadKey{}, thread{chatID: chatID, id: threadID})
+}
+
+// ChatThread returns the topic messages sent to chatID belong in, which is
+// the one the update being handled came from.
+func ChatThread(ctx context.Context, chatID int64) int {
+	if t, ok := ctx.Value(threadKey{}).(thread); ok && t.chatID == chatID {
+		return t.id
+	}
+	return 0
+}
+
+// ThreadMiddleware keeps the replies to an update in the forum topic it came
+// from.
+func ThreadMiddleware(next bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		message := update.Message
+		if message == nil && update.CallbackQuery != nil {
+			message = update.CallbackQuery.Message.Message
+		}
+
+		if threadID := MessageThread(message); threadID != 0 {
+			ctx = WithThread(ctx, message.Chat.ID, threadID)
+
+			// Messages that reply to nothing in a topic come as replies to
+			// the message that created it.
+			if reply := message.ReplyToMessage; reply != nil && reply.ID == threadID && update.Message != nil {
+				message.ReplyToMessage = nil
+			}
+		}
+
+		next(ctx, b, update)
+	}
+}